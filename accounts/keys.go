@@ -133,6 +133,40 @@ func (a *baseKey) Validate() error {
 	return nil
 }
 
+func (a *baseKey) withIndex(index uint32) *baseKey {
+	key := *a
+	key.index = index
+	return &key
+}
+
+// KeyWithIndex returns a copy of the key with the index of the key on the account changed.
+func KeyWithIndex(key Key, index uint32) (Key, error) {
+	switch k := key.(type) {
+	case *HexKey:
+		c := *k
+		c.baseKey = k.withIndex(index)
+		return &c, nil
+	case *FileKey:
+		c := *k
+		c.baseKey = k.withIndex(index)
+		return &c, nil
+	case *EnvKey:
+		c := *k
+		c.baseKey = k.withIndex(index)
+		return &c, nil
+	case *BIP44Key:
+		c := *k
+		c.baseKey = k.withIndex(index)
+		return &c, nil
+	case *KMSKey:
+		c := *k
+		c.baseKey = k.withIndex(index)
+		return &c, nil
+	}
+
+	return nil, fmt.Errorf(`invalid key type: "%s"`, key.Type())
+}
+
 // KMSKey implements Gcloud KMS system for signing.
 type KMSKey struct {
 	*baseKey
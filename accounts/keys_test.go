@@ -117,3 +117,24 @@ func Test_EnvKey(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, pubKey, sig.PublicKey().String())
 }
+
+func Test_KeyWithIndex(t *testing.T) {
+	rw, _ := tests.ReaderWriter()
+	fileKey := NewFileKey("./test.pkey", 0, config.DefaultSigAlgo, config.DefaultHashAlgo, rw)
+
+	key, err := KeyWithIndex(fileKey, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), key.Index())
+	assert.Equal(t, uint32(0), fileKey.Index())
+	assert.Equal(t, fileKey.ToConfig().Location, key.ToConfig().Location)
+	assert.Equal(t, rw, key.(*FileKey).rw)
+
+	pkey, err := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("seedseedseedseedseedseedseedseedseedseedseedseed"))
+	assert.NoError(t, err)
+	hexKey := NewHexKeyFromPrivateKey(1, crypto.SHA3_256, pkey)
+
+	key, err = KeyWithIndex(hexKey, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), key.Index())
+	assert.Equal(t, hexKey.ToConfig().PrivateKey, key.ToConfig().PrivateKey)
+}
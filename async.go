@@ -0,0 +1,162 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flowkit/v2/transactions"
+)
+
+// TransactionHandle is a transaction sent to the network without waiting for it, the handle is used to wait
+// for the transaction to reach a status or to receive its sealed result.
+type TransactionHandle struct {
+	ID          flow.Identifier
+	Transaction *flow.Transaction
+
+	flowkit *Flowkit
+	// ctx is the context the transaction was sent with, it's used to track the transaction for the result channel
+	ctx    context.Context
+	once   sync.Once
+	result chan TransactionHandleResult
+	// keyPool and proposalKey are set if the transaction is proposed with a key released to the pool once sent
+	keyPool     *ProposalKeyPool
+	proposalKey *ProposalKey
+}
+
+// TransactionHandleResult is the sealed result of a transaction sent asynchronously, or the error waiting for it.
+type TransactionHandleResult struct {
+	ID     flow.Identifier
+	Result *flow.TransactionResult
+	Err    error
+}
+
+func newTransactionHandle(ctx context.Context, f *Flowkit, tx *flow.Transaction) *TransactionHandle {
+	return &TransactionHandle{
+		ID:          tx.ID(),
+		Transaction: tx,
+		flowkit:     f,
+		ctx:         ctx,
+		result:      make(chan TransactionHandleResult, 1),
+	}
+}
+
+// Wait waits until the transaction reaches the status and returns the transaction result at the status.
+//
+// If the transaction was proposed with a pooled key and it expires or fails on the sequence number, the key's
+// sequence number is fetched from the network the next time it's leased.
+func (h *TransactionHandle) Wait(ctx context.Context, status flow.TransactionStatus) (*flow.TransactionResult, error) {
+	result, err := h.flowkit.TrackTransaction(ctx, h.ID, status, nil)
+	h.checkProposalKey(result, err)
+	return result, err
+}
+
+// checkProposalKey invalidates the pooled proposal key if the transaction didn't use its sequence number.
+func (h *TransactionHandle) checkProposalKey(result *flow.TransactionResult, err error) {
+	if h.proposalKey == nil {
+		return
+	}
+
+	expired := result != nil && result.Status == flow.TransactionStatusExpired
+	if expired || isSequenceNumberError(err) || (result != nil && isSequenceNumberError(result.Error)) {
+		h.keyPool.invalidateReleased(h.proposalKey)
+	}
+}
+
+// Result returns the channel receiving the sealed transaction result once, after which the channel is closed.
+//
+// The transaction is tracked in the background from the first call, using the context the transaction was sent with.
+func (h *TransactionHandle) Result() <-chan TransactionHandleResult {
+	h.once.Do(func() {
+		go func() {
+			res, err := h.Wait(h.ctx, flow.TransactionStatusSealed)
+			h.result <- TransactionHandleResult{ID: h.ID, Result: res, Err: err}
+			close(h.result)
+		}()
+	})
+	return h.result
+}
+
+// WaitTransactions waits concurrently until all the transactions reach the status, and returns the results
+// in the order of the handles. A transaction failing to reach the status doesn't stop waiting for the others.
+func WaitTransactions(
+	ctx context.Context,
+	handles []*TransactionHandle,
+	status flow.TransactionStatus,
+) []TransactionHandleResult {
+	results := make([]TransactionHandleResult, len(handles))
+
+	var wg sync.WaitGroup
+	for i, handle := range handles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := handle.Wait(ctx, status)
+			results[i] = TransactionHandleResult{ID: handle.ID, Result: res, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// SendTransactionAsync builds and sends the transaction like SendTransaction, but returns right after the transaction
+// is sent with a handle to wait for it.
+//
+// If the transaction is proposed with a key leased from the proposal key pool, the key is released once the
+// transaction is sent, so the transactions proposed with the key are pipelined. The key is resynced with the network
+// if waiting on the handle shows the transaction expired or failed on the sequence number.
+func (f *Flowkit) SendTransactionAsync(
+	ctx context.Context,
+	accounts transactions.AccountRoles,
+	script Script,
+	gasLimit uint64,
+) (*TransactionHandle, error) {
+	sentTx, proposalKey, err := f.submitTransaction(ctx, accounts, script, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+	f.logger.StopProgress()
+
+	handle := newTransactionHandle(ctx, f, sentTx)
+	if proposalKey != nil {
+		f.keyPool.Release(proposalKey, true)
+		handle.keyPool = f.keyPool
+		handle.proposalKey = proposalKey
+	}
+
+	return handle, nil
+}
+
+// SendSignedTransactionAsync sends the prebuilt and signed transaction like SendSignedTransaction, but returns
+// right after the transaction is sent with a handle to wait for it.
+func (f *Flowkit) SendSignedTransactionAsync(
+	ctx context.Context,
+	tx *transactions.Transaction,
+) (*TransactionHandle, error) {
+	sentTx, err := f.gateway.SendSignedTransaction(ctx, tx.FlowTransaction())
+	if err != nil {
+		return nil, err
+	}
+
+	return newTransactionHandle(ctx, f, sentTx), nil
+}
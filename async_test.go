@@ -0,0 +1,192 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+	"github.com/onflow/flowkit/v2/output"
+	"github.com/onflow/flowkit/v2/tests"
+	"github.com/onflow/flowkit/v2/transactions"
+)
+
+func TestTransactionHandles(t *testing.T) {
+	interval := transactionPollInterval
+	transactionPollInterval = time.Millisecond
+	t.Cleanup(func() { transactionPollInterval = interval })
+
+	ctx := context.Background()
+
+	setupAsync := func(t *testing.T) (Flowkit, *mocks.Gateway) {
+		g := mocks.NewGateway(t)
+		return Flowkit{
+			network: config.TestnetNetwork,
+			gateway: g,
+			logger:  output.NewStdoutLogger(output.NoneLog),
+		}, g
+	}
+
+	newTransaction := func(payer string) *transactions.Transaction {
+		return transactions.New().SetPayer(flow.HexToAddress(payer))
+	}
+
+	result := func(tx *flow.Transaction, status flow.TransactionStatus) *flow.TransactionResult {
+		return &flow.TransactionResult{TransactionID: tx.ID(), Status: status}
+	}
+
+	t.Run("Wait for status", func(t *testing.T) {
+		flowkit, g := setupAsync(t)
+		tx := newTransaction("01")
+		g.On("SendSignedTransaction", ctx, tx.FlowTransaction()).Return(tx.FlowTransaction(), nil).Once()
+
+		handle, err := flowkit.SendSignedTransactionAsync(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, tx.FlowTransaction().ID(), handle.ID)
+
+		g.On("GetTransactionResult", ctx, handle.ID, false).Return(result(tx.FlowTransaction(), flow.TransactionStatusPending), nil).Once()
+		g.On("GetTransactionResult", ctx, handle.ID, false).Return(result(tx.FlowTransaction(), flow.TransactionStatusFinalized), nil).Once()
+
+		res, err := handle.Wait(ctx, flow.TransactionStatusFinalized)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusFinalized, res.Status)
+	})
+
+	t.Run("Receive sealed result", func(t *testing.T) {
+		flowkit, g := setupAsync(t)
+		tx := newTransaction("01")
+		g.On("SendSignedTransaction", ctx, tx.FlowTransaction()).Return(tx.FlowTransaction(), nil).Once()
+		g.On("GetTransactionResult", ctx, tx.FlowTransaction().ID(), false).Return(result(tx.FlowTransaction(), flow.TransactionStatusExecuted), nil).Once()
+		g.On("GetTransactionResult", ctx, tx.FlowTransaction().ID(), false).Return(result(tx.FlowTransaction(), flow.TransactionStatusSealed), nil).Once()
+
+		handle, err := flowkit.SendSignedTransactionAsync(ctx, tx)
+		require.NoError(t, err)
+
+		// the result is only tracked once however many times the channel is requested
+		res := <-handle.Result()
+		require.NoError(t, res.Err)
+		assert.Equal(t, handle.ID, res.ID)
+		assert.Equal(t, flow.TransactionStatusSealed, res.Result.Status)
+
+		_, ok := <-handle.Result()
+		assert.False(t, ok)
+	})
+
+	t.Run("Wait for many transactions", func(t *testing.T) {
+		flowkit, g := setupAsync(t)
+		failure := errors.New("transaction not found")
+
+		var handles []*TransactionHandle
+		for i, payer := range []string{"01", "02", "03"} {
+			tx := newTransaction(payer)
+			g.On("SendSignedTransaction", ctx, tx.FlowTransaction()).Return(tx.FlowTransaction(), nil).Once()
+			if i == 1 {
+				g.On("GetTransactionResult", ctx, tx.FlowTransaction().ID(), false).Return(nil, failure).Once()
+			} else {
+				g.On("GetTransactionResult", ctx, tx.FlowTransaction().ID(), false).Return(result(tx.FlowTransaction(), flow.TransactionStatusSealed), nil).Once()
+			}
+
+			handle, err := flowkit.SendSignedTransactionAsync(ctx, tx)
+			require.NoError(t, err)
+			handles = append(handles, handle)
+		}
+
+		results := WaitTransactions(ctx, handles, flow.TransactionStatusSealed)
+		require.Len(t, results, 3)
+		for i, res := range results {
+			assert.Equal(t, handles[i].ID, res.ID)
+		}
+		assert.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[1].Err, failure)
+		assert.Equal(t, flow.TransactionStatusSealed, results[2].Result.Status)
+	})
+
+	t.Run("Resync pooled key of expired transaction", func(t *testing.T) {
+		flowkit, g := setupAsync(t)
+		address := flow.HexToAddress("01")
+		g.On("GetAccount", ctx, address).Return(&flow.Account{
+			Address: address,
+			Keys:    []*flow.AccountKey{{Index: 0, SequenceNumber: 3}},
+		}, nil).Once()
+		pool, err := NewProposalKeyPool(ctx, g, &accounts.Account{Name: "Alice", Address: address}, []uint32{0})
+		require.NoError(t, err)
+
+		// the transaction was sent with the leased key, which was released right after
+		key, err := pool.Lease(ctx)
+		require.NoError(t, err)
+		pool.Release(key, true)
+
+		tx := newTransaction("01")
+		handle := newTransactionHandle(ctx, &flowkit, tx.FlowTransaction())
+		handle.keyPool = pool
+		handle.proposalKey = key
+
+		g.On("GetTransactionResult", ctx, handle.ID, false).Return(result(tx.FlowTransaction(), flow.TransactionStatusExpired), nil).Once()
+		_, err = handle.Wait(ctx, flow.TransactionStatusSealed)
+		assert.ErrorContains(t, err, "expired")
+
+		g.On("GetAccount", ctx, address).Return(&flow.Account{
+			Address: address,
+			Keys:    []*flow.AccountKey{{Index: 0, SequenceNumber: 3}},
+		}, nil).Once()
+		key, err = pool.Lease(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), key.SequenceNumber)
+	})
+
+	t.Run("Fail to send", func(t *testing.T) {
+		flowkit, g := setupAsync(t)
+		tx := newTransaction("01")
+		failure := errors.New("invalid transaction")
+		g.On("SendSignedTransaction", ctx, tx.FlowTransaction()).Return(nil, failure).Once()
+
+		_, err := flowkit.SendSignedTransactionAsync(ctx, tx)
+		assert.ErrorIs(t, err, failure)
+	})
+
+	t.Run("Send transaction", func(t *testing.T) {
+		_, flowkit, gw := setup()
+		serviceAcc, _ := flowkit.state.EmulatorServiceAccount()
+		gw.SendSignedTransaction.Run(func(args mock.Arguments) {
+			gw.SendSignedTransaction.Return(args.Get(1).(*flow.Transaction), nil)
+		})
+
+		handle, err := flowkit.SendTransactionAsync(
+			ctx,
+			transactions.SingleAccountRole(*serviceAcc),
+			Script{Code: tests.TransactionSimple.Source},
+			flow.DefaultTransactionGasLimit,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, tests.TransactionSimple.Source, handle.Transaction.Script)
+
+		// nothing waits for the transaction until requested
+		gw.Mock.AssertNotCalled(t, mocks.GetTransactionResultFunc)
+	})
+}
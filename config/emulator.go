@@ -18,6 +18,8 @@
 
 package config
 
+import "fmt"
+
 var (
 	DefaultEmulator = Emulator{
 		Name:           "default",
@@ -27,11 +29,35 @@ var (
 	DefaultEmulators = Emulators{DefaultEmulator}
 )
 
+// EmulatorMemoryStorage keeps the emulator state in memory, it's the default emulator storage.
+const EmulatorMemoryStorage = "memory"
+
 // Emulator defines the configuration for a Flow Emulator instance.
+//
+// The zero values of the chain settings keep the emulator defaults.
 type Emulator struct {
 	Name           string
 	Port           int
 	ServiceAccount string
+	// Storage is either the memory storage or a directory the emulator state is persisted to.
+	Storage string
+	// ChainID of the emulated chain, such as flow-emulator, flow-testnet or flow-mainnet.
+	ChainID         string
+	TransactionFees bool
+	// StorageLimit limits the account storage by their balance, it's enabled unless set to false.
+	StorageLimit *bool
+	// MinimumAccountBalance is the FLOW amount reserved for the storage of each account.
+	MinimumAccountBalance string
+	ScriptGasLimit        uint64
+	ContractRemoval       bool
+}
+
+// PersistentStorage returns the directory the emulator state is persisted to, or an empty string if it's kept in memory.
+func (e Emulator) PersistentStorage() string {
+	if e.Storage == EmulatorMemoryStorage {
+		return ""
+	}
+	return e.Storage
 }
 
 type Emulators []Emulator
@@ -47,6 +73,17 @@ func (e *Emulators) Default() *Emulator {
 	return nil
 }
 
+// ByName get emulator by name or return an error if not found.
+func (e *Emulators) ByName(name string) (*Emulator, error) {
+	for _, em := range *e {
+		if em.Name == name {
+			return &em, nil
+		}
+	}
+
+	return nil, fmt.Errorf("emulator named %s does not exist in configuration", name)
+}
+
 // AddOrUpdate add new or update if already present.
 func (e *Emulators) AddOrUpdate(name string, emulator Emulator) {
 	for i, existingEmulator := range *e {
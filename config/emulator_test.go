@@ -73,3 +73,25 @@ func TestEmulatorsAddOrUpdate(t *testing.T) {
 		Port: 2345,
 	})
 }
+
+func TestEmulatorsByName(t *testing.T) {
+	emulators := Emulators{DefaultEmulator, {
+		Name:    "persistent",
+		Port:    3570,
+		Storage: "./.flowdb",
+	}}
+
+	emulator, err := emulators.ByName("persistent")
+	assert.NoError(t, err)
+	assert.Equal(t, 3570, emulator.Port)
+	assert.Equal(t, "./.flowdb", emulator.PersistentStorage())
+
+	_, err = emulators.ByName("missing")
+	assert.EqualError(t, err, "emulator named missing does not exist in configuration")
+}
+
+func TestEmulatorPersistentStorage(t *testing.T) {
+	assert.Empty(t, Emulator{}.PersistentStorage())
+	assert.Empty(t, Emulator{Storage: EmulatorMemoryStorage}.PersistentStorage())
+	assert.Equal(t, "/tmp/flowdb", Emulator{Storage: "/tmp/flowdb"}.PersistentStorage())
+}
@@ -20,6 +20,10 @@ package json
 
 import (
 	"fmt"
+	"slices"
+
+	"github.com/onflow/cadence"
+	flowGo "github.com/onflow/flow-go/model/flow"
 
 	"github.com/onflow/flowkit/v2/config"
 )
@@ -35,10 +39,27 @@ func (j jsonEmulators) transformToConfig() (config.Emulators, error) {
 			return nil, fmt.Errorf("invalid port value")
 		}
 
+		if e.ChainID != "" && !slices.Contains(flowGo.AllChainIDs(), flowGo.ChainID(e.ChainID)) {
+			return nil, fmt.Errorf("invalid chain ID %s for emulator %s", e.ChainID, name)
+		}
+
+		if e.MinimumAccountBalance != "" {
+			if _, err := cadence.NewUFix64(e.MinimumAccountBalance); err != nil {
+				return nil, fmt.Errorf("invalid minimum account balance for emulator %s: %w", name, err)
+			}
+		}
+
 		emulator := config.Emulator{
-			Name:           name,
-			Port:           e.Port,
-			ServiceAccount: e.ServiceAccount,
+			Name:                  name,
+			Port:                  e.Port,
+			ServiceAccount:        e.ServiceAccount,
+			Storage:               e.Storage,
+			ChainID:               e.ChainID,
+			TransactionFees:       e.TransactionFees,
+			StorageLimit:          e.StorageLimit,
+			MinimumAccountBalance: e.MinimumAccountBalance,
+			ScriptGasLimit:        e.ScriptGasLimit,
+			ContractRemoval:       e.ContractRemoval,
 		}
 
 		emulators = append(emulators, emulator)
@@ -56,8 +77,15 @@ func transformEmulatorsToJSON(emulators config.Emulators) jsonEmulators {
 			continue
 		}
 		jsonEmulators[e.Name] = jsonEmulator{
-			Port:           e.Port,
-			ServiceAccount: e.ServiceAccount,
+			Port:                  e.Port,
+			ServiceAccount:        e.ServiceAccount,
+			Storage:               e.Storage,
+			ChainID:               e.ChainID,
+			TransactionFees:       e.TransactionFees,
+			StorageLimit:          e.StorageLimit,
+			MinimumAccountBalance: e.MinimumAccountBalance,
+			ScriptGasLimit:        e.ScriptGasLimit,
+			ContractRemoval:       e.ContractRemoval,
 		}
 	}
 
@@ -65,6 +93,13 @@ func transformEmulatorsToJSON(emulators config.Emulators) jsonEmulators {
 }
 
 type jsonEmulator struct {
-	Port           int    `json:"port"`
-	ServiceAccount string `json:"serviceAccount"`
+	Port                  int    `json:"port"`
+	ServiceAccount        string `json:"serviceAccount"`
+	Storage               string `json:"storage,omitempty"`
+	ChainID               string `json:"chainId,omitempty"`
+	TransactionFees       bool   `json:"transactionFees,omitempty"`
+	StorageLimit          *bool  `json:"storageLimit,omitempty"`
+	MinimumAccountBalance string `json:"minimumAccountBalance,omitempty"`
+	ScriptGasLimit        uint64 `json:"scriptGasLimit,omitempty"`
+	ContractRemoval       bool   `json:"contractRemoval,omitempty"`
 }
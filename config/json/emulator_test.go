@@ -74,3 +74,67 @@ func Test_ConfigMultipleEmulators(t *testing.T) {
 	assert.Equal(t, emulators[1].Port, 3000)
 	assert.Equal(t, emulators[1].ServiceAccount, "custom-emulator-account")
 }
+
+func Test_ConfigEmulatorSettings(t *testing.T) {
+	b := []byte(`{
+		 "default": {
+				"port": 3569,
+				"serviceAccount": "emulator-account",
+				"storage": "./.flowdb",
+				"chainId": "flow-testnet",
+				"transactionFees": true,
+				"storageLimit": false,
+				"minimumAccountBalance": "0.01",
+				"scriptGasLimit": 200000,
+				"contractRemoval": true
+		 }
+	 }`)
+
+	var jsonEmulators jsonEmulators
+	err := json.Unmarshal(b, &jsonEmulators)
+	assert.NoError(t, err)
+
+	emulators, err := jsonEmulators.transformToConfig()
+	assert.NoError(t, err)
+
+	emulator := emulators[0]
+	assert.Equal(t, "./.flowdb", emulator.Storage)
+	assert.Equal(t, "./.flowdb", emulator.PersistentStorage())
+	assert.Equal(t, "flow-testnet", emulator.ChainID)
+	assert.True(t, emulator.TransactionFees)
+	assert.NotNil(t, emulator.StorageLimit)
+	assert.False(t, *emulator.StorageLimit)
+	assert.Equal(t, "0.01", emulator.MinimumAccountBalance)
+	assert.Equal(t, uint64(200000), emulator.ScriptGasLimit)
+	assert.True(t, emulator.ContractRemoval)
+
+	out, err := json.Marshal(transformEmulatorsToJSON(emulators))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"default": {
+			"port": 3569,
+			"serviceAccount": "emulator-account",
+			"storage": "./.flowdb",
+			"chainId": "flow-testnet",
+			"transactionFees": true,
+			"storageLimit": false,
+			"minimumAccountBalance": "0.01",
+			"scriptGasLimit": 200000,
+			"contractRemoval": true
+		}
+	}`, string(out))
+}
+
+func Test_ConfigEmulatorInvalidSettings(t *testing.T) {
+	t.Run("Invalid chain ID", func(t *testing.T) {
+		jsonEmulators := jsonEmulators{"default": {Port: 3569, ServiceAccount: "emulator-account", ChainID: "flow-foo"}}
+		_, err := jsonEmulators.transformToConfig()
+		assert.EqualError(t, err, "invalid chain ID flow-foo for emulator default")
+	})
+
+	t.Run("Invalid minimum account balance", func(t *testing.T) {
+		jsonEmulators := jsonEmulators{"default": {Port: 3569, ServiceAccount: "emulator-account", MinimumAccountBalance: "-1"}}
+		_, err := jsonEmulators.transformToConfig()
+		assert.ErrorContains(t, err, "invalid minimum account balance for emulator default")
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/invopop/jsonschema"
 	"github.com/onflow/flow-go-sdk/crypto"
@@ -38,18 +39,43 @@ func (j jsonNetworks) transformToConfig() (config.Networks, error) {
 
 	for networkName, n := range j {
 		// Advanced form: host required, key optional, fork optional
-		if n.Advanced.Host != "" || n.Advanced.Fork != "" {
+		if n.Advanced.Host != "" || n.Advanced.Fork != "" || len(n.Advanced.Hosts) > 0 {
+			if n.Advanced.Host == "" && len(n.Advanced.Hosts) > 0 {
+				return nil, fmt.Errorf("primary host is required when additional hosts are defined for network with name %s", networkName)
+			}
+			for _, host := range n.Advanced.Hosts {
+				if host == "" {
+					return nil, fmt.Errorf("empty host defined for network with name %s", networkName)
+				}
+			}
+			// the key identifies a single access node so it can't be shared by the failover hosts
+			if n.Advanced.Key != "" && len(n.Advanced.Hosts) > 0 {
+				return nil, fmt.Errorf("key can't be defined together with additional hosts for network with name %s", networkName)
+			}
 			if n.Advanced.Key != "" {
 				if err := validateECDSAP256Pub(n.Advanced.Key); err != nil {
 					return nil, fmt.Errorf("invalid key %s for network with name %s", n.Advanced.Key, networkName)
 				}
 			}
-			networks = append(networks, config.Network{
-				Name: networkName,
-				Host: n.Advanced.Host,
-				Key:  n.Advanced.Key,
-				Fork: n.Advanced.Fork,
-			})
+			retry, err := n.Advanced.Retry.transformToConfig()
+			if err != nil {
+				return nil, fmt.Errorf("invalid retry configuration for network with name %s: %w", networkName, err)
+			}
+			network := config.Network{
+				Name:           networkName,
+				Host:           n.Advanced.Host,
+				Hosts:          n.Advanced.Hosts,
+				Key:            n.Advanced.Key,
+				Fork:           n.Advanced.Fork,
+				Retry:          retry,
+				TLS:            n.Advanced.TLS.transformToConfig(),
+				Headers:        n.Advanced.Headers,
+				MaxMessageSize: n.Advanced.MaxMessageSize,
+			}
+			if err := n.Advanced.transformConnectionToConfig(&network); err != nil {
+				return nil, fmt.Errorf("invalid connection configuration for network with name %s: %w", networkName, err)
+			}
+			networks = append(networks, network)
 		} else if n.Simple.Host != "" {
 			networks = append(networks, config.Network{
 				Name: networkName,
@@ -68,8 +94,8 @@ func transformNetworksToJSON(networks config.Networks) jsonNetworks {
 	jsonNetworks := jsonNetworks{}
 
 	for _, n := range networks {
-		// Use advanced when key or fork present; otherwise simple
-		if n.Key != "" || n.Fork != "" {
+		// Use advanced when key, fork, additional hosts, retry or connection settings present; otherwise simple
+		if n.Key != "" || n.Fork != "" || len(n.Hosts) > 0 || !n.Retry.IsEmpty() || n.HasConnectionSettings() {
 			jsonNetworks[n.Name] = transformAdvancedNetworkToJSON(n)
 		} else {
 			jsonNetworks[n.Name] = transformSimpleNetworkToJSON(n)
@@ -90,13 +116,72 @@ func transformSimpleNetworkToJSON(n config.Network) jsonNetwork {
 func transformAdvancedNetworkToJSON(n config.Network) jsonNetwork {
 	return jsonNetwork{
 		Advanced: advancedNetwork{
-			Host: n.Host,
-			Key:  n.Key,
-			Fork: n.Fork,
+			Host:           n.Host,
+			Hosts:          n.Hosts,
+			Key:            n.Key,
+			Fork:           n.Fork,
+			Retry:          transformNetworkRetryToJSON(n.Retry),
+			TLS:            transformNetworkTLSToJSON(n.TLS),
+			Headers:        n.Headers,
+			Timeout:        formatDuration(n.Timeout),
+			Keepalive:      transformNetworkKeepaliveToJSON(n.Keepalive),
+			MaxMessageSize: n.MaxMessageSize,
 		},
 	}
 }
 
+func transformNetworkTLSToJSON(t config.NetworkTLS) *jsonNetworkTLS {
+	if t.IsEmpty() {
+		return nil
+	}
+
+	return &jsonNetworkTLS{
+		Enabled:    t.Enabled,
+		CAFile:     t.CAFile,
+		ServerName: t.ServerName,
+	}
+}
+
+func transformNetworkKeepaliveToJSON(k config.NetworkKeepalive) *jsonNetworkKeepalive {
+	if k.IsEmpty() {
+		return nil
+	}
+
+	return &jsonNetworkKeepalive{
+		Time:                formatDuration(k.Time),
+		Timeout:             formatDuration(k.Timeout),
+		PermitWithoutStream: k.PermitWithoutStream,
+	}
+}
+
+// formatDuration formats the duration in the Go format, leaving out unset durations.
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func transformNetworkRetryToJSON(r config.NetworkRetry) *jsonNetworkRetry {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	retry := &jsonNetworkRetry{
+		MaxAttempts: r.MaxAttempts,
+		Multiplier:  r.Multiplier,
+		Jitter:      r.Jitter,
+	}
+	if r.InitialBackoff != 0 {
+		retry.InitialBackoff = r.InitialBackoff.String()
+	}
+	if r.MaxBackoff != 0 {
+		retry.MaxBackoff = r.MaxBackoff.String()
+	}
+
+	return retry
+}
+
 type jsonNetwork struct {
 	Simple   simpleNetwork
 	Advanced advancedNetwork
@@ -107,9 +192,149 @@ type simpleNetwork struct {
 }
 
 type advancedNetwork struct {
-	Host string `json:"host,omitempty"`
-	Key  string `json:"key,omitempty"`
-	Fork string `json:"fork,omitempty"`
+	Host           string                `json:"host,omitempty"`
+	Hosts          []string              `json:"hosts,omitempty"`
+	Key            string                `json:"key,omitempty"`
+	Fork           string                `json:"fork,omitempty"`
+	Retry          *jsonNetworkRetry     `json:"retry,omitempty"`
+	TLS            *jsonNetworkTLS       `json:"tls,omitempty"`
+	Headers        map[string]string     `json:"headers,omitempty"`
+	Timeout        string                `json:"timeout,omitempty"`
+	Keepalive      *jsonNetworkKeepalive `json:"keepalive,omitempty"`
+	MaxMessageSize int                   `json:"maxMessageSize,omitempty"`
+}
+
+// transformConnectionToConfig validates the connection settings and sets the ones that need parsing on the network.
+func (a advancedNetwork) transformConnectionToConfig(network *config.Network) error {
+	if a.TLS != nil && !a.TLS.Enabled && (a.TLS.CAFile != "" || a.TLS.ServerName != "") {
+		return fmt.Errorf("TLS must be enabled to use a CA file or server name")
+	}
+	if a.TLS != nil && a.TLS.Enabled && a.Key != "" {
+		return fmt.Errorf("TLS can not be enabled for a network with a node key")
+	}
+	for name := range a.Headers {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("header name can not be empty")
+		}
+	}
+	if a.MaxMessageSize < 0 {
+		return fmt.Errorf("max message size can not be negative")
+	}
+
+	var err error
+	network.Timeout, err = parseDuration(a.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	}
+
+	if a.Keepalive != nil {
+		network.Keepalive.PermitWithoutStream = a.Keepalive.PermitWithoutStream
+		network.Keepalive.Time, err = parseDuration(a.Keepalive.Time)
+		if err != nil {
+			return fmt.Errorf("invalid keepalive time: %w", err)
+		}
+		network.Keepalive.Timeout, err = parseDuration(a.Keepalive.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid keepalive timeout: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// parseDuration parses a positive duration in the Go format, an empty value is parsed as zero.
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %s must be positive", value)
+	}
+
+	return d, nil
+}
+
+// jsonNetworkTLS defines the TLS settings of the network connection,
+// the CA file is a PEM encoded CA bundle used instead of the system certificates.
+type jsonNetworkTLS struct {
+	Enabled    bool   `json:"enabled"`
+	CAFile     string `json:"caFile,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// transformToConfig transforms json structures to config structure.
+func (t *jsonNetworkTLS) transformToConfig() config.NetworkTLS {
+	if t == nil {
+		return config.NetworkTLS{}
+	}
+
+	return config.NetworkTLS{
+		Enabled:    t.Enabled,
+		CAFile:     t.CAFile,
+		ServerName: t.ServerName,
+	}
+}
+
+// jsonNetworkKeepalive defines the keepalive pings of the network connection,
+// durations are in the Go format (e.g. "30s").
+type jsonNetworkKeepalive struct {
+	Time                string `json:"time,omitempty"`
+	Timeout             string `json:"timeout,omitempty"`
+	PermitWithoutStream bool   `json:"permitWithoutStream,omitempty"`
+}
+
+// jsonNetworkRetry defines the retry policy for the network access API calls,
+// backoff values are durations in the Go format (e.g. "500ms", "2s").
+type jsonNetworkRetry struct {
+	MaxAttempts    int     `json:"maxAttempts,omitempty"`
+	InitialBackoff string  `json:"initialBackoff,omitempty"`
+	MaxBackoff     string  `json:"maxBackoff,omitempty"`
+	Multiplier     float64 `json:"multiplier,omitempty"`
+	Jitter         float64 `json:"jitter,omitempty"`
+}
+
+// transformToConfig transforms json structures to config structure.
+func (r *jsonNetworkRetry) transformToConfig() (config.NetworkRetry, error) {
+	if r == nil {
+		return config.NetworkRetry{}, nil
+	}
+
+	if r.MaxAttempts < 0 {
+		return config.NetworkRetry{}, fmt.Errorf("max attempts can not be negative")
+	}
+	if r.Multiplier != 0 && r.Multiplier < 1 {
+		return config.NetworkRetry{}, fmt.Errorf("multiplier must be at least 1")
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return config.NetworkRetry{}, fmt.Errorf("jitter must be between 0 and 1")
+	}
+
+	retry := config.NetworkRetry{
+		MaxAttempts: r.MaxAttempts,
+		Multiplier:  r.Multiplier,
+		Jitter:      r.Jitter,
+	}
+
+	var err error
+	if r.InitialBackoff != "" {
+		retry.InitialBackoff, err = time.ParseDuration(r.InitialBackoff)
+		if err != nil {
+			return config.NetworkRetry{}, fmt.Errorf("invalid initial backoff: %w", err)
+		}
+	}
+	if r.MaxBackoff != "" {
+		retry.MaxBackoff, err = time.ParseDuration(r.MaxBackoff)
+		if err != nil {
+			return config.NetworkRetry{}, fmt.Errorf("invalid max backoff: %w", err)
+		}
+	}
+
+	return retry, nil
 }
 
 func (j *jsonNetwork) UnmarshalJSON(b []byte) error {
@@ -124,9 +349,7 @@ func (j *jsonNetwork) UnmarshalJSON(b []byte) error {
 	var advanced advancedNetwork
 	err = json.Unmarshal(b, &advanced)
 	if err == nil {
-		j.Advanced.Host = advanced.Host
-		j.Advanced.Key = advanced.Key
-		j.Advanced.Fork = advanced.Fork
+		j.Advanced = advanced
 	}
 
 	return err
@@ -20,8 +20,11 @@ package json
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flowkit/v2/config"
 )
 
 func Test_ConfigNetworkSimple(t *testing.T) {
@@ -153,3 +156,147 @@ func Test_TransformConfigAdvanced(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func Test_ConfigNetworkRetry(t *testing.T) {
+	t.Run("should parse retry configuration", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access.testnet.nodes.onflow.org:9000","retry":{"maxAttempts":5,"initialBackoff":"100ms","maxBackoff":"2s","multiplier":1.5,"jitter":0.1}}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		networks, err := jsonNetworks.transformToConfig()
+		assert.NoError(t, err)
+
+		testnet, err := networks.ByName("testnet")
+		assert.NoError(t, err)
+		assert.Equal(t, config.NetworkRetry{
+			MaxAttempts:    5,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+			Multiplier:     1.5,
+			Jitter:         0.1,
+		}, testnet.Retry)
+
+		x, _ := json.Marshal(transformNetworksToJSON(networks))
+		assert.JSONEq(t, string(b), string(x))
+	})
+	t.Run("should return error for invalid retry configuration", func(t *testing.T) {
+		invalid := []string{
+			`{"maxAttempts":-1}`,
+			`{"initialBackoff":"soon"}`,
+			`{"multiplier":0.5}`,
+			`{"jitter":2}`,
+		}
+		for _, retry := range invalid {
+			b := []byte(`{"testnet":{"host":"access.testnet.nodes.onflow.org:9000","retry":` + retry + `}}`)
+			var jsonNetworks jsonNetworks
+			err := json.Unmarshal(b, &jsonNetworks)
+			assert.NoError(t, err)
+
+			_, err = jsonNetworks.transformToConfig()
+			assert.Error(t, err, retry)
+		}
+	})
+}
+
+func Test_ConfigNetworkHosts(t *testing.T) {
+	t.Run("should parse additional hosts", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access-1.testnet.nodes.onflow.org:9000","hosts":["access-2.testnet.nodes.onflow.org:9000","access-3.testnet.nodes.onflow.org:9000"]}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		networks, err := jsonNetworks.transformToConfig()
+		assert.NoError(t, err)
+
+		testnet, err := networks.ByName("testnet")
+		assert.NoError(t, err)
+		assert.Equal(t, "access-1.testnet.nodes.onflow.org:9000", testnet.Host)
+		assert.Equal(t, []string{
+			"access-1.testnet.nodes.onflow.org:9000",
+			"access-2.testnet.nodes.onflow.org:9000",
+			"access-3.testnet.nodes.onflow.org:9000",
+		}, testnet.AccessHosts())
+
+		x, _ := json.Marshal(transformNetworksToJSON(networks))
+		assert.Equal(t, string(b), string(x))
+	})
+	t.Run("should return error if additional hosts are defined without host", func(t *testing.T) {
+		b := []byte(`{"testnet":{"hosts":["access-2.testnet.nodes.onflow.org:9000"]}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		_, err = jsonNetworks.transformToConfig()
+		assert.Error(t, err)
+	})
+	t.Run("should return error for empty host", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access-1.testnet.nodes.onflow.org:9000","hosts":[""]}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		_, err = jsonNetworks.transformToConfig()
+		assert.Error(t, err)
+	})
+	t.Run("should return error if key is defined with additional hosts", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access-1.testnet.nodes.onflow.org:9000","hosts":["access-2.testnet.nodes.onflow.org:9000"],"key":"5000676131ad3e22d853a3f75a5b5d0db4236d08dd6612e2baad771014b5266a242bccecc3522ff7207ac357dbe4f225c709d9b273ac484fed5d13976a39bdcd"}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		_, err = jsonNetworks.transformToConfig()
+		assert.ErrorContains(t, err, "key can't be defined together with additional hosts")
+	})
+}
+
+func Test_ConfigNetworkConnection(t *testing.T) {
+	t.Run("should parse connection configuration", func(t *testing.T) {
+		b := []byte(`{"testnet":{"host":"access.testnet.nodes.onflow.org:9000","tls":{"enabled":true,"caFile":"./certs/ca.pem","serverName":"access.testnet.nodes.onflow.org"},"headers":{"x-api-key":"secret"},"timeout":"30s","keepalive":{"time":"1m0s","timeout":"10s","permitWithoutStream":true},"maxMessageSize":1048576}}`)
+		var jsonNetworks jsonNetworks
+		err := json.Unmarshal(b, &jsonNetworks)
+		assert.NoError(t, err)
+
+		networks, err := jsonNetworks.transformToConfig()
+		assert.NoError(t, err)
+
+		testnet, err := networks.ByName("testnet")
+		assert.NoError(t, err)
+		assert.Equal(t, config.NetworkTLS{
+			Enabled:    true,
+			CAFile:     "./certs/ca.pem",
+			ServerName: "access.testnet.nodes.onflow.org",
+		}, testnet.TLS)
+		assert.Equal(t, map[string]string{"x-api-key": "secret"}, testnet.Headers)
+		assert.Equal(t, 30*time.Second, testnet.Timeout)
+		assert.Equal(t, config.NetworkKeepalive{
+			Time:                time.Minute,
+			Timeout:             10 * time.Second,
+			PermitWithoutStream: true,
+		}, testnet.Keepalive)
+		assert.Equal(t, 1048576, testnet.MaxMessageSize)
+
+		x, _ := json.Marshal(transformNetworksToJSON(networks))
+		assert.JSONEq(t, string(b), string(x))
+	})
+	t.Run("should return error for invalid connection configuration", func(t *testing.T) {
+		invalid := []string{
+			`"tls":{"caFile":"./certs/ca.pem"}`,
+			`"tls":{"enabled":true},"key":"5000676131ad3e22d853a3f75a5b5d0db4236d08dd6612e2baad771014b5266a242bccecc3522ff7207ac357dbe4f225c709d9b273ac484fed5d13976a39bdcd"`,
+			`"headers":{"":"secret"}`,
+			`"timeout":"soon"`,
+			`"timeout":"-1s"`,
+			`"keepalive":{"time":"often"}`,
+			`"maxMessageSize":-1`,
+		}
+		for _, connection := range invalid {
+			b := []byte(`{"testnet":{"host":"access.testnet.nodes.onflow.org:9000",` + connection + `}}`)
+			var jsonNetworks jsonNetworks
+			err := json.Unmarshal(b, &jsonNetworks)
+			assert.NoError(t, err)
+
+			_, err = jsonNetworks.transformToConfig()
+			assert.Error(t, err, connection)
+		}
+	})
+}
@@ -20,9 +20,12 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"time"
 )
 
 var (
+	// Deprecated: Network is not comparable, use Network.IsEmpty instead of comparing a network to EmptyNetwork.
 	EmptyNetwork    = Network{}
 	EmulatorNetwork = Network{
 		Name: "emulator",
@@ -52,10 +55,98 @@ type Networks []Network
 
 // Network defines the configuration for a Flow network.
 type Network struct {
-	Name string
-	Host string
-	Key  string
-	Fork string // Source network for alias resolution (e.g., "mainnet" for forked networks)
+	Name           string
+	Host           string
+	Hosts          []string // Additional access node hosts used for failover
+	Key            string
+	Fork           string // Source network for alias resolution (e.g., "mainnet" for forked networks)
+	Retry          NetworkRetry
+	TLS            NetworkTLS
+	Headers        map[string]string // Metadata sent with every call, e.g. API keys of hosted access providers
+	Timeout        time.Duration     // Timeout of a single call, zero means no timeout
+	Keepalive      NetworkKeepalive
+	MaxMessageSize int // Maximum size of a received message in bytes, zero means the gateway default
+}
+
+// IsEmpty returns true if the network is not defined.
+//
+// Network is not comparable, so IsEmpty must be used instead of comparing a network to EmptyNetwork.
+func (n Network) IsEmpty() bool {
+	return n.Name == "" &&
+		n.Host == "" &&
+		len(n.Hosts) == 0 &&
+		n.Key == "" &&
+		n.Fork == "" &&
+		n.Retry.IsEmpty() &&
+		!n.HasConnectionSettings()
+}
+
+// AccessHosts returns all access node hosts of the network, starting with the primary host.
+func (n Network) AccessHosts() []string {
+	hosts := make([]string, 0, len(n.Hosts)+1)
+	if n.Host != "" {
+		hosts = append(hosts, n.Host)
+	}
+
+	for _, host := range n.Hosts {
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// NetworkRetry defines how failed access API calls to the network are retried.
+//
+// The zero value disables retrying, any unset field is replaced with a default value
+// when the retry policy is built by the gateway.
+type NetworkRetry struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+}
+
+// IsEmpty returns true if no retry settings are defined.
+func (r NetworkRetry) IsEmpty() bool {
+	return r == NetworkRetry{}
+}
+
+// HasConnectionSettings returns true if any of the connection settings besides the hosts and key are defined.
+func (n Network) HasConnectionSettings() bool {
+	return !n.TLS.IsEmpty() ||
+		len(n.Headers) > 0 ||
+		n.Timeout != 0 ||
+		!n.Keepalive.IsEmpty() ||
+		n.MaxMessageSize != 0
+}
+
+// NetworkTLS defines the TLS settings used to connect to the network.
+//
+// If enabled without a CA file the system certificate pool is used to verify the server.
+type NetworkTLS struct {
+	Enabled    bool
+	CAFile     string // Path to a PEM encoded CA bundle
+	ServerName string // Overrides the server name used to verify the certificate
+}
+
+// IsEmpty returns true if no TLS settings are defined.
+func (t NetworkTLS) IsEmpty() bool {
+	return t == NetworkTLS{}
+}
+
+// NetworkKeepalive defines the keepalive pings sent on idle connections to the network.
+type NetworkKeepalive struct {
+	Time                time.Duration // Interval of the pings sent when the connection is idle
+	Timeout             time.Duration // Time to wait for a ping acknowledgement before closing the connection
+	PermitWithoutStream bool          // Send pings even when there are no active streams
+}
+
+// IsEmpty returns true if no keepalive settings are defined.
+func (k NetworkKeepalive) IsEmpty() bool {
+	return k == NetworkKeepalive{}
 }
 
 // ByName get network by name or return an error if not found.
@@ -20,6 +20,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -77,3 +78,31 @@ func TestNetworks_Remove(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "network named flow-mainnet does not exist in configuration")
 }
+
+func TestNetwork_AccessHosts(t *testing.T) {
+	// Test primary host is first and duplicates are removed.
+	network := Network{Name: "flow-testnet", Host: "localhost:3570", Hosts: []string{"localhost:3571", "localhost:3570"}}
+	assert.Equal(t, []string{"localhost:3570", "localhost:3571"}, network.AccessHosts())
+
+	// Test network without hosts.
+	assert.Empty(t, Network{Name: "flow-testnet"}.AccessHosts())
+}
+
+func TestNetwork_IsEmpty(t *testing.T) {
+	assert.True(t, EmptyNetwork.IsEmpty())
+	assert.True(t, Network{Hosts: []string{}}.IsEmpty())
+
+	// Test any defined setting makes the network non-empty.
+	for _, network := range []Network{
+		{Name: "flow-testnet"},
+		{Host: "localhost:3570"},
+		{Hosts: []string{"localhost:3571"}},
+		{Key: "flow-testnet-key"},
+		{Fork: "mainnet"},
+		{Retry: NetworkRetry{MaxAttempts: 3}},
+		{Headers: map[string]string{"x-api-key": "key"}},
+		{Timeout: time.Second},
+	} {
+		assert.False(t, network.IsEmpty())
+	}
+}
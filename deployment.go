@@ -0,0 +1,712 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	flowGo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flowkit/v2/output"
+	"github.com/onflow/flowkit/v2/project"
+	"github.com/onflow/flowkit/v2/transactions"
+)
+
+// DeploymentAction is the action a deployment plan takes for a contract.
+type DeploymentAction string
+
+const (
+	// DeploymentAdd adds the contract that doesn't exist on the account yet.
+	DeploymentAdd DeploymentAction = "add"
+	// DeploymentUpdate updates the contract that exists on the account with different code.
+	DeploymentUpdate DeploymentAction = "update"
+	// DeploymentUnchanged skips the contract that exists on the account with the same code.
+	DeploymentUnchanged DeploymentAction = "unchanged"
+)
+
+// DeploymentPlan contains the contracts of the project in the order they are deployed to the network.
+//
+// The plan is created without sending any transactions, so it can be reviewed before it's executed.
+type DeploymentPlan struct {
+	Network   string             `json:"network"`
+	Contracts []*PlannedContract `json:"contracts"`
+}
+
+// PlannedContract is a contract deployment in the deployment plan.
+//
+// The level is the dependency level of the contract, contracts only depend on contracts in lower levels.
+// The code is the contract source with imports resolved to the addresses on the network, which is deployed as is.
+// The existing hash is the hash of the code found on the account when the plan was created and is empty if
+// the contract didn't exist, it's used to detect changes on the network before the plan is executed.
+type PlannedContract struct {
+	Name         string
+	Account      string
+	Address      flow.Address
+	Location     string
+	Level        int
+	Action       DeploymentAction
+	SourceHash   string
+	ExistingHash string
+	Args         []cadence.Value
+	Code         []byte
+}
+
+type plannedContractJSON struct {
+	Name         string            `json:"name"`
+	Account      string            `json:"account"`
+	Address      flow.Address      `json:"address"`
+	Location     string            `json:"location"`
+	Level        int               `json:"level"`
+	Action       DeploymentAction  `json:"action"`
+	SourceHash   string            `json:"sourceHash"`
+	ExistingHash string            `json:"existingHash,omitempty"`
+	Args         []json.RawMessage `json:"args"`
+	Code         string            `json:"code"`
+}
+
+func (c *PlannedContract) MarshalJSON() ([]byte, error) {
+	args := make([]json.RawMessage, len(c.Args))
+	for i, arg := range c.Args {
+		encoded, err := jsoncdc.Encode(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument %d of the contract %s: %w", i, c.Name, err)
+		}
+		args[i] = bytes.TrimSpace(encoded)
+	}
+
+	return json.Marshal(plannedContractJSON{
+		Name:         c.Name,
+		Account:      c.Account,
+		Address:      c.Address,
+		Location:     c.Location,
+		Level:        c.Level,
+		Action:       c.Action,
+		SourceHash:   c.SourceHash,
+		ExistingHash: c.ExistingHash,
+		Args:         args,
+		Code:         string(c.Code),
+	})
+}
+
+func (c *PlannedContract) UnmarshalJSON(data []byte) error {
+	var raw plannedContractJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var args []cadence.Value
+	if len(raw.Args) > 0 {
+		args = make([]cadence.Value, len(raw.Args))
+	}
+	for i, arg := range raw.Args {
+		value, err := jsoncdc.Decode(nil, arg)
+		if err != nil {
+			return fmt.Errorf("failed to decode argument %d of the contract %s: %w", i, raw.Name, err)
+		}
+		args[i] = value
+	}
+
+	*c = PlannedContract{
+		Name:         raw.Name,
+		Account:      raw.Account,
+		Address:      raw.Address,
+		Location:     raw.Location,
+		Level:        raw.Level,
+		Action:       raw.Action,
+		SourceHash:   raw.SourceHash,
+		ExistingHash: raw.ExistingHash,
+		Args:         args,
+		Code:         []byte(raw.Code),
+	}
+	return nil
+}
+
+// DeploymentDriftError is returned when the contracts on the network changed after the deployment plan was created.
+type DeploymentDriftError struct {
+	Contracts []string
+}
+
+func (e *DeploymentDriftError) Error() string {
+	return fmt.Sprintf(
+		"contracts changed on the network since the deployment plan was created: %s",
+		strings.Join(e.Contracts, ", "),
+	)
+}
+
+func codeHash(code []byte) string {
+	hash := sha256.Sum256(code)
+	return hex.EncodeToString(hash[:])
+}
+
+// PlanDeployment creates the plan for deploying the project contracts to the network without sending any transactions.
+//
+// Contracts are sorted in the deployment order and their imports are resolved the same way as in DeployProject.
+// Contracts that exist on the account with different code are planned for update only if UpdateContract returns true,
+// otherwise an error for the contract is returned as part of the ProjectDeploymentError.
+func (f *Flowkit) PlanDeployment(ctx context.Context, update UpdateContract) (*DeploymentPlan, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	plan, _, err := f.planDeployment(ctx, state, update)
+	return plan, err
+}
+
+// planDeployment creates the deployment plan and returns it with the planned project contracts in the same order.
+func (f *Flowkit) planDeployment(
+	ctx context.Context,
+	state *State,
+	update UpdateContract,
+) (*DeploymentPlan, []*project.Contract, error) {
+	contracts, err := state.DeploymentContractsByNetwork(f.network)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deployment, err := project.NewDeployment(contracts, state.AliasesForNetwork(f.network))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	levels, err := deployment.Levels()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan := &DeploymentPlan{
+		Network:   f.network.Name,
+		Contracts: make([]*PlannedContract, 0, len(contracts)),
+	}
+	sorted := make([]*project.Contract, 0, len(contracts))
+
+	onChain := make(map[flow.Address]*flow.Account)
+	planErr := &ProjectDeploymentError{}
+	for level, levelContracts := range levels {
+		for _, contract := range levelContracts {
+			planned, err := f.planContract(ctx, state, contract, onChain, update)
+			if err != nil {
+				planErr.add(contract.Name, err, fmt.Sprintf("failed to plan contract %s", contract.Name))
+				continue
+			}
+			planned.Level = level
+
+			plan.Contracts = append(plan.Contracts, planned)
+			sorted = append(sorted, contract)
+		}
+	}
+
+	if len(planErr.contracts) > 0 {
+		return nil, nil, planErr
+	}
+
+	return plan, sorted, nil
+}
+
+// planContract resolves the contract imports and compares the code with the contract on the account to decide
+// the deployment action. Accounts fetched from the network are cached in the provided map.
+func (f *Flowkit) planContract(
+	ctx context.Context,
+	state *State,
+	contract *project.Contract,
+	onChain map[flow.Address]*flow.Account,
+	update UpdateContract,
+) (*PlannedContract, error) {
+	program, err := f.resolveContract(state, Script{
+		Code:     contract.Code(),
+		Args:     contract.Args,
+		Location: contract.Location(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := program.Name()
+	if err != nil {
+		return nil, err
+	}
+
+	flowAccount, ok := onChain[contract.AccountAddress]
+	if !ok {
+		flowAccount, err = f.gateway.GetAccount(ctx, contract.AccountAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", contract.AccountAddress, err)
+		}
+		onChain[contract.AccountAddress] = flowAccount
+	}
+
+	planned := &PlannedContract{
+		Name:       name,
+		Account:    contract.AccountName,
+		Address:    contract.AccountAddress,
+		Location:   contract.Location(),
+		Action:     DeploymentAdd,
+		SourceHash: codeHash(program.Code()),
+		Args:       contract.Args,
+		Code:       program.Code(),
+	}
+
+	if existing, exists := flowAccount.Contracts[name]; exists {
+		planned.ExistingHash = codeHash(existing)
+
+		switch {
+		case bytes.Equal(existing, program.Code()):
+			planned.Action = DeploymentUnchanged
+		case update(existing, program.Code()):
+			planned.Action = DeploymentUpdate
+		default:
+			return nil, fmt.Errorf("contract %s exists in account %s", name, contract.AccountName)
+		}
+
+		if planned.Action == DeploymentUpdate && f.validateUpdates {
+			err := f.validateContractUpdate(ctx, contract.AccountAddress, name, existing, program.Code())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return planned, nil
+}
+
+// ExecuteDeploymentPlan deploys the contracts exactly as planned and returns the IDs of the sent transactions
+// by the contract name.
+//
+// Before sending any transaction the contracts on the network are compared to the state they were in when
+// the plan was created, and if any of them changed a DeploymentDriftError is returned.
+func (f *Flowkit) ExecuteDeploymentPlan(ctx context.Context, plan *DeploymentPlan) (map[string]flow.Identifier, error) {
+	if plan.Network != f.network.Name {
+		return nil, fmt.Errorf(
+			"deployment plan was created for network %s, but the network used is %s",
+			plan.Network,
+			f.network.Name,
+		)
+	}
+
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contract := range plan.Contracts {
+		if codeHash(contract.Code) != contract.SourceHash {
+			return nil, fmt.Errorf("code of the contract %s doesn't match the planned source hash", contract.Name)
+		}
+
+		account, err := state.Accounts().ByName(contract.Account)
+		if err != nil {
+			return nil, fmt.Errorf("target account for deploying contract %s not found in configuration", contract.Name)
+		}
+		if account.Address != contract.Address {
+			return nil, fmt.Errorf(
+				"account %s has address %s, but the contract %s is planned for address %s",
+				account.Name,
+				account.Address,
+				contract.Name,
+				contract.Address,
+			)
+		}
+	}
+
+	if err := f.checkDeploymentDrift(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	return f.executeDeploymentPlan(ctx, state, plan, false, nil)
+}
+
+// DeployProjectParallel deploys the project contracts like DeployProject, but deploys the contracts in the same
+// dependency level concurrently and waits for the whole level to be sealed before deploying the next level.
+//
+// Contracts on different accounts are deployed at the same time, while contracts on the same account are deployed
+// one after another, unless the account is proposing with the proposal key pool set on flowkit, in which case
+// they are deployed concurrently with different proposal keys.
+func (f *Flowkit) DeployProjectParallel(ctx context.Context, update UpdateContract) ([]*project.Contract, error) {
+	return f.deployProjectPlan(ctx, update, true)
+}
+
+// deployProjectPlan plans the deployment of the project contracts and executes the plan, deploying the contracts
+// level by level concurrently if parallel is set.
+func (f *Flowkit) deployProjectPlan(ctx context.Context, update UpdateContract, parallel bool) ([]*project.Contract, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	plan, sorted, err := f.planDeployment(ctx, state, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sorted) == 0 {
+		f.logNoDeployments()
+		return sorted, nil
+	}
+
+	f.logger.Info(fmt.Sprintf(
+		"\nDeploying %d contracts for accounts: %s\n",
+		len(sorted),
+		state.AccountsForNetwork(f.network).String(),
+	))
+
+	if _, err := f.executeDeploymentPlan(ctx, state, plan, parallel, nil); err != nil {
+		return nil, err
+	}
+
+	f.logger.Info(fmt.Sprintf("\n%s All contracts deployed successfully", output.SuccessEmoji()))
+	return sorted, nil
+}
+
+// plannedDeployment is the outcome of deploying a planned contract, the transaction ID is empty
+// if the contract is unchanged.
+type plannedDeployment struct {
+	contract *PlannedContract
+	txID     flow.Identifier
+	err      error
+}
+
+// maxBatchByteSize is the maximum size of the script and arguments of a transaction deploying a batch of contracts,
+// leaving room in the transaction size limit for the rest of the transaction and its signatures.
+var maxBatchByteSize = flowGo.DefaultMaxTransactionByteSize - 10_000
+
+// executeDeploymentPlan deploys the planned contracts in order, or level by level if parallel is set,
+// and collects the failed contracts in the ProjectDeploymentError. The progress is recorded in the journal if provided.
+func (f *Flowkit) executeDeploymentPlan(
+	ctx context.Context,
+	state *State,
+	plan *DeploymentPlan,
+	parallel bool,
+	journal *DeploymentJournal,
+) (map[string]flow.Identifier, error) {
+	defer f.logger.StopProgress()
+
+	sent := make(map[string]flow.Identifier)
+	deployErr := &ProjectDeploymentError{}
+	for _, group := range plan.groups(parallel || f.batchDeployments) {
+		batches := f.deploymentBatches(state, group)
+
+		var deployed []plannedDeployment
+		if parallel {
+			f.logger.StartProgress(fmt.Sprintf("Deploying %d contracts of dependency level %d...", len(group), group[0].Level))
+			deployed = f.deployLevel(ctx, state, batches, journal)
+		} else {
+			for _, batch := range batches {
+				deployed = append(deployed, f.deployBatch(ctx, state, batch, journal)...)
+			}
+		}
+
+		results := make(map[*PlannedContract]plannedDeployment, len(deployed))
+		for _, d := range deployed {
+			results[d.contract] = d
+		}
+
+		for _, contract := range group {
+			d := results[contract]
+			if d.err != nil {
+				deployErr.add(contract.Name, d.err, fmt.Sprintf("failed to deploy contract %s", contract.Name))
+				continue
+			}
+
+			if contract.Action == DeploymentUnchanged {
+				f.logger.Info(fmt.Sprintf(
+					"%s -> 0x%s [skipping, no changes found]",
+					output.Italic(contract.Name),
+					contract.Address.String(),
+				))
+				continue
+			}
+
+			sent[contract.Name] = d.txID
+			f.logger.Info(fmt.Sprintf(
+				"%s -> 0x%s (%s) %s",
+				output.Green(contract.Name),
+				contract.Address,
+				d.txID.String(),
+				map[bool]string{true: "[updated]", false: ""}[contract.Action == DeploymentUpdate],
+			))
+		}
+	}
+
+	if len(deployErr.contracts) > 0 {
+		return sent, deployErr
+	}
+
+	return sent, nil
+}
+
+// groups returns the planned contracts grouped by the dependency level, or each contract alone if not by level.
+func (p *DeploymentPlan) groups(byLevel bool) [][]*PlannedContract {
+	groups := make([][]*PlannedContract, 0)
+	for _, contract := range p.Contracts {
+		if !byLevel {
+			groups = append(groups, []*PlannedContract{contract})
+			continue
+		}
+
+		i := slices.IndexFunc(groups, func(group []*PlannedContract) bool {
+			return group[0].Level == contract.Level
+		})
+		if i == -1 {
+			groups = append(groups, nil)
+			i = len(groups) - 1
+		}
+		groups[i] = append(groups[i], contract)
+	}
+
+	slices.SortStableFunc(groups, func(a, b []*PlannedContract) int {
+		return a[0].Level - b[0].Level
+	})
+	return groups
+}
+
+// deploymentBatches splits the contracts into batches deployed with a single transaction each.
+//
+// If deployments aren't batched every contract is a batch on its own, otherwise the changed contracts of each
+// account are batched together in order, starting a new batch when the transaction would exceed the size limit.
+// Contracts which can't be batched, for example because of invalid init arguments, are deployed on their own,
+// so the error is reported only for them.
+func (f *Flowkit) deploymentBatches(state *State, contracts []*PlannedContract) [][]*PlannedContract {
+	batches := make([][]*PlannedContract, 0, len(contracts))
+	open := make(map[flow.Address]int)
+	for _, contract := range contracts {
+		if f.batchDeployments && contract.Action != DeploymentUnchanged {
+			if i, ok := open[contract.Address]; ok && fitsInBatch(state, append(slices.Clip(batches[i]), contract)) {
+				batches[i] = append(batches[i], contract)
+				continue
+			}
+			open[contract.Address] = len(batches)
+		}
+
+		batches = append(batches, []*PlannedContract{contract})
+	}
+
+	return batches
+}
+
+// fitsInBatch returns whether the transaction deploying all the contracts can be built within the size limit.
+func fitsInBatch(state *State, batch []*PlannedContract) bool {
+	account, err := state.Accounts().ByName(batch[0].Account)
+	if err != nil {
+		return false
+	}
+
+	tx, err := transactions.NewDeployAccountContracts(account, accountContracts(batch))
+	if err != nil {
+		return false
+	}
+
+	size := len(tx.FlowTransaction().Script)
+	for _, arg := range tx.FlowTransaction().Arguments {
+		size += len(arg)
+	}
+	return size <= maxBatchByteSize
+}
+
+func accountContracts(batch []*PlannedContract) []transactions.AccountContract {
+	contracts := make([]transactions.AccountContract, len(batch))
+	for i, contract := range batch {
+		contracts[i] = transactions.AccountContract{
+			Name:   contract.Name,
+			Source: contract.Code,
+			Args:   contract.Args,
+			Update: contract.Action == DeploymentUpdate,
+		}
+	}
+	return contracts
+}
+
+// deployLevel deploys the batches of a dependency level concurrently and waits for all of them.
+//
+// The batches are deployed by a copy of flowkit without logging, since the progress can't be shown
+// for multiple transactions at once.
+func (f *Flowkit) deployLevel(
+	ctx context.Context,
+	state *State,
+	batches [][]*PlannedContract,
+	journal *DeploymentJournal,
+) []plannedDeployment {
+	worker := *f
+	worker.logger = output.NewStdoutLogger(output.NoneLog)
+
+	byAccount := make(map[flow.Address][]int)
+	addresses := make([]flow.Address, 0)
+	for i, batch := range batches {
+		address := batch[0].Address
+		if _, ok := byAccount[address]; !ok {
+			addresses = append(addresses, address)
+		}
+		byAccount[address] = append(byAccount[address], i)
+	}
+
+	deployed := make([][]plannedDeployment, len(batches))
+	var wg sync.WaitGroup
+	for _, address := range addresses {
+		indexes := byAccount[address]
+
+		// batches on the pool account are proposed with different keys, so they don't need to wait for each other
+		if f.keyPool != nil && f.keyPool.Address() == address {
+			for _, i := range indexes {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					deployed[i] = worker.deployBatch(ctx, state, batches[i], journal)
+				}()
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, i := range indexes {
+				deployed[i] = worker.deployBatch(ctx, state, batches[i], journal)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return slices.Concat(deployed...)
+}
+
+// deployBatch deploys the planned contracts and records the transaction and the outcome of each in the journal.
+func (f *Flowkit) deployBatch(
+	ctx context.Context,
+	state *State,
+	batch []*PlannedContract,
+	journal *DeploymentJournal,
+) []plannedDeployment {
+	deployed := f.sendPlannedContracts(ctx, state, batch, func(ID flow.Identifier) error {
+		for _, contract := range batch {
+			if err := journal.sent(contract.Name, ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	for i := range deployed {
+		err := journal.finish(deployed[i].contract.Name, deployed[i].err)
+		if err != nil && deployed[i].err == nil {
+			deployed[i].err = err
+		}
+	}
+
+	return deployed
+}
+
+// sendPlannedContracts sends the transaction for the planned contracts actions and waits for it to be sealed.
+// The contracts of the batch are on the same account and share the transaction outcome.
+//
+// If the contract account is proposing with the proposal key pool the transaction is proposed with a leased key.
+func (f *Flowkit) sendPlannedContracts(
+	ctx context.Context,
+	state *State,
+	batch []*PlannedContract,
+	sent func(flow.Identifier) error,
+) []plannedDeployment {
+	deployed := make([]plannedDeployment, len(batch))
+	for i, contract := range batch {
+		deployed[i].contract = contract
+	}
+
+	outcome := func(txID flow.Identifier, err error) []plannedDeployment {
+		for i := range deployed {
+			deployed[i].txID = txID
+			deployed[i].err = err
+		}
+		return deployed
+	}
+
+	// unchanged contracts are never batched
+	if batch[0].Action == DeploymentUnchanged {
+		return deployed
+	}
+
+	account, err := state.Accounts().ByName(batch[0].Account)
+	if err != nil {
+		return outcome(flow.EmptyID, fmt.Errorf("target account for deploying contract not found in configuration"))
+	}
+
+	if f.keyPool == nil || f.keyPool.Address() != account.Address {
+		return outcome(f.sendContracts(ctx, account, accountContracts(batch), sent, untilSealed))
+	}
+
+	key, err := f.keyPool.Lease(ctx)
+	if err != nil {
+		return outcome(flow.EmptyID, err)
+	}
+
+	signer, err := f.keyPool.Signer(key)
+	if err != nil {
+		f.keyPool.Release(key, false)
+		return outcome(flow.EmptyID, err)
+	}
+
+	txID, err := f.sendContracts(ctx, signer, accountContracts(batch), sent, untilSealed)
+	// the sequence number is fetched when the transaction is prepared, so it's only known to be used if sealed
+	if err != nil {
+		f.keyPool.Invalidate(key)
+	} else {
+		f.keyPool.Release(key, true)
+	}
+
+	return outcome(txID, err)
+}
+
+// checkDeploymentDrift compares the contracts on the network with the existing code recorded in the plan.
+func (f *Flowkit) checkDeploymentDrift(ctx context.Context, plan *DeploymentPlan) error {
+	onChain := make(map[flow.Address]*flow.Account)
+	drift := &DeploymentDriftError{}
+	for _, contract := range plan.Contracts {
+		flowAccount, ok := onChain[contract.Address]
+		if !ok {
+			var err error
+			flowAccount, err = f.gateway.GetAccount(ctx, contract.Address)
+			if err != nil {
+				return fmt.Errorf("failed to get account %s: %w", contract.Address, err)
+			}
+			onChain[contract.Address] = flowAccount
+		}
+
+		existingHash := ""
+		if existing, exists := flowAccount.Contracts[contract.Name]; exists {
+			existingHash = codeHash(existing)
+		}
+		if existingHash != contract.ExistingHash {
+			drift.Contracts = append(drift.Contracts, contract.Name)
+		}
+	}
+
+	if len(drift.Contracts) > 0 {
+		return drift
+	}
+
+	return nil
+}
@@ -0,0 +1,388 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/tests"
+)
+
+func TestDeploymentPlan(t *testing.T) {
+	t.Run("Serialize to JSON", func(t *testing.T) {
+		code := tests.ContractSimpleWithArgs.Source
+		plan := &DeploymentPlan{
+			Network: config.EmulatorNetwork.Name,
+			Contracts: []*PlannedContract{{
+				Name:         tests.ContractSimpleWithArgs.Name,
+				Account:      "emulator-account",
+				Address:      flow.HexToAddress("f8d6e0586b0a20c7"),
+				Location:     tests.ContractSimpleWithArgs.Filename,
+				Level:        1,
+				Action:       DeploymentUpdate,
+				SourceHash:   codeHash(code),
+				ExistingHash: codeHash([]byte("access(all) contract Simple {}")),
+				Args:         []cadence.Value{cadence.UInt64(4)},
+				Code:         code,
+			}},
+		}
+
+		data, err := json.Marshal(plan)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"action":"update"`)
+		assert.Contains(t, string(data), `"address":"f8d6e0586b0a20c7"`)
+		assert.Contains(t, string(data), `"args":[{"value":"4","type":"UInt64"}]`)
+
+		var decoded DeploymentPlan
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, plan, &decoded)
+	})
+
+	t.Run("Fail on different network", func(t *testing.T) {
+		_, flowkit, _ := setup()
+
+		_, err := flowkit.ExecuteDeploymentPlan(ctx, &DeploymentPlan{Network: "testnet"})
+		assert.EqualError(t, err, "deployment plan was created for network testnet, but the network used is emulator")
+	})
+
+	t.Run("Fail on modified code", func(t *testing.T) {
+		_, flowkit, _ := setup()
+
+		_, err := flowkit.ExecuteDeploymentPlan(ctx, &DeploymentPlan{
+			Network: config.EmulatorNetwork.Name,
+			Contracts: []*PlannedContract{{
+				Name:       tests.ContractSimple.Name,
+				SourceHash: codeHash(tests.ContractSimple.Source),
+				Code:       tests.ContractSimpleUpdated.Source,
+			}},
+		})
+		assert.EqualError(t, err, "code of the contract Simple doesn't match the planned source hash")
+	})
+}
+
+func TestDeploymentPlan_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+	state.Networks().AddOrUpdate(config.EmulatorNetwork)
+	state.Contracts().AddOrUpdate(config.Contract{
+		Name:     tests.ContractSimple.Name,
+		Location: tests.ContractSimple.Filename,
+	})
+	state.Deployments().AddOrUpdate(config.Deployment{
+		Network:   config.EmulatorNetwork.Name,
+		Account:   srvAcc.Name,
+		Contracts: []config.ContractDeployment{{Name: tests.ContractSimple.Name}},
+	})
+
+	added, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+	require.NoError(t, err)
+	require.Len(t, added.Contracts, 1)
+
+	planned := added.Contracts[0]
+	assert.Equal(t, tests.ContractSimple.Name, planned.Name)
+	assert.Equal(t, srvAcc.Name, planned.Account)
+	assert.Equal(t, srvAcc.Address, planned.Address)
+	assert.Equal(t, DeploymentAdd, planned.Action)
+	assert.Equal(t, codeHash(tests.ContractSimple.Source), planned.SourceHash)
+	assert.Empty(t, planned.ExistingHash)
+
+	// nothing is sent while planning
+	account, err := flowkit.GetAccount(ctx, srvAcc.Address)
+	require.NoError(t, err)
+	assert.NotContains(t, account.Contracts, tests.ContractSimple.Name)
+
+	t.Run("Execute reviewed plan", func(t *testing.T) {
+		data, err := json.Marshal(added)
+		require.NoError(t, err)
+
+		var reviewed DeploymentPlan
+		require.NoError(t, json.Unmarshal(data, &reviewed))
+
+		sent, err := flowkit.ExecuteDeploymentPlan(ctx, &reviewed)
+		require.NoError(t, err)
+		assert.Len(t, sent, 1)
+		assert.Contains(t, sent, tests.ContractSimple.Name)
+
+		account, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		assert.Equal(t, tests.ContractSimple.Source, account.Contracts[tests.ContractSimple.Name])
+	})
+
+	t.Run("Plan update", func(t *testing.T) {
+		state.Contracts().AddOrUpdate(config.Contract{
+			Name:     tests.ContractSimple.Name,
+			Location: tests.ContractSimpleUpdated.Filename,
+		})
+
+		_, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+		var deployErr *ProjectDeploymentError
+		require.ErrorAs(t, err, &deployErr)
+		assert.Contains(t, deployErr.Contracts(), tests.ContractSimple.Name)
+
+		updated, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(true))
+		require.NoError(t, err)
+		require.Len(t, updated.Contracts, 1)
+		assert.Equal(t, DeploymentUpdate, updated.Contracts[0].Action)
+		assert.Equal(t, codeHash(tests.ContractSimple.Source), updated.Contracts[0].ExistingHash)
+
+		// the contract was added after the first plan was created
+		_, err = flowkit.ExecuteDeploymentPlan(ctx, added)
+		var driftErr *DeploymentDriftError
+		require.ErrorAs(t, err, &driftErr)
+		assert.Equal(t, []string{tests.ContractSimple.Name}, driftErr.Contracts)
+
+		sent, err := flowkit.ExecuteDeploymentPlan(ctx, updated)
+		require.NoError(t, err)
+		assert.Len(t, sent, 1)
+
+		unchanged, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+		require.NoError(t, err)
+		require.Len(t, unchanged.Contracts, 1)
+		assert.Equal(t, DeploymentUnchanged, unchanged.Contracts[0].Action)
+
+		sent, err = flowkit.ExecuteDeploymentPlan(ctx, unchanged)
+		require.NoError(t, err)
+		assert.Empty(t, sent)
+	})
+}
+
+func TestDeployProjectParallel_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+	state.Networks().AddOrUpdate(config.EmulatorNetwork)
+
+	pkey, _ := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("seedseedseedseedseedseedseedseedseedseedseedseedDeploy"))
+	flowAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{{
+		Public:   pkey.PublicKey(),
+		SigAlgo:  crypto.ECDSA_P256,
+		HashAlgo: crypto.SHA3_256,
+	}})
+	require.NoError(t, err)
+	poolAcc := &accounts.Account{
+		Name:    "Pool",
+		Address: flowAcc.Address,
+		Key:     accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+	}
+	state.Accounts().AddOrUpdate(poolAcc)
+
+	indexes, err := flowkit.AddProposalKeys(ctx, poolAcc, 2)
+	require.NoError(t, err)
+	pool, err := NewProposalKeyPool(ctx, flowkit.Gateway(), poolAcc, indexes)
+	require.NoError(t, err)
+	flowkit.SetProposalKeyPool(pool)
+
+	for _, c := range []tests.Resource{tests.ContractA, tests.ContractB, tests.ContractC, tests.ContractSimple, tests.ContractHelloString} {
+		state.Contracts().AddOrUpdate(config.Contract{Name: c.Name, Location: c.Filename})
+	}
+	state.Deployments().AddOrUpdate(config.Deployment{
+		Network: config.EmulatorNetwork.Name,
+		Account: srvAcc.Name,
+		Contracts: []config.ContractDeployment{
+			{Name: tests.ContractC.Name, Args: []cadence.Value{cadence.String("foo")}},
+			{Name: tests.ContractB.Name},
+			{Name: tests.ContractA.Name},
+		},
+	})
+	state.Deployments().AddOrUpdate(config.Deployment{
+		Network: config.EmulatorNetwork.Name,
+		Account: poolAcc.Name,
+		Contracts: []config.ContractDeployment{
+			{Name: tests.ContractSimple.Name},
+			{Name: tests.ContractHelloString.Name},
+		},
+	})
+
+	plan, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+	require.NoError(t, err)
+	levels := make(map[string]int)
+	for _, c := range plan.Contracts {
+		levels[c.Name] = c.Level
+	}
+	assert.Equal(t, map[string]int{
+		tests.ContractA.Name:           0,
+		tests.ContractSimple.Name:      0,
+		tests.ContractHelloString.Name: 0,
+		tests.ContractB.Name:           1,
+		tests.ContractC.Name:           2,
+	}, levels)
+
+	contracts, err := flowkit.DeployProjectParallel(ctx, UpdateExistingContract(false))
+	require.NoError(t, err)
+	require.Len(t, contracts, 5)
+	assert.Equal(t, tests.ContractC.Name, contracts[4].Name)
+
+	for address, names := range map[flow.Address][]string{
+		srvAcc.Address:  {tests.ContractA.Name, tests.ContractB.Name, tests.ContractC.Name},
+		poolAcc.Address: {tests.ContractSimple.Name, tests.ContractHelloString.Name},
+	} {
+		account, err := flowkit.GetAccount(ctx, address)
+		require.NoError(t, err)
+		for _, name := range names {
+			assert.Contains(t, account.Contracts, name)
+		}
+	}
+
+	// contracts on the pool account were proposed with the pool keys
+	account, err := flowkit.GetAccount(ctx, poolAcc.Address)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), account.Keys[0].SequenceNumber)
+	assert.Equal(t, uint64(2), account.Keys[1].SequenceNumber+account.Keys[2].SequenceNumber)
+
+	t.Run("Skip unchanged contracts", func(t *testing.T) {
+		contracts, err := flowkit.DeployProjectParallel(ctx, UpdateExistingContract(false))
+		require.NoError(t, err)
+		assert.Len(t, contracts, 5)
+	})
+}
+
+func TestBatchDeployments_Integration(t *testing.T) {
+	setupBatches := func(resources []tests.Resource, args map[string][]cadence.Value) (*State, Flowkit, *accounts.Account) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+		state.Networks().AddOrUpdate(config.EmulatorNetwork)
+
+		deployments := make([]config.ContractDeployment, len(resources))
+		for i, c := range resources {
+			state.Contracts().AddOrUpdate(config.Contract{Name: c.Name, Location: c.Filename})
+			deployments[i] = config.ContractDeployment{Name: c.Name, Args: args[c.Name]}
+		}
+		state.Deployments().AddOrUpdate(config.Deployment{
+			Network:   config.EmulatorNetwork.Name,
+			Account:   srvAcc.Name,
+			Contracts: deployments,
+		})
+		flowkit.SetBatchDeployments(true)
+
+		return state, flowkit, srvAcc
+	}
+
+	t.Run("Deploy dependency levels in single transactions", func(t *testing.T) {
+		_, flowkit, srvAcc := setupBatches(
+			[]tests.Resource{tests.ContractA, tests.ContractB, tests.ContractC, tests.ContractSimple, tests.ContractHelloString},
+			map[string][]cadence.Value{tests.ContractC.Name: {cadence.String("foo")}},
+		)
+
+		plan, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+		require.NoError(t, err)
+
+		sent, err := flowkit.ExecuteDeploymentPlan(ctx, plan)
+		require.NoError(t, err)
+		require.Len(t, sent, 5)
+
+		// the first level is deployed with one transaction
+		assert.Equal(t, sent[tests.ContractA.Name], sent[tests.ContractSimple.Name])
+		assert.Equal(t, sent[tests.ContractA.Name], sent[tests.ContractHelloString.Name])
+		assert.NotEqual(t, sent[tests.ContractA.Name], sent[tests.ContractB.Name])
+		assert.NotEqual(t, sent[tests.ContractB.Name], sent[tests.ContractC.Name])
+
+		account, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		for name := range sent {
+			assert.Contains(t, account.Contracts, name)
+		}
+	})
+
+	t.Run("Deploy project in single transactions", func(t *testing.T) {
+		_, flowkit, srvAcc := setupBatches(
+			[]tests.Resource{tests.ContractA, tests.ContractB, tests.ContractC, tests.ContractSimple, tests.ContractHelloString},
+			map[string][]cadence.Value{tests.ContractC.Name: {cadence.String("foo")}},
+		)
+
+		before, err := flowkit.GetBlock(ctx, LatestBlockQuery)
+		require.NoError(t, err)
+
+		contracts, err := flowkit.DeployProject(ctx, UpdateExistingContract(false))
+		require.NoError(t, err)
+		assert.Len(t, contracts, 5)
+
+		// each transaction is sealed in its own block, and the first level is deployed with one transaction
+		after, err := flowkit.GetBlock(ctx, LatestBlockQuery)
+		require.NoError(t, err)
+		assert.Equal(t, before.Height+3, after.Height)
+
+		account, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		for _, c := range contracts {
+			assert.Contains(t, account.Contracts, c.Name)
+		}
+	})
+
+	t.Run("Split batches exceeding the size limit", func(t *testing.T) {
+		_, flowkit, _ := setupBatches([]tests.Resource{tests.ContractA, tests.ContractSimple, tests.ContractHelloString}, nil)
+
+		defaultSize := maxBatchByteSize
+		defer func() { maxBatchByteSize = defaultSize }()
+		maxBatchByteSize = 700
+
+		plan, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+		require.NoError(t, err)
+
+		sent, err := flowkit.ExecuteDeploymentPlan(ctx, plan)
+		require.NoError(t, err)
+		require.Len(t, sent, 3)
+
+		IDs := make(map[flow.Identifier]bool)
+		for _, ID := range sent {
+			IDs[ID] = true
+		}
+		assert.Len(t, IDs, 2)
+	})
+
+	t.Run("Keep account unchanged if a contract fails", func(t *testing.T) {
+		_, flowkit, srvAcc := setupBatches(nil, nil)
+		plan := &DeploymentPlan{Network: config.EmulatorNetwork.Name}
+		for _, c := range []struct {
+			resource tests.Resource
+			args     []cadence.Value
+		}{
+			{tests.ContractHelloString, nil},
+			// the init argument has the wrong type, so the contract fails to be added
+			{tests.ContractSimpleWithArgs, []cadence.Value{cadence.String("foo")}},
+		} {
+			plan.Contracts = append(plan.Contracts, &PlannedContract{
+				Name:       c.resource.Name,
+				Account:    srvAcc.Name,
+				Address:    srvAcc.Address,
+				Action:     DeploymentAdd,
+				SourceHash: codeHash(c.resource.Source),
+				Args:       c.args,
+				Code:       c.resource.Source,
+			})
+		}
+
+		_, err := flowkit.ExecuteDeploymentPlan(ctx, plan)
+		var deployErr *ProjectDeploymentError
+		require.ErrorAs(t, err, &deployErr)
+		assert.Len(t, deployErr.Contracts(), 2)
+
+		account, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		assert.NotContains(t, account.Contracts, tests.ContractHelloString.Name)
+		assert.NotContains(t, account.Contracts, tests.ContractSimpleWithArgs.Name)
+	})
+}
@@ -0,0 +1,169 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go/fvm/systemcontracts"
+	flowGo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flowkit/v2/gateway"
+	"github.com/onflow/flowkit/v2/transactions"
+)
+
+// DefaultComputeMargin is the safety margin added to the estimated compute limits, as a fraction of the
+// computation used by the transaction.
+const DefaultComputeMargin = 0.2
+
+// maxComputeLimit is the compute limit the transactions are executed with when estimated.
+const maxComputeLimit = flowGo.DefaultMaxTransactionGasLimit
+
+// computeFeesScript computes the transaction fees the same way the FVM does when deducting them.
+const computeFeesScript = `
+import FlowFees from 0x%s
+
+access(all) fun main(inclusionEffort: UFix64, executionEffort: UFix64): UFix64 {
+	return FlowFees.computeFees(inclusionEffort: inclusionEffort, executionEffort: executionEffort)
+}`
+
+// TransactionEstimate is the computation, effort and fee of a transaction measured by executing it against an emulator.
+type TransactionEstimate struct {
+	ComputationUsed uint64
+	InclusionEffort cadence.UFix64
+	ExecutionEffort cadence.UFix64
+	// ComputeLimit is the suggested compute limit, the computation used with the safety margin added.
+	ComputeLimit uint64
+	// Fee is the expected FLOW fee paid by the payer of the transaction.
+	Fee cadence.UFix64
+	// Simulation is the simulation the estimate was measured with, including the transaction error if it failed.
+	Simulation *TransactionSimulation
+}
+
+// EstimateTransaction builds the transaction the same way SendTransaction does and simulates it with the maximum
+// compute limit to measure its computation and effort, see SimulateTransaction for how the transaction is executed.
+//
+// The suggested compute limit is the computation used with the margin added as a fraction of it, so a margin of 0.2
+// adds 20%, and it never exceeds the maximum compute limit. The transaction failing doesn't fail the estimation,
+// the error is part of the estimate simulation.
+func (f *Flowkit) EstimateTransaction(
+	ctx context.Context,
+	accounts transactions.AccountRoles,
+	script Script,
+	margin float64,
+) (*TransactionEstimate, error) {
+	f.logger.StartProgress("Estimating transaction...")
+	defer f.logger.StopProgress()
+
+	return f.estimate(ctx, accounts.AddressRoles(), margin, func(sim *Flowkit) (*transactions.Transaction, error) {
+		return sim.signedTransaction(ctx, accounts, script, maxComputeLimit, nil)
+	})
+}
+
+// estimate simulates the transaction built by the simulation flowkit and measures its computation, effort and fee.
+func (f *Flowkit) estimate(
+	ctx context.Context,
+	roles transactions.AddressesRoles,
+	margin float64,
+	build func(sim *Flowkit) (*transactions.Transaction, error),
+) (*TransactionEstimate, error) {
+	if margin < 0 {
+		return nil, fmt.Errorf("compute margin can not be negative")
+	}
+
+	simulation, gw, discard, err := f.simulate(ctx, roles, build)
+	if err != nil {
+		return nil, err
+	}
+
+	// the FVM uses the raw computation used as the execution effort and a fixed inclusion effort
+	inclusionEffort := cadence.UFix64(flowGo.TransactionBody{}.InclusionEffort())
+	executionEffort := cadence.UFix64(simulation.ComputationUsed)
+
+	fee, feeErr := transactionFee(ctx, gw, inclusionEffort, executionEffort)
+	if err := discard(); err != nil {
+		return nil, err
+	}
+	if feeErr != nil {
+		return nil, fmt.Errorf("failed to compute the transaction fee: %w", feeErr)
+	}
+
+	limit := uint64(math.Ceil(float64(simulation.ComputationUsed) * (1 + margin)))
+	limit = min(max(limit, 1), maxComputeLimit)
+
+	return &TransactionEstimate{
+		ComputationUsed: simulation.ComputationUsed,
+		InclusionEffort: inclusionEffort,
+		ExecutionEffort: executionEffort,
+		ComputeLimit:    limit,
+		Fee:             fee,
+		Simulation:      simulation,
+	}, nil
+}
+
+// estimateComputeLimit returns the compute limit estimated with the margin set on flowkit,
+// and fails if the transaction fails while being estimated.
+func (f *Flowkit) estimateComputeLimit(
+	ctx context.Context,
+	roles transactions.AddressesRoles,
+	build func(sim *Flowkit) (*transactions.Transaction, error),
+) (uint64, error) {
+	margin := DefaultComputeMargin
+	if f.computeMargin != nil {
+		margin = *f.computeMargin
+	}
+
+	f.logger.StartProgress("Estimating compute limit...")
+	estimate, err := f.estimate(ctx, roles, margin, build)
+	f.logger.StopProgress()
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate the compute limit: %w", err)
+	}
+	if estimate.Simulation.Error != nil {
+		return 0, fmt.Errorf("transaction failed while estimating the compute limit: %w", estimate.Simulation.Error)
+	}
+
+	f.logger.Info(fmt.Sprintf("Estimated compute limit: %d", estimate.ComputeLimit))
+	return estimate.ComputeLimit, nil
+}
+
+// transactionFee computes the fee of the transaction with the effort using the fee parameters of the emulator chain.
+func transactionFee(
+	ctx context.Context,
+	gw *gateway.EmulatorGateway,
+	inclusionEffort cadence.UFix64,
+	executionEffort cadence.UFix64,
+) (cadence.UFix64, error) {
+	feesAddress := systemcontracts.SystemContractsForChain(flowGo.ChainID(gw.ChainID())).FlowFees.Address
+	script := fmt.Sprintf(computeFeesScript, feesAddress.Hex())
+
+	value, err := gw.ExecuteScript(ctx, []byte(script), []cadence.Value{inclusionEffort, executionEffort})
+	if err != nil {
+		return 0, err
+	}
+
+	fee, ok := value.(cadence.UFix64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected transaction fee result %s", value)
+	}
+	return fee, nil
+}
@@ -0,0 +1,176 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"github.com/onflow/flow-go-sdk"
+)
+
+// Ledger key part types identifying the owner and the key of a register.
+const (
+	keyPartOwner = 0
+	keyPartKey   = 2
+)
+
+// ExecutionResult is the outcome of executing a block. The block is executed in chunks,
+// one for every collection and a last one for the system transactions.
+type ExecutionResult struct {
+	BlockID          flow.Identifier
+	PreviousResultID flow.Identifier
+	Chunks           []ExecutionChunk
+	// ServiceEvents are the protocol events emitted while executing the block, such as the epoch events.
+	ServiceEvents []ServiceEvent
+}
+
+// ExecutionChunk describes the execution of a chunk of the block.
+type ExecutionChunk struct {
+	Index                uint64
+	CollectionIndex      uint
+	NumberOfTransactions uint16
+	TotalComputationUsed uint64
+	// StartState and EndState are the execution state commitments before and after executing the chunk.
+	StartState flow.StateCommitment
+	EndState   flow.StateCommitment
+	// EventCollection is the hash of the events emitted by the chunk.
+	EventCollection []byte
+}
+
+// ServiceEvent is a protocol event, the payload is the encoded event.
+type ServiceEvent struct {
+	Type    string
+	Payload []byte
+}
+
+// NewExecutionResult converts the execution result returned by the gateway.
+func NewExecutionResult(result *flow.ExecutionResult) *ExecutionResult {
+	res := &ExecutionResult{
+		BlockID:          result.BlockID,
+		PreviousResultID: result.PreviousResultID,
+		Chunks:           make([]ExecutionChunk, 0, len(result.Chunks)),
+		ServiceEvents:    make([]ServiceEvent, 0, len(result.ServiceEvents)),
+	}
+
+	for _, c := range result.Chunks {
+		res.Chunks = append(res.Chunks, ExecutionChunk{
+			Index:                c.Index,
+			CollectionIndex:      c.CollectionIndex,
+			NumberOfTransactions: c.NumberOfTransactions,
+			TotalComputationUsed: c.TotalComputationUsed,
+			StartState:           c.StartState,
+			EndState:             c.EndState,
+			EventCollection:      c.EventCollection,
+		})
+	}
+
+	for _, e := range result.ServiceEvents {
+		res.ServiceEvents = append(res.ServiceEvents, ServiceEvent{
+			Type:    e.Type,
+			Payload: e.Payload,
+		})
+	}
+
+	return res
+}
+
+// ExecutionData is the data produced by executing every chunk of a block.
+type ExecutionData struct {
+	BlockID flow.Identifier
+	Chunks  []ChunkExecutionData
+}
+
+// ChunkExecutionData contains the transactions executed by a chunk with their results and events,
+// and the registers the chunk updated.
+type ChunkExecutionData struct {
+	Transactions []*flow.Transaction
+	Results      []ChunkTransactionResult
+	Events       []flow.Event
+	// RegisterUpdates are empty if the network doesn't provide the trie updates.
+	RegisterUpdates []RegisterUpdate
+}
+
+// ChunkTransactionResult is the outcome of a transaction executed by a chunk.
+type ChunkTransactionResult struct {
+	TransactionID   flow.Identifier
+	Failed          bool
+	ComputationUsed uint64
+}
+
+// RegisterUpdate is a register value written to the execution state, the owner is empty for the global registers.
+type RegisterUpdate struct {
+	Path  []byte
+	Owner flow.Address
+	Key   string
+	Value []byte
+}
+
+// NewExecutionData converts the execution data returned by the gateway.
+func NewExecutionData(data *flow.ExecutionData) *ExecutionData {
+	res := &ExecutionData{
+		BlockID: data.BlockID,
+		Chunks:  make([]ChunkExecutionData, 0, len(data.ChunkExecutionData)),
+	}
+
+	for _, c := range data.ChunkExecutionData {
+		chunk := ChunkExecutionData{
+			Transactions: c.Transactions,
+			Results:      make([]ChunkTransactionResult, 0, len(c.TransactionResults)),
+			Events:       make([]flow.Event, 0, len(c.Events)),
+		}
+
+		for _, r := range c.TransactionResults {
+			chunk.Results = append(chunk.Results, ChunkTransactionResult{
+				TransactionID:   r.TransactionID,
+				Failed:          r.Failed,
+				ComputationUsed: r.ComputationUsed,
+			})
+		}
+
+		for _, e := range c.Events {
+			chunk.Events = append(chunk.Events, *e)
+		}
+
+		if c.TrieUpdate != nil {
+			for i, payload := range c.TrieUpdate.Payloads {
+				update := newRegisterUpdate(payload)
+				if i < len(c.TrieUpdate.Paths) {
+					update.Path = c.TrieUpdate.Paths[i]
+				}
+				chunk.RegisterUpdates = append(chunk.RegisterUpdates, update)
+			}
+		}
+
+		res.Chunks = append(res.Chunks, chunk)
+	}
+
+	return res
+}
+
+func newRegisterUpdate(payload *flow.Payload) RegisterUpdate {
+	update := RegisterUpdate{Value: payload.Value}
+	for _, part := range payload.KeyPart {
+		switch part.Type {
+		case keyPartOwner:
+			update.Owner = flow.BytesToAddress(part.Value)
+		case keyPartKey:
+			update.Key = string(part.Value)
+		}
+	}
+
+	return update
+}
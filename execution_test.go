@@ -0,0 +1,101 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit_test
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2"
+	"github.com/onflow/flowkit/v2/tests"
+)
+
+func TestNewExecutionResult(t *testing.T) {
+	blockID := flow.HexToID("01")
+	result := flowkit.NewExecutionResult(&flow.ExecutionResult{
+		BlockID:          blockID,
+		PreviousResultID: flow.HexToID("02"),
+		Chunks: []*flow.Chunk{{
+			CollectionIndex:      0,
+			StartState:           flow.StateCommitment(flow.HexToID("03")),
+			EndState:             flow.StateCommitment(flow.HexToID("04")),
+			BlockID:              blockID,
+			TotalComputationUsed: 100,
+			NumberOfTransactions: 2,
+			Index:                0,
+		}},
+		ServiceEvents: []*flow.ServiceEvent{{Type: "flow.EpochSetup", Payload: []byte("{}")}},
+	})
+
+	assert.Equal(t, blockID, result.BlockID)
+	assert.Equal(t, flow.HexToID("02"), result.PreviousResultID)
+	require.Len(t, result.Chunks, 1)
+	assert.Equal(t, uint64(100), result.Chunks[0].TotalComputationUsed)
+	assert.Equal(t, uint16(2), result.Chunks[0].NumberOfTransactions)
+	assert.Equal(t, flow.StateCommitment(flow.HexToID("04")), result.Chunks[0].EndState)
+	assert.Equal(t, []flowkit.ServiceEvent{{Type: "flow.EpochSetup", Payload: []byte("{}")}}, result.ServiceEvents)
+}
+
+func TestNewExecutionData(t *testing.T) {
+	tx := tests.NewTransaction()
+	event := tests.NewEvent(0, "A.01.Token.Deposited", nil, nil)
+	owner := flow.HexToAddress("01")
+
+	data := flowkit.NewExecutionData(&flow.ExecutionData{
+		BlockID: flow.HexToID("01"),
+		ChunkExecutionData: []*flow.ChunkExecutionData{{
+			Transactions: []*flow.Transaction{tx},
+			Events:       []*flow.Event{event},
+			TrieUpdate: &flow.TrieUpdate{
+				Paths: [][]byte{{1}, {2}},
+				Payloads: []*flow.Payload{{
+					KeyPart: []*flow.KeyPart{{Type: 0, Value: owner.Bytes()}, {Type: 2, Value: []byte("balance")}},
+					Value:   []byte{5},
+				}, {
+					KeyPart: []*flow.KeyPart{{Type: 0, Value: nil}, {Type: 2, Value: []byte("uuid")}},
+					Value:   []byte{6},
+				}},
+			},
+			TransactionResults: []*flow.LightTransactionResult{{
+				TransactionID:   tx.ID(),
+				Failed:          true,
+				ComputationUsed: 10,
+			}},
+		}, {}},
+	})
+
+	require.Len(t, data.Chunks, 2)
+	chunk := data.Chunks[0]
+	assert.Equal(t, []*flow.Transaction{tx}, chunk.Transactions)
+	assert.Equal(t, []flow.Event{*event}, chunk.Events)
+	assert.Equal(t, []flowkit.ChunkTransactionResult{{
+		TransactionID:   tx.ID(),
+		Failed:          true,
+		ComputationUsed: 10,
+	}}, chunk.Results)
+	assert.Equal(t, []flowkit.RegisterUpdate{
+		{Path: []byte{1}, Owner: owner, Key: "balance", Value: []byte{5}},
+		{Path: []byte{2}, Owner: flow.EmptyAddress, Key: "uuid", Value: []byte{6}},
+	}, chunk.RegisterUpdates)
+
+	assert.Empty(t, data.Chunks[1].RegisterUpdates)
+}
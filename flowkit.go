@@ -95,6 +95,27 @@ type EventWorker struct {
 	BlocksPerWorker uint64
 }
 
+// BatchScript is a script executed as part of a batch at the block provided by the query.
+type BatchScript struct {
+	Script Script
+	Query  ScriptQuery
+}
+
+// BatchScriptResult is the result of a script executed as part of a batch.
+type BatchScriptResult struct {
+	Value cadence.Value
+	Err   error
+}
+
+// ScriptBatch defines how many workers execute the batch of scripts concurrently, and whether the scripts are
+// pinned to the same block height. Pinned scripts are executed at the height instead of their query block,
+// or at the latest sealed block height if the height is not set.
+type ScriptBatch struct {
+	Workers int
+	Pinned  bool
+	Height  uint64
+}
+
 var _ Services = &Flowkit{}
 
 func NewFlowkit(
@@ -103,14 +124,24 @@ func NewFlowkit(
 	gateway gateway.Gateway,
 	logger output.Logger,
 ) *Flowkit {
-	return &Flowkit{state, network, gateway, logger}
+	return &Flowkit{
+		state:   state,
+		network: network,
+		gateway: gateway,
+		logger:  logger,
+	}
 }
 
 type Flowkit struct {
-	state   *State
-	network config.Network
-	gateway gateway.Gateway
-	logger  output.Logger
+	state            *State
+	network          config.Network
+	gateway          gateway.Gateway
+	logger           output.Logger
+	computeLimit     *uint64
+	computeMargin    *float64
+	keyPool          *ProposalKeyPool
+	batchDeployments bool
+	validateUpdates  bool
 }
 
 func (f *Flowkit) Network() config.Network {
@@ -125,6 +156,43 @@ func (f *Flowkit) SetLogger(logger output.Logger) {
 	f.logger = logger
 }
 
+// SetComputeLimit sets the compute limit of the transactions creating accounts and adding contracts.
+//
+// The default transaction gas limit is used until the limit is set, and if the limit is set to zero
+// the compute limit of each transaction is estimated by executing it against an emulator first.
+func (f *Flowkit) SetComputeLimit(limit uint64) {
+	f.computeLimit = &limit
+}
+
+// SetComputeMargin sets the safety margin added to the estimated compute limits, as a fraction of the
+// computation used by the transaction. The DefaultComputeMargin is used until the margin is set.
+func (f *Flowkit) SetComputeMargin(margin float64) {
+	f.computeMargin = &margin
+}
+
+// SetProposalKeyPool sets the pool the transactions proposed by the pool account are sent with, each transaction
+// is proposed with a key leased from the pool instead of the proposer account key. Setting nil removes the pool.
+func (f *Flowkit) SetProposalKeyPool(pool *ProposalKeyPool) {
+	f.keyPool = pool
+}
+
+// SetBatchDeployments sets whether the contracts deployed to the same account in a dependency level are
+// deployed with a single transaction when a deployment plan is executed, so the account either gets all of them
+// or stays unchanged. This applies to DeployProject, DeployProjectParallel and ExecuteDeploymentPlan.
+//
+// Batches exceeding the transaction size limit are split into several transactions, in which case only the
+// contracts of each transaction are deployed atomically and the account can be left with part of the level deployed.
+func (f *Flowkit) SetBatchDeployments(batch bool) {
+	f.batchDeployments = batch
+}
+
+// SetValidateContractUpdates sets whether contract updates are validated with the Cadence contract update rules
+// before they are sent or planned, so incompatible updates are rejected with a ContractUpdateValidationError
+// instead of failing on the network.
+func (f *Flowkit) SetValidateContractUpdates(validate bool) {
+	f.validateUpdates = validate
+}
+
 func (f *Flowkit) State() (*State, error) {
 	if f.state == nil {
 		return nil, config.ErrDoesNotExist
@@ -153,6 +221,34 @@ func (f *Flowkit) CreateAccount(
 	ctx context.Context,
 	signer *accounts.Account,
 	keys []accounts.PublicKey,
+) (*flow.Account, flow.Identifier, error) {
+	return f.createAccount(ctx, signer, keys, untilSealed)
+}
+
+// CreateAccountAndTrack creates the account like CreateAccount, but only waits until the transaction reaches the
+// provided status, which must be at least executed for the account address to be known. The callback is called
+// with every status transition and can be nil.
+//
+// If the transaction isn't tracked until sealed the account isn't fetched from the network, and the returned
+// account only contains the address and the keys.
+func (f *Flowkit) CreateAccountAndTrack(
+	ctx context.Context,
+	signer *accounts.Account,
+	keys []accounts.PublicKey,
+	until flow.TransactionStatus,
+	callback TransactionStatusCallback,
+) (*flow.Account, flow.Identifier, error) {
+	if until < flow.TransactionStatusExecuted {
+		return nil, flow.EmptyID, fmt.Errorf("can not track account creation until status %s, the account is known once executed", until)
+	}
+	return f.createAccount(ctx, signer, keys, transactionTracking{until: until, callback: callback})
+}
+
+func (f *Flowkit) createAccount(
+	ctx context.Context,
+	signer *accounts.Account,
+	keys []accounts.PublicKey,
+	tracking transactionTracking,
 ) (*flow.Account, flow.Identifier, error) {
 	var accKeys []*flow.AccountKey
 	for _, k := range keys {
@@ -175,12 +271,9 @@ func (f *Flowkit) CreateAccount(
 		accKeys = append(accKeys, accKey)
 	}
 
-	tx, err := transactions.NewCreateAccount(signer, accKeys, nil)
-	if err != nil {
-		return nil, flow.EmptyID, err
-	}
-
-	tx, err = f.prepareTransaction(ctx, tx, signer)
+	tx, err := f.templateTransaction(ctx, signer, func() (*transactions.Transaction, error) {
+		return transactions.NewCreateAccount(signer, accKeys, nil)
+	})
 	if err != nil {
 		return nil, flow.EmptyID, err
 	}
@@ -194,10 +287,7 @@ func (f *Flowkit) CreateAccount(
 		return nil, flow.EmptyID, errors.Wrap(err, "account creation transaction failed")
 	}
 
-	f.logger.StartProgress("Waiting for transaction to be sealed...")
-	defer f.logger.StopProgress()
-
-	result, err := f.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+	result, err := f.waitFor(ctx, sentTx.ID(), tracking)
 	if err != nil {
 		return nil, flow.EmptyID, err
 	}
@@ -212,6 +302,11 @@ func (f *Flowkit) CreateAccount(
 		return nil, flow.EmptyID, fmt.Errorf("new account address couldn't be fetched")
 	}
 
+	// the account state is only available once the transaction is sealed
+	if result.Status < flow.TransactionStatusSealed {
+		return &flow.Account{Address: *newAccountAddress[0], Keys: accKeys}, sentTx.ID(), nil
+	}
+
 	account, err := f.gateway.GetAccount(ctx, *newAccountAddress[0]) // we know it's the only and first event
 	if err != nil {
 		return nil, flow.EmptyID, err
@@ -220,6 +315,46 @@ func (f *Flowkit) CreateAccount(
 	return account, sentTx.ID(), nil
 }
 
+// templateTransaction creates the transaction from a template with the compute limit set on flowkit,
+// and prepares it for sending.
+func (f *Flowkit) templateTransaction(
+	ctx context.Context,
+	account *accounts.Account,
+	newTransaction func() (*transactions.Transaction, error),
+) (*transactions.Transaction, error) {
+	limit := uint64(flow.DefaultTransactionGasLimit)
+	if f.computeLimit != nil {
+		limit = *f.computeLimit
+	}
+
+	if limit == 0 {
+		roles := transactions.AddressesRoles{
+			Proposer:    account.Address,
+			Payer:       account.Address,
+			Authorizers: []flow.Address{account.Address},
+		}
+
+		var err error
+		limit, err = f.estimateComputeLimit(ctx, roles, func(sim *Flowkit) (*transactions.Transaction, error) {
+			tx, err := newTransaction()
+			if err != nil {
+				return nil, err
+			}
+			return sim.prepareTransaction(ctx, tx.SetComputeLimit(maxComputeLimit), account)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := newTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.prepareTransaction(ctx, tx.SetComputeLimit(limit), account)
+}
+
 // prepareTransaction prepares transaction for sending with data from network
 func (f *Flowkit) prepareTransaction(
 	ctx context.Context,
@@ -259,45 +394,128 @@ func UpdateExistingContract(updateExisting bool) UpdateContract {
 	}
 }
 
+// ValidatedUpdateContract returns an UpdateContract which validates the update with the Cadence contract
+// update rules and leaves the decision to the verdict function based on the found violations.
+//
+// The policy doesn't know the account of the contract, so imports of all the contracts from an address
+// are reported as violations. Contracts that can't be parsed are not updated.
+func ValidatedUpdateContract(verdict func(violations []project.UpdateViolation) bool) UpdateContract {
+	return func(existing []byte, new []byte) bool {
+		program, err := project.NewProgram(new, nil, "")
+		if err != nil {
+			return false
+		}
+		name, err := program.Name()
+		if err != nil {
+			return false
+		}
+
+		violations, err := project.ValidateContractUpdate(flow.EmptyAddress, name, existing, new, nil)
+		if err != nil {
+			return false
+		}
+		return verdict(violations)
+	}
+}
+
+// UpdateCompatibleContract returns an UpdateContract which only updates contracts without update rule violations.
+func UpdateCompatibleContract() UpdateContract {
+	return ValidatedUpdateContract(func(violations []project.UpdateViolation) bool {
+		return len(violations) == 0
+	})
+}
+
+// ContractUpdateValidationError is returned when the contract update violates the Cadence contract update rules.
+type ContractUpdateValidationError struct {
+	Contract   string
+	Violations []project.UpdateViolation
+}
+
+func (e *ContractUpdateValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("contract %s can not be updated:", e.Contract))
+	for _, violation := range e.Violations {
+		b.WriteString(fmt.Sprintf("\n  %s", violation))
+	}
+	return b.String()
+}
+
+// validateContractUpdate validates the update of the contract on the account and returns a
+// ContractUpdateValidationError if the update violates the contract update rules.
+func (f *Flowkit) validateContractUpdate(
+	ctx context.Context,
+	address flow.Address,
+	name string,
+	existing []byte,
+	new []byte,
+) error {
+	violations, err := project.ValidateContractUpdate(
+		address,
+		name,
+		existing,
+		new,
+		func(address flow.Address) ([]string, error) {
+			account, err := f.gateway.GetAccount(ctx, address)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get account %s: %w", address, err)
+			}
+			return maps.Keys(account.Contracts), nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &ContractUpdateValidationError{Contract: name, Violations: violations}
+	}
+	return nil
+}
+
 // AddContract to the Flow account provided and return the transaction ID.
 //
 // If the contract already exists on the account the operation will fail and error will be returned.
 // Use UpdateExistingContract(bool) to define whether a contract should be updated or not, or you can also
 // define a custom UpdateContract function which returns bool indicating whether a contract should be updated or not.
+// If update validation is enabled with SetValidateContractUpdates the update is validated before it's sent.
 func (f *Flowkit) AddContract(
 	ctx context.Context,
 	account *accounts.Account,
 	contract Script,
 	update UpdateContract,
+) (flow.Identifier, bool, error) {
+	return f.addContract(ctx, account, contract, update, untilSealed)
+}
+
+// AddContractAndTrack adds the contract like AddContract, but only waits until the transaction reaches the
+// provided status. The callback is called with every status transition and can be nil.
+func (f *Flowkit) AddContractAndTrack(
+	ctx context.Context,
+	account *accounts.Account,
+	contract Script,
+	update UpdateContract,
+	until flow.TransactionStatus,
+	callback TransactionStatusCallback,
+) (flow.Identifier, bool, error) {
+	return f.addContract(ctx, account, contract, update, transactionTracking{until: until, callback: callback})
+}
+
+func (f *Flowkit) addContract(
+	ctx context.Context,
+	account *accounts.Account,
+	contract Script,
+	update UpdateContract,
+	tracking transactionTracking,
 ) (flow.Identifier, bool, error) {
 	state, err := f.State()
 	if err != nil {
 		return flow.EmptyID, false, err
 	}
 
-	program, err := project.NewProgram(contract.Code, contract.Args, contract.Location)
+	program, err := f.resolveContract(state, contract)
 	if err != nil {
 		return flow.EmptyID, false, err
 	}
 
-	if program.HasImports() {
-		contracts, err := state.DeploymentContractsByNetwork(f.network)
-		if err != nil {
-			return flow.EmptyID, false, err
-		}
-
-		importReplacer := project.NewImportReplacer(
-			contracts,
-			state.AliasesForNetwork(f.network),
-			state.CanonicalContractMapping(),
-		)
-
-		program, err = importReplacer.Replace(program)
-		if err != nil {
-			return flow.EmptyID, false, err
-		}
-	}
-
 	name, err := program.Name()
 	if err != nil {
 		return flow.EmptyID, false, err
@@ -320,52 +538,20 @@ func (f *Flowkit) AddContract(
 
 	updateExisting := update(existingContract, program.Code())
 
-	var tx *transactions.Transaction
-	if exists {
-		if !updateExisting {
-			return flow.EmptyID, false, fmt.Errorf("contract %s exists in account %s", name, account.Name)
-		}
+	if exists && !updateExisting {
+		return flow.EmptyID, false, fmt.Errorf("contract %s exists in account %s", name, account.Name)
+	}
 
-		tx, err = transactions.NewUpdateAccountContract(account, name, program.Code())
-		if err != nil {
-			return flow.EmptyID, false, err
-		}
-	} else {
-		tx, err = transactions.NewAddAccountContract(
-			account,
-			name,
-			program.Code(),
-			contract.Args,
-		)
+	if exists && f.validateUpdates {
+		err := f.validateContractUpdate(ctx, account.Address, name, existingContract, program.Code())
 		if err != nil {
 			return flow.EmptyID, false, err
 		}
 	}
 
-	tx, err = f.prepareTransaction(ctx, tx, account)
-	if err != nil {
-		return flow.EmptyID, false, err
-	}
-
-	// send transaction with contract
-	sentTx, err := f.gateway.SendSignedTransaction(ctx, tx.FlowTransaction())
-	if err != nil {
-		return tx.FlowTransaction().ID(), false, fmt.Errorf("failed to send transaction to deploy a contract: %w", err)
-	}
-
-	if exists {
-		f.logger.StartProgress(fmt.Sprintf("Contract '%s' updating on the account '%s'.", name, account.Address))
-	} else {
-		f.logger.StartProgress(fmt.Sprintf("Contract '%s' deploying on the account '%s'.", name, account.Address))
-	}
-
-	// we wait for transaction to be sealed
-	trx, err := f.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+	txID, err := f.sendContract(ctx, account, name, program.Code(), contract.Args, exists, nil, tracking)
 	if err != nil {
-		return tx.FlowTransaction().ID(), false, err
-	}
-	if trx.Error != nil {
-		return tx.FlowTransaction().ID(), false, trx.Error
+		return txID, false, err
 	}
 
 	d := state.Deployments().ByAccountAndNetwork(account.Name, f.network.Name)
@@ -392,7 +578,114 @@ func (f *Flowkit) AddContract(
 		})
 	}
 
-	return sentTx.ID(), updateExisting, err
+	return txID, updateExisting, err
+}
+
+// resolveContract parses the contract and replaces its imports with the addresses the imported
+// contracts are deployed to on the network.
+func (f *Flowkit) resolveContract(state *State, contract Script) (*project.Program, error) {
+	program, err := project.NewProgram(contract.Code, contract.Args, contract.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	if !program.HasImports() {
+		return program, nil
+	}
+
+	contracts, err := state.DeploymentContractsByNetwork(f.network)
+	if err != nil {
+		return nil, err
+	}
+
+	importReplacer := project.NewImportReplacer(
+		contracts,
+		state.AliasesForNetwork(f.network),
+		state.CanonicalContractMapping(),
+	)
+
+	return importReplacer.Replace(program)
+}
+
+// sendContract adds the contract to the account, or updates it if it exists, and tracks the transaction as defined by the tracking.
+// The sent callback is called with the transaction ID before the transaction is sent, and the transaction isn't sent
+// if it returns an error. The callback can be nil.
+func (f *Flowkit) sendContract(
+	ctx context.Context,
+	account *accounts.Account,
+	name string,
+	code []byte,
+	args []cadence.Value,
+	exists bool,
+	sent func(flow.Identifier) error,
+	tracking transactionTracking,
+) (flow.Identifier, error) {
+	return f.sendContracts(ctx, account, []transactions.AccountContract{{
+		Name:   name,
+		Source: code,
+		Args:   args,
+		Update: exists,
+	}}, sent, tracking)
+}
+
+// sendContracts adds or updates the contracts on the account with a single transaction and tracks it as defined by the tracking.
+// The sent callback is called with the transaction ID before the transaction is sent, and the transaction isn't sent
+// if it returns an error. The callback can be nil.
+func (f *Flowkit) sendContracts(
+	ctx context.Context,
+	account *accounts.Account,
+	contracts []transactions.AccountContract,
+	sent func(flow.Identifier) error,
+	tracking transactionTracking,
+) (flow.Identifier, error) {
+	tx, err := f.templateTransaction(ctx, account, func() (*transactions.Transaction, error) {
+		if len(contracts) > 1 {
+			return transactions.NewDeployAccountContracts(account, contracts)
+		}
+		if contracts[0].Update {
+			return transactions.NewUpdateAccountContract(account, contracts[0].Name, contracts[0].Source)
+		}
+		return transactions.NewAddAccountContract(account, contracts[0].Name, contracts[0].Source, contracts[0].Args)
+	})
+	if err != nil {
+		return flow.EmptyID, err
+	}
+
+	// the ID is reported before sending, so the transaction can be checked if the send fails after it was accepted
+	if sent != nil {
+		if err := sent(tx.FlowTransaction().ID()); err != nil {
+			return flow.EmptyID, err
+		}
+	}
+
+	// send transaction with contract
+	sentTx, err := f.gateway.SendSignedTransaction(ctx, tx.FlowTransaction())
+	if err != nil {
+		return tx.FlowTransaction().ID(), fmt.Errorf("failed to send transaction to deploy a contract: %w", err)
+	}
+
+	switch {
+	case len(contracts) > 1:
+		names := make([]string, len(contracts))
+		for i, contract := range contracts {
+			names[i] = contract.Name
+		}
+		f.logger.StartProgress(fmt.Sprintf("Contracts '%s' deploying on the account '%s'.", strings.Join(names, "', '"), account.Address))
+	case contracts[0].Update:
+		f.logger.StartProgress(fmt.Sprintf("Contract '%s' updating on the account '%s'.", contracts[0].Name, account.Address))
+	default:
+		f.logger.StartProgress(fmt.Sprintf("Contract '%s' deploying on the account '%s'.", contracts[0].Name, account.Address))
+	}
+
+	trx, err := f.waitFor(ctx, sentTx.ID(), tracking)
+	if err != nil {
+		return tx.FlowTransaction().ID(), err
+	}
+	if trx.Error != nil {
+		return tx.FlowTransaction().ID(), trx.Error
+	}
+
+	return sentTx.ID(), nil
 }
 
 // RemoveContract from the provided account by its name.
@@ -402,6 +695,27 @@ func (f *Flowkit) RemoveContract(
 	ctx context.Context,
 	account *accounts.Account,
 	contractName string,
+) (flow.Identifier, error) {
+	return f.removeContract(ctx, account, contractName, untilSealed)
+}
+
+// RemoveContractAndTrack removes the contract like RemoveContract, but only waits until the transaction reaches
+// the provided status. The callback is called with every status transition and can be nil.
+func (f *Flowkit) RemoveContractAndTrack(
+	ctx context.Context,
+	account *accounts.Account,
+	contractName string,
+	until flow.TransactionStatus,
+	callback TransactionStatusCallback,
+) (flow.Identifier, error) {
+	return f.removeContract(ctx, account, contractName, transactionTracking{until: until, callback: callback})
+}
+
+func (f *Flowkit) removeContract(
+	ctx context.Context,
+	account *accounts.Account,
+	contractName string,
+	tracking transactionTracking,
 ) (flow.Identifier, error) {
 	// check if contracts exists on the account
 	flowAcc, err := f.gateway.GetAccount(ctx, account.Address)
@@ -438,7 +752,7 @@ func (f *Flowkit) RemoveContract(
 		return flow.EmptyID, err
 	}
 
-	txr, err := f.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+	txr, err := f.waitFor(ctx, sentTx.ID(), tracking)
 	if err != nil {
 		return flow.EmptyID, err
 	}
@@ -446,8 +760,6 @@ func (f *Flowkit) RemoveContract(
 		return flow.EmptyID, txr.Error
 	}
 
-	f.logger.StopProgress()
-
 	return sentTx.ID(), nil
 }
 
@@ -584,6 +896,41 @@ func makeEventQueries(
 	return queries
 }
 
+// SubscribeBlocks streams the sealed blocks starting at the provided height, or at the latest sealed block if the height is zero.
+//
+// The subscription runs until the context is cancelled or an error is sent to the error channel, after which both channels are closed.
+func (f *Flowkit) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return f.gateway.SubscribeBlocks(ctx, startHeight)
+}
+
+// SubscribeBlockHeaders streams the sealed block headers starting at the provided height, or at the latest sealed block if the height is zero.
+func (f *Flowkit) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return f.gateway.SubscribeBlockHeaders(ctx, startHeight)
+}
+
+// SubscribeEvents streams the events matching the filter by event type, address or contract starting at the provided height,
+// or at the latest sealed block if the height is zero.
+//
+// Networks that can't stream are polled for new blocks instead, in that case filtering by address or contract fetches
+// all the events of every block and the filter can't be empty.
+func (f *Flowkit) SubscribeEvents(
+	ctx context.Context,
+	startHeight uint64,
+	filter flow.EventFilter,
+) (<-chan flow.BlockEvents, <-chan error, error) {
+	return f.gateway.SubscribeEvents(ctx, startHeight, filter)
+}
+
+// SubscribeAccountStatuses streams the core account events grouped by account starting at the provided height,
+// or at the latest sealed block if the height is zero. The filter can limit the event types and the accounts.
+func (f *Flowkit) SubscribeAccountStatuses(
+	ctx context.Context,
+	startHeight uint64,
+	filter flow.AccountStatusFilter,
+) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return f.gateway.SubscribeAccountStatuses(ctx, startHeight, filter)
+}
+
 // GenerateKey using the signature algorithm and optional seed. If seed is not provided a random safe seed will be generated.
 func (f *Flowkit) GenerateKey(
 	_ context.Context,
@@ -704,30 +1051,27 @@ func (f *Flowkit) derivePrivateKeyFromSeed(
 // Retrieve all the contracts for specified network, sort them for deployment deploy one by one and replace
 // the imports in the contract source, so it corresponds to the account name the contract was deployed to.
 // If contracts already exist use UpdateExistingContract(bool) to define whether a contract should be updated or not.
+//
+// If batch deployments are set the contracts are deployed by executing a deployment plan, so the contracts
+// deployed to the same account in a dependency level are sent with a single transaction.
 func (f *Flowkit) DeployProject(ctx context.Context, update UpdateContract) ([]*project.Contract, error) {
-	state, err := f.State()
-	if err != nil {
-		return nil, err
-	}
-
-	contracts, err := state.DeploymentContractsByNetwork(f.network)
-	if err != nil {
-		return nil, err
+	if f.batchDeployments {
+		return f.deployProjectPlan(ctx, update, false)
 	}
 
-	deployment, err := project.NewDeployment(contracts, state.AliasesForNetwork(f.network))
+	state, err := f.State()
 	if err != nil {
 		return nil, err
 	}
 
-	sorted, err := deployment.Sort()
+	sorted, err := f.sortedDeploymentContracts(state)
 	if err != nil {
 		return nil, err
 	}
 
 	// Early return if no contracts are configured for deployment
 	if len(sorted) == 0 {
-		f.logger.Info(fmt.Sprintf("\n%s No contracts configured for deployment on network '%s'.\n\nTo add deployments, use 'flow config add deployment'.\nIf you meant to deploy to a different network, use the --network flag (e.g., 'flow project deploy --network testnet').\n\nFor more details, see: https://developers.flow.com/build/tools/flow-cli/deployment/deploy-project-contracts", output.WarningEmoji(), f.network.Name))
+		f.logNoDeployments()
 		return sorted, nil
 	}
 
@@ -759,7 +1103,7 @@ func (f *Flowkit) DeployProject(ctx context.Context, update UpdateContract) ([]*
 			))
 			continue
 		} else if err != nil {
-			deployErr.add(contract, err, fmt.Sprintf("failed to deploy contract %s", contract.Name))
+			deployErr.add(contract.Name, err, fmt.Sprintf("failed to deploy contract %s", contract.Name))
 			continue
 		}
 
@@ -780,15 +1124,34 @@ func (f *Flowkit) DeployProject(ctx context.Context, update UpdateContract) ([]*
 	return sorted, nil
 }
 
+func (f *Flowkit) logNoDeployments() {
+	f.logger.Info(fmt.Sprintf("\n%s No contracts configured for deployment on network '%s'.\n\nTo add deployments, use 'flow config add deployment'.\nIf you meant to deploy to a different network, use the --network flag (e.g., 'flow project deploy --network testnet').\n\nFor more details, see: https://developers.flow.com/build/tools/flow-cli/deployment/deploy-project-contracts", output.WarningEmoji(), f.network.Name))
+}
+
+// sortedDeploymentContracts returns the contracts configured for deployment on the network in the deployment order.
+func (f *Flowkit) sortedDeploymentContracts(state *State) ([]*project.Contract, error) {
+	contracts, err := state.DeploymentContractsByNetwork(f.network)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := project.NewDeployment(contracts, state.AliasesForNetwork(f.network))
+	if err != nil {
+		return nil, err
+	}
+
+	return deployment.Sort()
+}
+
 type ProjectDeploymentError struct {
 	contracts map[string]error
 }
 
-func (d *ProjectDeploymentError) add(contract *project.Contract, err error, msg string) {
+func (d *ProjectDeploymentError) add(name string, err error, msg string) {
 	if d.contracts == nil {
 		d.contracts = make(map[string]error)
 	}
-	d.contracts[contract.Name] = fmt.Errorf("%s: %w", msg, err)
+	d.contracts[name] = fmt.Errorf("%s: %w", msg, err)
 }
 
 func (d *ProjectDeploymentError) Contracts() map[string]error {
@@ -820,6 +1183,16 @@ func (f *Flowkit) ExecuteScript(ctx context.Context, script Script, query Script
 		return nil, err
 	}
 
+	code, err := f.resolveScriptCode(state, script)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.executeScriptCode(ctx, code, script.Args, query)
+}
+
+// resolveScriptCode returns the script code with the imports replaced by the addresses of the contracts on the network.
+func (f *Flowkit) resolveScriptCode(state *State, script Script) ([]byte, error) {
 	program, err := project.NewProgram(script.Code, script.Args, script.Location)
 	if err != nil {
 		return nil, err
@@ -840,7 +1213,7 @@ func (f *Flowkit) ExecuteScript(ctx context.Context, script Script, query Script
 		if state == nil {
 			return nil, config.ErrDoesNotExist
 		}
-		if f.network == config.EmptyNetwork {
+		if f.network.IsEmpty() {
 			return nil, fmt.Errorf("missing network, specify which network to use to resolve imports in script code")
 		}
 		if script.Location == "" {
@@ -853,13 +1226,91 @@ func (f *Flowkit) ExecuteScript(ctx context.Context, script Script, query Script
 		}
 	}
 
+	return program.Code(), nil
+}
+
+func (f *Flowkit) executeScriptCode(ctx context.Context, code []byte, args []cadence.Value, query ScriptQuery) (cadence.Value, error) {
 	if query.Latest {
-		return f.gateway.ExecuteScript(ctx, program.Code(), script.Args)
+		return f.gateway.ExecuteScript(ctx, code, args)
 	} else if query.ID != flow.EmptyID {
-		return f.gateway.ExecuteScriptAtID(ctx, program.Code(), script.Args, query.ID)
+		return f.gateway.ExecuteScriptAtID(ctx, code, args, query.ID)
 	} else {
-		return f.gateway.ExecuteScriptAtHeight(ctx, program.Code(), script.Args, query.Height)
+		return f.gateway.ExecuteScriptAtHeight(ctx, code, args, query.Height)
+	}
+}
+
+// ExecuteScripts executes the batch of scripts concurrently and returns the results in the order of the scripts.
+//
+// The imports are resolved once for each unique script source. A script failing doesn't stop the batch,
+// its error is returned as part of its result. If the batch is pinned all the scripts are executed
+// at the same block height, so the results are consistent.
+func (f *Flowkit) ExecuteScripts(ctx context.Context, scripts []BatchScript, batch *ScriptBatch) ([]BatchScriptResult, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	if batch == nil { // if no batch options are passed, create default ones
+		batch = &ScriptBatch{Workers: 1}
 	}
+
+	if batch.Pinned && batch.Height == 0 {
+		block, err := f.gateway.GetLatestBlock(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the latest block to pin the scripts to: %w", err)
+		}
+		batch = &ScriptBatch{Workers: batch.Workers, Pinned: true, Height: block.Height}
+	}
+
+	type resolved struct {
+		code []byte
+		err  error
+	}
+
+	results := make([]BatchScriptResult, len(scripts))
+	codes := make([][]byte, len(scripts))
+	sources := make(map[string]resolved)
+	for i, s := range scripts {
+		key := s.Script.Location + "\x00" + string(s.Script.Code)
+		source, ok := sources[key]
+		if !ok {
+			source.code, source.err = f.resolveScriptCode(state, s.Script)
+			sources[key] = source
+		}
+
+		codes[i] = source.code
+		results[i].Err = source.err
+	}
+
+	workers := max(batch.Workers, 1)
+	jobChan := make(chan int, workers)
+
+	var wg sync.WaitGroup
+
+	// each worker writes only the results of the scripts it received, so the results need no locking
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobChan {
+				query := scripts[i].Query
+				if batch.Pinned {
+					query = ScriptQuery{Height: batch.Height}
+				}
+				results[i].Value, results[i].Err = f.executeScriptCode(ctx, codes[i], scripts[i].Script.Args, query)
+			}
+		}()
+	}
+
+	for i := range scripts {
+		if results[i].Err == nil {
+			jobChan <- i
+		}
+	}
+	close(jobChan)
+	wg.Wait()
+
+	return results, nil
 }
 
 // GetTransactionByID from the Flow network including the transaction result. Using the waitSeal we can wait for the transaction to be sealed.
@@ -877,11 +1328,11 @@ func (f *Flowkit) GetTransactionByID(
 	}
 
 	if waitSeal {
-		f.logger.StartProgress("Waiting for transaction to be sealed...")
-		defer f.logger.StopProgress()
+		result, err := f.waitForSeal(ctx, ID)
+		return tx, result, err
 	}
 
-	result, err := f.gateway.GetTransactionResult(ctx, ID, waitSeal)
+	result, err := f.gateway.GetTransactionResult(ctx, ID, false)
 	return tx, result, err
 }
 
@@ -941,6 +1392,32 @@ func (f *Flowkit) GetSystemTransactionWithID(
 	return tx, res, nil
 }
 
+// GetExecutionResult returns the execution result of the block with its chunks.
+func (f *Flowkit) GetExecutionResult(ctx context.Context, blockID flow.Identifier) (*ExecutionResult, error) {
+	f.logger.StartProgress("Fetching Execution Result...")
+	defer f.logger.StopProgress()
+
+	result, err := f.gateway.GetExecutionResultForBlockID(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution result for block %s: %w", blockID, err)
+	}
+
+	return NewExecutionResult(result), nil
+}
+
+// GetExecutionData returns the transactions, results, events and register updates of every chunk of the block.
+func (f *Flowkit) GetExecutionData(ctx context.Context, blockID flow.Identifier) (*ExecutionData, error) {
+	f.logger.StartProgress("Fetching Execution Data...")
+	defer f.logger.StopProgress()
+
+	data, err := f.gateway.GetExecutionDataByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution data for block %s: %w", blockID, err)
+	}
+
+	return NewExecutionData(data), nil
+}
+
 // BuildTransaction builds a new transaction type for later signing and submitting to the network.
 //
 // AddressesRoles type defines the address for each role (payer, proposer, authorizers) and the script defines the transaction content.
@@ -950,6 +1427,24 @@ func (f *Flowkit) BuildTransaction(
 	proposerKeyIndex uint32,
 	script Script,
 	gasLimit uint64,
+) (*transactions.Transaction, error) {
+	return f.buildTransaction(ctx, addresses, script, gasLimit, func(tx *transactions.Transaction) error {
+		proposerAccount, err := f.gateway.GetAccount(ctx, addresses.Proposer)
+		if err != nil {
+			return err
+		}
+
+		return tx.SetProposer(proposerAccount, proposerKeyIndex)
+	})
+}
+
+// buildTransaction builds a new transaction with the proposal key set by the provided function.
+func (f *Flowkit) buildTransaction(
+	ctx context.Context,
+	addresses transactions.AddressesRoles,
+	script Script,
+	gasLimit uint64,
+	setProposer func(*transactions.Transaction) error,
 ) (*transactions.Transaction, error) {
 	state, err := f.State()
 	if err != nil {
@@ -961,11 +1456,6 @@ func (f *Flowkit) BuildTransaction(
 		return nil, fmt.Errorf("failed to get latest sealed block: %w", err)
 	}
 
-	proposerAccount, err := f.gateway.GetAccount(ctx, addresses.Proposer)
-	if err != nil {
-		return nil, err
-	}
-
 	tx := transactions.New().
 		SetPayer(addresses.Payer).
 		SetComputeLimit(gasLimit).
@@ -977,7 +1467,7 @@ func (f *Flowkit) BuildTransaction(
 	}
 
 	if program.HasImports() {
-		if f.network == config.EmptyNetwork {
+		if f.network.IsEmpty() {
 			return nil, fmt.Errorf("missing network, specify which network to use to resolve imports in transaction code")
 		}
 		if script.Location == "" { // when used as lib with code we don't support imports
@@ -1001,7 +1491,7 @@ func (f *Flowkit) BuildTransaction(
 		}
 	}
 
-	if err := tx.SetProposer(proposerAccount, proposerKeyIndex); err != nil {
+	if err := setProposer(tx); err != nil {
 		return nil, err
 	}
 
@@ -1050,7 +1540,7 @@ func (f *Flowkit) SendSignedTransaction(
 		return nil, nil, err
 	}
 
-	res, err := f.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+	res, err := f.waitForSeal(ctx, sentTx.ID())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1060,51 +1550,154 @@ func (f *Flowkit) SendSignedTransaction(
 
 // SendTransaction will build and send a transaction to the Flow network, using the accounts provided for each role and
 // contain the script. Transaction as well as transaction result will be returned in case the transaction is successfully submitted.
+//
+// If the gas limit is zero the compute limit is estimated by executing the transaction against an emulator first.
+// If the proposer is the account of the proposal key pool, the transaction is proposed with a key leased from the pool.
 func (f *Flowkit) SendTransaction(
 	ctx context.Context,
 	accounts transactions.AccountRoles,
 	script Script,
 	gasLimit uint64,
 ) (*flow.Transaction, *flow.TransactionResult, error) {
-	tx, err := f.BuildTransaction(
-		ctx,
-		accounts.AddressRoles(),
-		accounts.Proposer.Key.Index(),
-		script,
-		gasLimit,
-	)
+	return f.SendTransactionAndTrack(ctx, accounts, script, gasLimit, flow.TransactionStatusSealed, nil)
+}
+
+// SendTransactionAndTrack builds and sends the transaction like SendTransaction, but only waits until the transaction
+// reaches the provided status. The callback is called with every status transition and can be nil.
+func (f *Flowkit) SendTransactionAndTrack(
+	ctx context.Context,
+	accounts transactions.AccountRoles,
+	script Script,
+	gasLimit uint64,
+	until flow.TransactionStatus,
+	callback TransactionStatusCallback,
+) (*flow.Transaction, *flow.TransactionResult, error) {
+	sentTx, proposalKey, err := f.submitTransaction(ctx, accounts, script, gasLimit)
 	if err != nil {
 		return nil, nil, err
 	}
+	res, err := f.waitFor(ctx, sentTx.ID(), transactionTracking{until: until, callback: callback})
+	if proposalKey != nil {
+		// the sequence number is unknown if the transaction couldn't be tracked
+		if err != nil || isSequenceNumberError(res.Error) {
+			f.keyPool.Invalidate(proposalKey)
+		} else {
+			f.keyPool.Release(proposalKey, true)
+		}
+	}
 
-	for _, signer := range accounts.Signers() {
-		err = tx.SetSigner(signer)
+	return sentTx, res, err
+}
+
+// submitTransaction builds, signs and sends the transaction without waiting for it. If the transaction was proposed
+// with a key leased from the proposal key pool, the key is returned and it must be released by the caller.
+func (f *Flowkit) submitTransaction(
+	ctx context.Context,
+	accounts transactions.AccountRoles,
+	script Script,
+	gasLimit uint64,
+) (*flow.Transaction, *ProposalKey, error) {
+	if gasLimit == 0 {
+		var err error
+		gasLimit, err = f.estimateComputeLimit(ctx, accounts.AddressRoles(), func(sim *Flowkit) (*transactions.Transaction, error) {
+			return sim.signedTransaction(ctx, accounts, script, maxComputeLimit, nil)
+		})
 		if err != nil {
 			return nil, nil, err
 		}
+	}
 
-		tx, err = tx.Sign()
+	var proposalKey *ProposalKey
+	if f.keyPool != nil && f.keyPool.Address() == accounts.Proposer.Address {
+		var err error
+		proposalKey, err = f.keyPool.Lease(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		accounts, err = f.keyPool.pooledRoles(accounts, proposalKey)
 		if err != nil {
+			f.keyPool.Release(proposalKey, false)
 			return nil, nil, err
 		}
 	}
 
+	tx, err := f.signedTransaction(ctx, accounts, script, gasLimit, proposalKey)
+	if err != nil {
+		f.releaseUnsentProposalKey(proposalKey, err)
+		return nil, nil, err
+	}
+
 	f.logger.Info(fmt.Sprintf("Transaction ID: %s", tx.FlowTransaction().ID()))
 	f.logger.StartProgress("Sending transaction...")
-	defer f.logger.StopProgress()
 
 	sentTx, err := f.gateway.SendSignedTransaction(ctx, tx.FlowTransaction())
 	if err != nil {
+		f.logger.StopProgress()
+		f.releaseUnsentProposalKey(proposalKey, err)
 		return nil, nil, err
 	}
 
-	f.logger.StopProgress()
-	f.logger.StartProgress("Waiting for transaction to be sealed...")
-	defer f.logger.StopProgress()
+	return sentTx, proposalKey, nil
+}
 
-	res, err := f.gateway.GetTransactionResult(ctx, sentTx.ID(), true)
+// releaseUnsentProposalKey returns the proposal key leased from the pool for a transaction that wasn't sent, if any.
+// The sequence number is fetched again the next time the key is leased if the transaction was rejected with
+// a sequence number mismatch.
+func (f *Flowkit) releaseUnsentProposalKey(key *ProposalKey, err error) {
+	if key == nil {
+		return
+	}
 
-	return sentTx, res, err
+	if isSequenceNumberError(err) {
+		f.keyPool.Invalidate(key)
+		return
+	}
+	f.keyPool.Release(key, false)
+}
+
+// signedTransaction builds the transaction and signs it with the signers of the account roles, proposing it with
+// the proposal key leased from the pool if provided.
+func (f *Flowkit) signedTransaction(
+	ctx context.Context,
+	accounts transactions.AccountRoles,
+	script Script,
+	gasLimit uint64,
+	proposalKey *ProposalKey,
+) (*transactions.Transaction, error) {
+	var tx *transactions.Transaction
+	var err error
+	if proposalKey != nil {
+		tx, err = f.buildTransaction(ctx, accounts.AddressRoles(), script, gasLimit, func(tx *transactions.Transaction) error {
+			tx.SetProposalKey(accounts.Proposer.Address, proposalKey.Index, proposalKey.SequenceNumber)
+			return nil
+		})
+	} else {
+		tx, err = f.BuildTransaction(
+			ctx,
+			accounts.AddressRoles(),
+			accounts.Proposer.Key.Index(),
+			script,
+			gasLimit,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, signer := range accounts.Signers() {
+		err = tx.SetSigner(signer)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err = tx.Sign()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
 }
 
 // ReplaceImportsInScript will replace the imports in the script code with the contracts from the network.
@@ -23,8 +23,13 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
@@ -32,6 +37,8 @@ import (
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/access/grpc"
 	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow-go/fvm/systemcontracts"
+	flowGo "github.com/onflow/flow-go/model/flow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -265,7 +272,7 @@ func TestAccounts(t *testing.T) {
 	})
 }
 
-func setupIntegration() (*State, Flowkit) {
+func setupIntegration(opts ...func(*gateway.EmulatorGateway)) (*State, Flowkit) {
 	readerWriter, _ := tests.ReaderWriter()
 	state, err := Init(readerWriter)
 	if err != nil {
@@ -277,13 +284,14 @@ func setupIntegration() (*State, Flowkit) {
 
 	acc, _ := state.EmulatorServiceAccount()
 	pk, _ := acc.Key.PrivateKey()
+	opts = append([]func(*gateway.EmulatorGateway){gateway.WithEmulatorOptions(
+		emulator.WithTransactionExpiry(10),
+	)}, opts...)
 	gw := gateway.NewEmulatorGatewayWithOpts(&gateway.EmulatorKey{
 		PublicKey: (*pk).PublicKey(),
 		SigAlgo:   acc.Key.SigAlgo(),
 		HashAlgo:  acc.Key.HashAlgo(),
-	}, gateway.WithEmulatorOptions(
-		emulator.WithTransactionExpiry(10),
-	))
+	}, opts...)
 
 	flowkit := Flowkit{
 		state:   state,
@@ -559,6 +567,63 @@ func TestAccountsAddContract_Integration(t *testing.T) {
 		require.NotNil(t, deployments)
 	})
 
+	t.Run("Update Contract Validation", func(t *testing.T) {
+		t.Parallel()
+
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+		flowkit.SetValidateContractUpdates(true)
+
+		_, _, err := flowkit.AddContract(
+			ctx,
+			srvAcc,
+			Script{
+				Code:     tests.ContractSimpleWithArgs.Source,
+				Location: tests.ContractSimpleWithArgs.Filename,
+				Args:     []cadence.Value{cadence.UInt64(4)},
+			},
+			UpdateExistingContract(false),
+		)
+		require.NoError(t, err)
+
+		incompatible := Script{
+			Code: []byte(`
+				access(all) contract Simple {
+					access(all) let id: String
+					init(initId: String) {
+						self.id = initId
+					}
+				}
+			`),
+			Location: "contractArgsIncompatible.cdc",
+			Args:     []cadence.Value{cadence.String("4")},
+		}
+
+		_, _, err = flowkit.AddContract(ctx, srvAcc, incompatible, UpdateExistingContract(true))
+		var validationErr *ContractUpdateValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "Simple", validationErr.Contract)
+		require.Len(t, validationErr.Violations, 1)
+		assert.Equal(t, 3, validationErr.Violations[0].StartPos.Line)
+		assert.Contains(t, validationErr.Violations[0].Secondary, "expected `UInt64`, found `String`")
+
+		_, _, err = flowkit.AddContract(ctx, srvAcc, incompatible, UpdateCompatibleContract())
+		assert.EqualError(t, err, "contract Simple exists in account emulator-account")
+
+		acc, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		assert.Equal(t, tests.ContractSimpleWithArgs.Source, acc.Contracts["Simple"])
+
+		_, updated, err := flowkit.AddContract(
+			ctx,
+			srvAcc,
+			resourceToContract(tests.ContractSimpleWithArgsUpdated),
+			UpdateCompatibleContract(),
+		)
+		require.NoError(t, err)
+		assert.True(t, updated)
+	})
+
 	t.Run("Add Contract Invalid Same Content", func(t *testing.T) {
 		t.Parallel()
 
@@ -722,6 +787,213 @@ func TestAccountsRemoveContract_Integration(t *testing.T) {
 	})
 }
 
+func TestAccountKeys_Integration(t *testing.T) {
+	newKey := func(seed string) crypto.PrivateKey {
+		pkey, _ := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte(seed))
+		return pkey
+	}
+	publicKey := func(pkey crypto.PrivateKey, weight int) accounts.PublicKey {
+		return accounts.PublicKey{
+			Public:   pkey.PublicKey(),
+			Weight:   weight,
+			SigAlgo:  crypto.ECDSA_P256,
+			HashAlgo: crypto.SHA3_256,
+		}
+	}
+
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+
+	first := newKey("seedseedseedseedseedseedseedseedseedseedseedseedFirst")
+	flowAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{publicKey(first, 0)})
+	require.NoError(t, err)
+
+	acc := &accounts.Account{
+		Name:    "Alice",
+		Address: flowAcc.Address,
+		Key:     accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, first),
+	}
+	state.Accounts().AddOrUpdate(acc)
+
+	t.Run("Add and revoke key", func(t *testing.T) {
+		second := newKey("seedseedseedseedseedseedseedseedseedseedseedseedSecond")
+		key, _, err := flowkit.AddKey(ctx, acc, publicKey(second, 500))
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), key.Index)
+		assert.Equal(t, 500, key.Weight)
+		assert.True(t, key.PublicKey.Equals(second.PublicKey()))
+
+		_, err = flowkit.RevokeKey(ctx, acc, 1)
+		require.NoError(t, err)
+
+		flowAcc, err := flowkit.GetAccount(ctx, acc.Address)
+		require.NoError(t, err)
+		assert.True(t, flowAcc.Keys[1].Revoked)
+		assert.False(t, flowAcc.Keys[0].Revoked)
+
+		_, err = flowkit.RevokeKey(ctx, acc, 1)
+		assert.EqualError(t, err, fmt.Sprintf("key 1 of the account %s is already revoked", acc.Address))
+
+		_, err = flowkit.RevokeKey(ctx, acc, 5)
+		assert.EqualError(t, err, fmt.Sprintf("account %s has no key with index 5", acc.Address))
+
+		_, err = flowkit.RevokeKey(ctx, acc, 0)
+		assert.EqualError(t, err, fmt.Sprintf("can not revoke the key 0 used for signing by the account %s", acc.Address))
+	})
+
+	t.Run("Rotate key", func(t *testing.T) {
+		rotated := newKey("seedseedseedseedseedseedseedseedseedseedseedseedRotated")
+		_, _, err := flowkit.RotateKey(ctx, acc, accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, rotated), 500)
+		assert.EqualError(t, err, fmt.Sprintf("can not rotate the key 0, the keys of the account %s would only have weight 500 of the required 1000", acc.Address))
+
+		key, _, err := flowkit.RotateKey(ctx, acc, accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, rotated), 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), key.Index)
+		assert.Equal(t, flow.AccountKeyWeightThreshold, key.Weight)
+
+		// the account signs with the new key which is written back to the state
+		assert.Equal(t, uint32(2), acc.Key.Index())
+		stateAcc, err := state.Accounts().ByName("Alice")
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), stateAcc.Key.Index())
+		pkey, err := stateAcc.Key.PrivateKey()
+		require.NoError(t, err)
+		assert.True(t, (*pkey).Equals(rotated))
+
+		flowAcc, err := flowkit.GetAccount(ctx, acc.Address)
+		require.NoError(t, err)
+		assert.True(t, flowAcc.Keys[0].Revoked)
+
+		// the account can still send transactions signed with the new key
+		_, result, err := flowkit.SendTransaction(
+			ctx,
+			transactions.SingleAccountRole(*acc),
+			Script{Code: []byte(`transaction { prepare(signer: &Account) {} }`)},
+			flow.DefaultTransactionGasLimit,
+		)
+		require.NoError(t, err)
+		assert.NoError(t, result.Error)
+	})
+
+	t.Run("Fail to revoke key under the weight threshold", func(t *testing.T) {
+		half := newKey("seedseedseedseedseedseedseedseedseedseedseedseedHalf")
+		other := newKey("seedseedseedseedseedseedseedseedseedseedseedseedOther")
+		flowAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{publicKey(half, 500), publicKey(other, 500)})
+		require.NoError(t, err)
+
+		halfAcc := &accounts.Account{
+			Name:    "Bob",
+			Address: flowAcc.Address,
+			Key:     accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, half),
+		}
+
+		_, err = flowkit.RevokeKey(ctx, halfAcc, 1)
+		assert.EqualError(t, err, fmt.Sprintf("can not revoke the key 1, the remaining keys of the account %s would only have weight 500 of the required 1000", halfAcc.Address))
+	})
+}
+
+func TestProposalKeyPool_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+
+	pkey, _ := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("seedseedseedseedseedseedseedseedseedseedseedseedPool"))
+	flowAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{{
+		Public:   pkey.PublicKey(),
+		SigAlgo:  crypto.ECDSA_P256,
+		HashAlgo: crypto.SHA3_256,
+	}})
+	require.NoError(t, err)
+	acc := &accounts.Account{
+		Name:    "Pool",
+		Address: flowAcc.Address,
+		Key:     accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+	}
+
+	indexes, err := flowkit.AddProposalKeys(ctx, acc, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{1, 2, 3, 4}, indexes)
+
+	pool, err := NewProposalKeyPool(ctx, flowkit.Gateway(), acc, indexes)
+	require.NoError(t, err)
+	assert.Equal(t, 4, pool.Size())
+	flowkit.SetProposalKeyPool(pool)
+
+	script := Script{Code: []byte(`transaction { prepare(signer: &Account) {} }`)}
+
+	t.Run("Send transactions concurrently", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 12)
+		for i := 0; i < 12; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, result, err := flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*acc), script, flow.DefaultTransactionGasLimit)
+				if err == nil {
+					err = result.Error
+				}
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+
+		flowAcc, err := flowkit.GetAccount(ctx, acc.Address)
+		require.NoError(t, err)
+		used := uint64(0)
+		for _, index := range indexes {
+			used += flowAcc.Keys[index].SequenceNumber
+		}
+		assert.Equal(t, uint64(12), used)
+		// the account key wasn't used to propose
+		assert.Equal(t, uint64(1), flowAcc.Keys[0].SequenceNumber)
+	})
+
+	t.Run("Pipeline transactions asynchronously", func(t *testing.T) {
+		var handles []*TransactionHandle
+		for i := 0; i < 8; i++ {
+			handle, err := flowkit.SendTransactionAsync(ctx, transactions.SingleAccountRole(*acc), script, flow.DefaultTransactionGasLimit)
+			require.NoError(t, err)
+			handles = append(handles, handle)
+		}
+
+		for _, res := range WaitTransactions(ctx, handles, flow.TransactionStatusSealed) {
+			require.NoError(t, res.Err)
+			assert.NoError(t, res.Result.Error)
+		}
+	})
+
+	t.Run("Resync after sequence number mismatch", func(t *testing.T) {
+		keys := make([]*ProposalKey, pool.Size())
+		for i := range keys {
+			keys[i], err = pool.Lease(ctx)
+			require.NoError(t, err)
+			keys[i].SequenceNumber += 10
+		}
+		for _, key := range keys {
+			pool.Release(key, false)
+		}
+
+		_, result, err := flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*acc), script, flow.DefaultTransactionGasLimit)
+		if err == nil {
+			err = result.Error
+		}
+		assert.True(t, isSequenceNumberError(err), err)
+
+		// the key failing the transaction is resynced when it's leased again
+		for i := 0; i < pool.Size(); i++ {
+			_, result, err = flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*acc), script, flow.DefaultTransactionGasLimit)
+			if err == nil && result.Error == nil {
+				return
+			}
+		}
+		assert.Fail(t, "no transaction was sent with a resynced key")
+	})
+}
+
 func TestAccountsGet_Integration(t *testing.T) {
 	state, flowkit := setupIntegration()
 	srvAcc, _ := state.EmulatorServiceAccount()
@@ -1474,6 +1746,37 @@ func TestScripts(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("Execute Scripts", func(t *testing.T) {
+		_, flowkit, _ := setup()
+		g := mocks.NewGateway(t)
+		flowkit.gateway = g
+
+		block := tests.NewBlock()
+		g.On("GetLatestBlock", ctx).Return(block, nil).Once()
+		for _, name := range []string{"Foo", "Bar"} {
+			g.On("ExecuteScriptAtHeight", ctx, mock.Anything, []cadence.Value{cadence.String(name)}, block.Height).
+				Return(cadence.String("Hello "+name), nil).
+				Once()
+		}
+
+		scripts := []BatchScript{
+			{Script: Script{Code: tests.ScriptArgString.Source, Args: []cadence.Value{cadence.String("Foo")}}, Query: LatestScriptQuery},
+			{Script: Script{Code: tests.ScriptImport.Source}, Query: LatestScriptQuery},
+			{Script: Script{Code: tests.ScriptArgString.Source, Args: []cadence.Value{cadence.String("Bar")}}, Query: ScriptQuery{Height: 1}},
+		}
+
+		results, err := flowkit.ExecuteScripts(ctx, scripts, &ScriptBatch{Workers: 2, Pinned: true})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		assert.Equal(t, cadence.String("Hello Foo"), results[0].Value)
+		assert.NoError(t, results[0].Err)
+		assert.EqualError(t, results[1].Err, "resolving imports in scripts not supported")
+		assert.Nil(t, results[1].Value)
+		assert.Equal(t, cadence.String("Hello Bar"), results[2].Value)
+		assert.NoError(t, results[2].Err)
+	})
 }
 
 func TestScripts_Integration(t *testing.T) {
@@ -1552,6 +1855,22 @@ func TestScripts_Integration(t *testing.T) {
 		)
 		assert.NoError(t, err)
 		assert.Equal(t, res.String(), "\"Hello Hello, World!\"")
+
+		script := Script{Code: tests.ScriptImport.Source, Location: tests.ScriptImport.Filename}
+		results, err := flowkit.ExecuteScripts(
+			ctx,
+			[]BatchScript{
+				{Script: script, Query: LatestScriptQuery},
+				{Script: Script{Code: tests.ScriptWithError.Source, Args: []cadence.Value{cadence.String("Foo")}}, Query: LatestScriptQuery},
+				{Script: script, Query: LatestScriptQuery},
+			},
+			&ScriptBatch{Workers: 2, Pinned: true},
+		)
+		assert.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, "\"Hello Hello, World!\"", results[0].Value.String())
+		assert.ErrorContains(t, results[1].Err, "cannot find type in this scope")
+		assert.Equal(t, "\"Hello Hello, World!\"", results[2].Value.String())
 	})
 
 	t.Run("Execute Script Invalid", func(t *testing.T) {
@@ -2260,3 +2579,875 @@ func Test_BlockQuery(t *testing.T) {
 	_, err = NewBlockQuery("invalid")
 	assert.EqualError(t, err, "invalid query: invalid, valid are: \"latest\", block height or block ID")
 }
+
+func TestRecordReplay_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+	keys := []accounts.PublicKey{{
+		Public:   tests.PubKeys()[0],
+		Weight:   flow.AccountKeyWeightThreshold,
+		SigAlgo:  tests.SigAlgos()[0],
+		HashAlgo: tests.HashAlgos()[0],
+	}}
+	script := Script{Code: tests.ScriptArgString.Source, Args: []cadence.Value{cadence.String("Foo")}}
+
+	// record the session against the emulator
+	recorder := gateway.NewRecordingGateway(flowkit.gateway)
+	flowkit.gateway = recorder
+
+	recordedAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+	require.NoError(t, err)
+	recordedRes, err := flowkit.ExecuteScript(ctx, script, LatestScriptQuery)
+	require.NoError(t, err)
+
+	fixturePath := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, recorder.Save(fixturePath))
+
+	// replay the session without the emulator
+	fixture, err := gateway.LoadFixture(fixturePath)
+	require.NoError(t, err)
+	replay := gateway.NewReplayGateway(fixture, gateway.ReplayStrict)
+	flowkit.gateway = replay
+
+	acc, ID, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+	require.NoError(t, err)
+	assert.NotEqual(t, flow.EmptyID, ID)
+	assert.Equal(t, recordedAcc.Address, acc.Address)
+	assert.Equal(t, recordedAcc.Keys[0].PublicKey, acc.Keys[0].PublicKey)
+
+	res, err := flowkit.ExecuteScript(ctx, script, LatestScriptQuery)
+	require.NoError(t, err)
+	assert.Equal(t, recordedRes, res)
+	assert.Empty(t, replay.Unused())
+}
+
+func TestSubscriptions_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+	keys := []accounts.PublicKey{{
+		Public:   tests.PubKeys()[0],
+		Weight:   flow.AccountKeyWeightThreshold,
+		SigAlgo:  tests.SigAlgos()[0],
+		HashAlgo: tests.HashAlgos()[0],
+	}}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	latest, err := flowkit.GetBlock(ctx, LatestBlockQuery)
+	require.NoError(t, err)
+
+	blocks, blockErrs, err := flowkit.SubscribeBlocks(ctx, 0)
+	require.NoError(t, err)
+	headers, headerErrs, err := flowkit.SubscribeBlockHeaders(ctx, latest.Height+1)
+	require.NoError(t, err)
+	events, eventErrs, err := flowkit.SubscribeEvents(ctx, latest.Height+1, flow.EventFilter{EventTypes: []string{flow.EventAccountCreated}})
+	require.NoError(t, err)
+	statuses, statusErrs, err := flowkit.SubscribeAccountStatuses(ctx, latest.Height+1, flow.AccountStatusFilter{})
+	require.NoError(t, err)
+	flowToken := fmt.Sprintf("A.%s.FlowToken", systemcontracts.SystemContractsForChain(flowGo.Emulator).FlowToken.Address.Hex())
+	tokenEvents, tokenEventErrs, err := flowkit.SubscribeEvents(ctx, latest.Height+1, flow.EventFilter{Contracts: []string{flowToken}})
+	require.NoError(t, err)
+
+	// the blocks subscription starts at the latest block
+	select {
+	case block := <-blocks:
+		assert.Equal(t, latest.ID, block.ID)
+	case err := <-blockErrs:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no block received")
+	}
+
+	acc, _, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+	require.NoError(t, err)
+
+	// the following subscriptions are notified of the committed block
+	select {
+	case block := <-blocks:
+		assert.Equal(t, latest.Height+1, block.Height)
+	case err := <-blockErrs:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no block received")
+	}
+
+	select {
+	case header := <-headers:
+		assert.Equal(t, latest.Height+1, header.Height)
+	case err := <-headerErrs:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no block header received")
+	}
+
+	select {
+	case blockEvents := <-events:
+		require.Len(t, blockEvents.Events, 1)
+		assert.Equal(t, flow.EventAccountCreated, blockEvents.Events[0].Type)
+	case err := <-eventErrs:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no events received")
+	}
+
+	// the events of a contract are filtered from all the events of the block
+	select {
+	case blockEvents := <-tokenEvents:
+		require.NotEmpty(t, blockEvents.Events)
+		for _, event := range blockEvents.Events {
+			assert.True(t, strings.HasPrefix(event.Type, flowToken+"."), event.Type)
+		}
+	case err := <-tokenEventErrs:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no contract events received")
+	}
+
+	select {
+	case status := <-statuses:
+		addresses := make([]flow.Address, 0, len(status.Results))
+		for _, r := range status.Results {
+			addresses = append(addresses, r.Address)
+		}
+		assert.Contains(t, addresses, acc.Address)
+	case err := <-statusErrs:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no account status received")
+	}
+
+	// cancelling the context closes the subscriptions
+	cancel()
+	for range blocks {
+	}
+	_, ok := <-blockErrs
+	assert.False(t, ok)
+}
+
+func TestEmulatorSnapshots_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+	keys := []accounts.PublicKey{{
+		Public:   tests.PubKeys()[0],
+		Weight:   flow.AccountKeyWeightThreshold,
+		SigAlgo:  tests.SigAlgos()[0],
+		HashAlgo: tests.HashAlgos()[0],
+	}}
+
+	snapshotter, ok := flowkit.Gateway().(gateway.Snapshotter)
+	require.True(t, ok)
+
+	fixtureAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+	require.NoError(t, err)
+	require.NoError(t, snapshotter.CreateSnapshot("fixture"))
+
+	// the snapshot can be loaded repeatedly with the same state
+	for i := 0; i < 2; i++ {
+		acc, _, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+		require.NoError(t, err)
+
+		require.NoError(t, snapshotter.LoadSnapshot("fixture"))
+
+		_, err = flowkit.GetAccount(ctx, acc.Address)
+		assert.Error(t, err)
+		_, err = flowkit.GetAccount(ctx, fixtureAcc.Address)
+		assert.NoError(t, err)
+	}
+
+	require.NoError(t, snapshotter.CreateSnapshot("other"))
+	require.NoError(t, snapshotter.CreateSnapshot("other"))
+	names, err := snapshotter.Snapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fixture", "other"}, names)
+
+	require.NoError(t, snapshotter.DeleteSnapshot("fixture"))
+	names, err = snapshotter.Snapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, names)
+
+	assert.EqualError(t, snapshotter.LoadSnapshot("fixture"), "snapshot fixture does not exist")
+	assert.EqualError(t, snapshotter.DeleteSnapshot("fixture"), "snapshot fixture does not exist")
+	assert.ErrorContains(t, snapshotter.CreateSnapshot("../fixture"), "invalid snapshot name")
+}
+
+func TestEmulatorFileSnapshots_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+	pk, _ := srvAcc.Key.PrivateKey()
+	key := &gateway.EmulatorKey{
+		PublicKey: (*pk).PublicKey(),
+		SigAlgo:   srvAcc.Key.SigAlgo(),
+		HashAlgo:  srvAcc.Key.HashAlgo(),
+	}
+	keys := []accounts.PublicKey{{
+		Public:   tests.PubKeys()[0],
+		Weight:   flow.AccountKeyWeightThreshold,
+		SigAlgo:  tests.SigAlgos()[0],
+		HashAlgo: tests.HashAlgos()[0],
+	}}
+	conf := config.Emulator{
+		Name:           "persistent",
+		ServiceAccount: "emulator-account",
+		Storage:        t.TempDir(),
+	}
+	storedFiles := func() []string {
+		entries, err := os.ReadDir(conf.Storage)
+		require.NoError(t, err)
+		var files []string
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), "snapshot_") {
+				files = append(files, entry.Name())
+			}
+		}
+		return files
+	}
+
+	gw, err := gateway.NewEmulatorGatewayFromConfig(key, conf)
+	require.NoError(t, err)
+	flowkit.gateway = gw
+
+	fixtureAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+	require.NoError(t, err)
+	require.NoError(t, gw.CreateSnapshot("fixture"))
+
+	// every load overwrites the same working copy of the snapshot
+	for i := 0; i < 3; i++ {
+		acc, _, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+		require.NoError(t, err)
+
+		require.NoError(t, gw.LoadSnapshot("fixture"))
+
+		_, err = flowkit.GetAccount(ctx, acc.Address)
+		assert.Error(t, err)
+		_, err = flowkit.GetAccount(ctx, fixtureAcc.Address)
+		assert.NoError(t, err)
+	}
+	assert.ElementsMatch(t, []string{"snapshot_fixture", "snapshot_flowkit.working"}, storedFiles())
+
+	require.NoError(t, gw.CreateSnapshot("other"))
+	require.NoError(t, gw.CreateSnapshot("other"))
+	require.NoError(t, gw.Close())
+
+	// the snapshots are listed from the storage directory
+	gw, err = gateway.NewEmulatorGatewayFromConfig(key, conf)
+	require.NoError(t, err)
+	defer gw.Close()
+	names, err := gw.Snapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fixture", "other"}, names)
+
+	require.NoError(t, gw.DeleteSnapshot("fixture"))
+	assert.ElementsMatch(t, []string{"snapshot_other", "snapshot_flowkit.working"}, storedFiles())
+	assert.EqualError(t, gw.LoadSnapshot("fixture"), "snapshot fixture does not exist")
+}
+
+func TestEmulatorManualMining_Integration(t *testing.T) {
+	script := Script{Code: tests.TransactionSimple.Source, Location: tests.TransactionSimple.Filename}
+
+	t.Run("Wait for seal commits the pending block", func(t *testing.T) {
+		state, flowkit := setupIntegration(gateway.WithManualMining())
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		_, result, err := flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+	})
+
+	t.Run("Track transaction status", func(t *testing.T) {
+		state, flowkit := setupIntegration(gateway.WithManualMining())
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		var updates []TransactionStatusUpdate
+		tx, result, err := flowkit.SendTransactionAndTrack(
+			ctx,
+			transactions.SingleAccountRole(*srvAcc),
+			script,
+			flow.DefaultTransactionGasLimit,
+			flow.TransactionStatusSealed,
+			func(update TransactionStatusUpdate) {
+				updates = append(updates, update)
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+
+		require.Len(t, updates, 2)
+		assert.Equal(t, flow.TransactionStatusPending, updates[0].Status)
+		assert.Equal(t, flow.TransactionStatusSealed, updates[1].Status)
+		assert.Equal(t, tx.ID(), updates[1].TransactionID)
+		assert.Equal(t, result, updates[1].Result)
+		assert.False(t, updates[1].Timestamp.Before(updates[0].Timestamp))
+	})
+
+	t.Run("Track account and contract transactions", func(t *testing.T) {
+		state, flowkit := setupIntegration(gateway.WithManualMining())
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		var statuses []flow.TransactionStatus
+		collect := func(update TransactionStatusUpdate) {
+			statuses = append(statuses, update.Status)
+		}
+		pkey := tests.PubKeys()[0]
+		keys := []accounts.PublicKey{{Public: pkey, SigAlgo: crypto.ECDSA_P256, HashAlgo: crypto.SHA3_256}}
+
+		_, _, err := flowkit.CreateAccountAndTrack(ctx, srvAcc, keys, flow.TransactionStatusFinalized, collect)
+		assert.EqualError(t, err, "can not track account creation until status FINALIZED, the account is known once executed")
+
+		account, _, err := flowkit.CreateAccountAndTrack(ctx, srvAcc, keys, flow.TransactionStatusExecuted, collect)
+		require.NoError(t, err)
+		assert.Equal(t, pkey, account.Keys[0].PublicKey)
+		assert.Equal(t, []flow.TransactionStatus{flow.TransactionStatusPending, flow.TransactionStatusSealed}, statuses)
+
+		statuses = nil
+		_, _, err = flowkit.AddContractAndTrack(
+			ctx,
+			srvAcc,
+			resourceToContract(tests.ContractSimple),
+			UpdateExistingContract(false),
+			flow.TransactionStatusExecuted,
+			collect,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []flow.TransactionStatus{flow.TransactionStatusPending, flow.TransactionStatusSealed}, statuses)
+
+		statuses = nil
+		_, err = flowkit.RemoveContractAndTrack(ctx, srvAcc, tests.ContractSimple.Name, flow.TransactionStatusExecuted, collect)
+		require.NoError(t, err)
+		assert.Equal(t, []flow.TransactionStatus{flow.TransactionStatusPending, flow.TransactionStatusSealed}, statuses)
+
+		acc, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		assert.NotContains(t, acc.Contracts, tests.ContractSimple.Name)
+	})
+
+	t.Run("Wait for seal through gateway decorators", func(t *testing.T) {
+		state, flowkit := setupIntegration(gateway.WithManualMining())
+		srvAcc, _ := state.EmulatorServiceAccount()
+		flowkit.gateway = gateway.NewRecordingGateway(
+			gateway.NewRetryGateway(flowkit.gateway, gateway.NewRetryPolicy(config.NetworkRetry{MaxAttempts: 1})),
+		)
+
+		miner, ok := gateway.As[gateway.ManualMiner](flowkit.gateway)
+		require.True(t, ok)
+		assert.True(t, miner.ManualMining())
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		_, result, err := flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+	})
+
+	t.Run("Batch transactions in a block", func(t *testing.T) {
+		state, flowkit := setupIntegration(gateway.WithManualMining())
+		srvAcc, _ := state.EmulatorServiceAccount()
+		gw := flowkit.Gateway().(*gateway.EmulatorGateway)
+
+		var sent []*flow.Transaction
+		for i := uint64(0); i < 2; i++ {
+			tx, err := flowkit.BuildTransaction(
+				ctx,
+				transactions.AddressesRoles{Proposer: srvAcc.Address, Payer: srvAcc.Address},
+				srvAcc.Key.Index(),
+				script,
+				flow.DefaultTransactionGasLimit,
+			)
+			require.NoError(t, err)
+
+			// the sequence number isn't incremented on chain until the block is committed
+			proposal := tx.FlowTransaction().ProposalKey
+			tx.FlowTransaction().SetProposalKey(proposal.Address, proposal.KeyIndex, proposal.SequenceNumber+i)
+			require.NoError(t, tx.SetSigner(srvAcc))
+			tx, err = tx.Sign()
+			require.NoError(t, err)
+
+			flowTx, err := gw.SendSignedTransaction(ctx, tx.FlowTransaction())
+			require.NoError(t, err)
+			sent = append(sent, flowTx)
+		}
+
+		result, err := gw.GetTransactionResult(ctx, sent[0].ID(), false)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusPending, result.Status)
+
+		result, err = gw.ExecuteNextTransaction()
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusExecuted, result.Status)
+		assert.Equal(t, sent[0].ID(), result.TransactionID)
+
+		block, err := gw.CommitBlock()
+		require.NoError(t, err)
+
+		for _, tx := range sent {
+			result, err := gw.GetTransactionResult(ctx, tx.ID(), false)
+			require.NoError(t, err)
+			assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+			assert.Equal(t, block.ID, result.BlockID)
+		}
+	})
+
+	t.Run("Commit block after executing all transactions", func(t *testing.T) {
+		state, flowkit := setupIntegration(gateway.WithManualMining())
+		srvAcc, _ := state.EmulatorServiceAccount()
+		gw := flowkit.Gateway().(*gateway.EmulatorGateway)
+
+		tx, err := flowkit.BuildTransaction(
+			ctx,
+			transactions.AddressesRoles{Proposer: srvAcc.Address, Payer: srvAcc.Address},
+			srvAcc.Key.Index(),
+			script,
+			flow.DefaultTransactionGasLimit,
+		)
+		require.NoError(t, err)
+		require.NoError(t, tx.SetSigner(srvAcc))
+		tx, err = tx.Sign()
+		require.NoError(t, err)
+
+		sent, err := gw.SendSignedTransaction(ctx, tx.FlowTransaction())
+		require.NoError(t, err)
+
+		_, err = gw.ExecuteNextTransaction()
+		require.NoError(t, err)
+
+		block, err := gw.CommitBlock()
+		require.NoError(t, err)
+
+		result, err := gw.GetTransactionResult(ctx, sent.ID(), false)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+		assert.Equal(t, block.ID, result.BlockID)
+	})
+
+	t.Run("Control block time", func(t *testing.T) {
+		_, flowkit := setupIntegration(gateway.WithManualMining())
+		gw := flowkit.Gateway().(*gateway.EmulatorGateway)
+		start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		gw.SetBlockTime(start)
+		block, err := gw.CommitBlock()
+		require.NoError(t, err)
+		assert.Equal(t, start, block.Timestamp.UTC())
+
+		gw.AdvanceBlockTime(time.Hour)
+		block, err = gw.CommitBlock()
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(time.Hour), block.Timestamp.UTC())
+
+		// the time stays fixed until it's changed again
+		block, err = gw.CommitBlock()
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(time.Hour), block.Timestamp.UTC())
+	})
+}
+
+func TestEmulatorFromConfig_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+	pk, _ := srvAcc.Key.PrivateKey()
+	key := &gateway.EmulatorKey{
+		PublicKey: (*pk).PublicKey(),
+		SigAlgo:   srvAcc.Key.SigAlgo(),
+		HashAlgo:  srvAcc.Key.HashAlgo(),
+	}
+	keys := []accounts.PublicKey{{
+		Public:   tests.PubKeys()[0],
+		Weight:   flow.AccountKeyWeightThreshold,
+		SigAlgo:  tests.SigAlgos()[0],
+		HashAlgo: tests.HashAlgos()[0],
+	}}
+
+	t.Run("Persistent storage", func(t *testing.T) {
+		conf := config.Emulator{
+			Name:           "persistent",
+			ServiceAccount: "emulator-account",
+			Storage:        filepath.Join(t.TempDir(), "flowdb"),
+		}
+
+		gw, err := gateway.NewEmulatorGatewayFromConfig(key, conf)
+		require.NoError(t, err)
+		flowkit.gateway = gw
+
+		acc, _, err := flowkit.CreateAccount(ctx, srvAcc, keys)
+		require.NoError(t, err)
+
+		// the storage is closed with the gateway
+		require.NoError(t, gw.Close())
+		_, err = gw.GetAccount(ctx, acc.Address)
+		assert.Error(t, err)
+
+		// the state is loaded from the storage directory by a new emulator
+		gw, err = gateway.NewEmulatorGatewayFromConfig(key, conf)
+		require.NoError(t, err)
+		defer gw.Close()
+
+		_, err = gw.GetAccount(ctx, acc.Address)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Script gas limit", func(t *testing.T) {
+		script := []byte(`
+			access(all) fun main(): Int {
+				var i = 0
+				while i < 1000 { i = i + 1 }
+				return i
+			}`)
+
+		gw, err := gateway.NewEmulatorGatewayFromConfig(key, config.Emulator{Name: "limited", ScriptGasLimit: 10})
+		require.NoError(t, err)
+
+		_, err = gw.ExecuteScript(ctx, script, nil)
+		assert.ErrorContains(t, err, "computation exceeds limit")
+
+		gw, err = gateway.NewEmulatorGatewayFromConfig(key, config.DefaultEmulator)
+		require.NoError(t, err)
+
+		value, err := gw.ExecuteScript(ctx, script, nil)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(1000), value)
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		_, err := gateway.NewEmulatorGatewayFromConfig(key, config.Emulator{Name: "invalid", MinimumAccountBalance: "-1"})
+		assert.ErrorContains(t, err, "invalid emulator invalid configuration")
+	})
+}
+
+func TestSystemTransactions_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+
+	_, _, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{{
+		Public:   tests.PubKeys()[0],
+		Weight:   flow.AccountKeyWeightThreshold,
+		SigAlgo:  tests.SigAlgos()[0],
+		HashAlgo: tests.HashAlgos()[0],
+	}})
+	require.NoError(t, err)
+
+	block, err := flowkit.Gateway().GetLatestBlock(ctx)
+	require.NoError(t, err)
+
+	t.Run("Get system chunk transaction", func(t *testing.T) {
+		tx, result, err := flowkit.GetSystemTransaction(ctx, block.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, tx.ID(), result.TransactionID)
+		assert.Equal(t, block.ID, result.BlockID)
+		assert.Equal(t, block.Height, result.BlockHeight)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+		assert.NoError(t, result.Error)
+
+		// the system chunk transaction is the last transaction of the block
+		txs, results, err := flowkit.GetTransactionsByBlockID(ctx, block.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, txs)
+		assert.Equal(t, tx.ID(), txs[len(txs)-1].ID())
+		assert.Equal(t, tx.ID(), results[len(results)-1].TransactionID)
+	})
+
+	t.Run("Get system transaction by ID", func(t *testing.T) {
+		systemTx, _, err := flowkit.GetSystemTransaction(ctx, block.ID)
+		require.NoError(t, err)
+
+		tx, result, err := flowkit.GetSystemTransactionWithID(ctx, block.ID, systemTx.ID())
+		require.NoError(t, err)
+		assert.Equal(t, systemTx.ID(), tx.ID())
+		assert.Equal(t, systemTx.ID(), result.TransactionID)
+
+		// an empty ID returns the system chunk transaction
+		tx, _, err = flowkit.GetSystemTransactionWithID(ctx, block.ID, flow.EmptyID)
+		require.NoError(t, err)
+		assert.Equal(t, systemTx.ID(), tx.ID())
+	})
+
+	t.Run("Fail not found", func(t *testing.T) {
+		_, _, err := flowkit.GetSystemTransactionWithID(ctx, block.ID, flow.HexToID("01"))
+		assert.ErrorContains(t, err, "could not find transaction")
+
+		_, _, err = flowkit.GetSystemTransaction(ctx, flow.HexToID("01"))
+		assert.ErrorContains(t, err, "could not find transaction")
+	})
+}
+
+func TestExecution_Integration(t *testing.T) {
+	state, flowkit := setupIntegration()
+	srvAcc, _ := state.EmulatorServiceAccount()
+
+	acc, _, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{{
+		Public:   tests.PubKeys()[0],
+		Weight:   flow.AccountKeyWeightThreshold,
+		SigAlgo:  tests.SigAlgos()[0],
+		HashAlgo: tests.HashAlgos()[0],
+	}})
+	require.NoError(t, err)
+
+	block, err := flowkit.Gateway().GetLatestBlock(ctx)
+	require.NoError(t, err)
+	txs, _, err := flowkit.GetTransactionsByBlockID(ctx, block.ID)
+	require.NoError(t, err)
+
+	t.Run("Get execution result", func(t *testing.T) {
+		result, err := flowkit.GetExecutionResult(ctx, block.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, block.ID, result.BlockID)
+		// a chunk for the collection and one for the system transactions
+		require.Len(t, result.Chunks, 2)
+		assert.Equal(t, uint16(1), result.Chunks[0].NumberOfTransactions)
+		assert.Equal(t, uint64(1), result.Chunks[1].Index)
+	})
+
+	t.Run("Get execution data", func(t *testing.T) {
+		data, err := flowkit.GetExecutionData(ctx, block.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, block.ID, data.BlockID)
+		require.Len(t, data.Chunks, 2)
+
+		chunk := data.Chunks[0]
+		require.Len(t, chunk.Transactions, 1)
+		assert.Equal(t, txs[0].ID(), chunk.Transactions[0].ID())
+		assert.Equal(t, txs[0].ID(), chunk.Results[0].TransactionID)
+		assert.False(t, chunk.Results[0].Failed)
+
+		var created []flow.Address
+		for _, e := range chunk.Events {
+			if e.Type == flow.EventAccountCreated {
+				event := NewEvent(e)
+				created = append(created, *event.GetAddress())
+			}
+		}
+		assert.Equal(t, []flow.Address{acc.Address}, created)
+
+		system := data.Chunks[1]
+		require.NotEmpty(t, system.Transactions)
+		assert.Equal(t, txs[len(txs)-1].ID(), system.Transactions[len(system.Transactions)-1].ID())
+	})
+
+	t.Run("Fail unknown block", func(t *testing.T) {
+		_, err := flowkit.GetExecutionResult(ctx, flow.HexToID("01"))
+		assert.ErrorContains(t, err, "failed to get execution result for block")
+
+		_, err = flowkit.GetExecutionData(ctx, flow.HexToID("01"))
+		assert.ErrorContains(t, err, "failed to get execution data for block")
+	})
+}
+
+func TestSimulateTransaction_Integration(t *testing.T) {
+	script := Script{Code: []byte(`
+		transaction {
+			prepare(signer: auth(Storage) &Account) {
+				signer.storage.save("Hello, Simulation!", to: /storage/simulation)
+			}
+		}`)}
+
+	t.Run("Simulate against the emulator gateway", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		before, err := flowkit.Gateway().GetLatestBlock(ctx)
+		require.NoError(t, err)
+
+		simulation, err := flowkit.SimulateTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+
+		assert.True(t, simulation.Forked)
+		assert.NoError(t, simulation.Error)
+		require.Len(t, simulation.StorageChanges, 1)
+		assert.Greater(t, simulation.StorageChanges[0].After, simulation.StorageChanges[0].Before)
+
+		// the simulation runs on a copy of the emulator, which mines the blocks as before
+		after, err := flowkit.Gateway().GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before.ID, after.ID)
+
+		_, result, err := flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+	})
+
+	t.Run("Keep pending transactions of a manually mined emulator", func(t *testing.T) {
+		state, flowkit := setupIntegration(gateway.WithManualMining())
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		pending := Script{Code: tests.TransactionSimple.Source, Location: tests.TransactionSimple.Filename}
+		handle, err := flowkit.SendTransactionAsync(ctx, transactions.SingleAccountRole(*srvAcc), pending, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+
+		simulation, err := flowkit.SimulateTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+		assert.NoError(t, simulation.Error)
+		assert.NotEqual(t, handle.ID, simulation.Transaction.ID())
+
+		estimate, err := flowkit.EstimateTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, 0.2)
+		require.NoError(t, err)
+		assert.NoError(t, estimate.Simulation.Error)
+
+		// the pending transaction is still in the pending block of the emulator
+		miner, ok := gateway.As[gateway.ManualMiner](flowkit.gateway)
+		require.True(t, ok)
+		_, err = miner.CommitBlock()
+		require.NoError(t, err)
+
+		result, err := handle.Wait(ctx, flow.TransactionStatusSealed)
+		require.NoError(t, err)
+		assert.NoError(t, result.Error)
+	})
+
+	// the REST API can't be forked, so the simulation falls back to a local emulator
+	unforkable := config.Network{Name: config.EmulatorNetwork.Name, Host: "http://127.0.0.1:8888"}
+
+	t.Run("Simulate with a local emulator", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+		// hide the emulator gateway so the simulation can't use it
+		flowkit.gateway = struct{ gateway.Gateway }{flowkit.gateway}
+		flowkit.network = unforkable
+
+		before, err := flowkit.Gateway().GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+
+		simulation, err := flowkit.SimulateTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+
+		assert.False(t, simulation.Forked)
+		assert.NoError(t, simulation.Error)
+		assert.Greater(t, simulation.ComputationUsed, uint64(0))
+		require.Len(t, simulation.StorageChanges, 1)
+		assert.Equal(t, srvAcc.Address, simulation.StorageChanges[0].Address)
+		assert.Greater(t, simulation.StorageChanges[0].After, simulation.StorageChanges[0].Before)
+
+		// nothing was sent to the network
+		after, err := flowkit.Gateway().GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		assert.Equal(t, before.Keys[0].SequenceNumber, after.Keys[0].SequenceNumber)
+	})
+
+	t.Run("Simulate with project deployments on a local emulator", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		flowkit.gateway = struct{ gateway.Gateway }{flowkit.gateway}
+		flowkit.network = unforkable
+
+		// the second account created by the emulator, so the first one is created and left unused
+		alice := newAccount("Alice", "179b6b1cb6755e31", "seedseedseedseedseedseedseedseedseedseedseedseedAlice")
+		state.Accounts().AddOrUpdate(alice)
+		state.Contracts().AddOrUpdate(config.Contract{
+			Name:     tests.ContractHelloString.Name,
+			Location: tests.ContractHelloString.Filename,
+		})
+		state.Deployments().AddOrUpdate(config.Deployment{
+			Network:   config.EmulatorNetwork.Name,
+			Account:   alice.Name,
+			Contracts: []config.ContractDeployment{{Name: tests.ContractHelloString.Name}},
+		})
+
+		greeting := Script{Code: []byte(`
+			import "Hello"
+
+			transaction {
+				prepare(signer: auth(Storage) &Account) {
+					signer.storage.save(Hello.hello(), to: /storage/greeting)
+				}
+			}`), Location: "greeting.cdc"}
+		simulation, err := flowkit.SimulateTransaction(ctx, transactions.SingleAccountRole(*alice), greeting, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+
+		assert.False(t, simulation.Forked)
+		assert.NoError(t, simulation.Error)
+		require.Len(t, simulation.StorageChanges, 1)
+		assert.Equal(t, alice.Address, simulation.StorageChanges[0].Address)
+	})
+
+	t.Run("Simulate failing transaction", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		failing := Script{Code: []byte(`transaction { prepare(signer: &Account) { panic("simulated failure") } }`)}
+		simulation, err := flowkit.SimulateTransaction(ctx, transactions.SingleAccountRole(*srvAcc), failing, flow.DefaultTransactionGasLimit)
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, simulation.Error, "simulated failure")
+		assert.True(t, simulation.Forked)
+	})
+}
+
+func TestEstimateTransaction_Integration(t *testing.T) {
+	script := Script{Code: []byte(`
+		transaction {
+			prepare(signer: auth(Storage) &Account) {
+				signer.storage.save("Hello, Estimation!", to: /storage/estimation)
+			}
+		}`)}
+
+	t.Run("Estimate transaction", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		estimate, err := flowkit.EstimateTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, 0.5)
+		require.NoError(t, err)
+
+		assert.NoError(t, estimate.Simulation.Error)
+		assert.Greater(t, estimate.ComputationUsed, uint64(0))
+		assert.Equal(t, uint64(math.Ceil(float64(estimate.ComputationUsed)*1.5)), estimate.ComputeLimit)
+		assert.Equal(t, cadence.UFix64(100_000_000), estimate.InclusionEffort)
+		assert.Equal(t, cadence.UFix64(estimate.ComputationUsed), estimate.ExecutionEffort)
+		assert.Greater(t, estimate.Fee, cadence.UFix64(0))
+
+		_, err = flowkit.EstimateTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, -1)
+		assert.EqualError(t, err, "compute margin can not be negative")
+	})
+
+	t.Run("Send transaction with estimated limit", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		tx, result, err := flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*srvAcc), script, 0)
+		require.NoError(t, err)
+		require.NoError(t, result.Error)
+
+		assert.Greater(t, tx.GasLimit, result.ComputationUsage)
+		assert.Less(t, tx.GasLimit, uint64(flow.DefaultTransactionGasLimit))
+	})
+
+	t.Run("Fail to send transaction failing estimation", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+
+		failing := Script{Code: []byte(`transaction { prepare(signer: &Account) { panic("estimated failure") } }`)}
+		_, _, err := flowkit.SendTransaction(ctx, transactions.SingleAccountRole(*srvAcc), failing, 0)
+		assert.ErrorContains(t, err, "transaction failed while estimating the compute limit")
+	})
+
+	t.Run("Create account with estimated limit", func(t *testing.T) {
+		state, flowkit := setupIntegration()
+		srvAcc, _ := state.EmulatorServiceAccount()
+		flowkit.SetComputeLimit(0)
+
+		pkey, _ := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("seedseedseedseedseedseedseedseedseedseedseedseed"))
+		acc, ID, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{{
+			Public:   pkey.PublicKey(),
+			SigAlgo:  crypto.ECDSA_P256,
+			HashAlgo: crypto.SHA3_256,
+		}})
+		require.NoError(t, err)
+		require.NotNil(t, acc)
+
+		tx, _, err := flowkit.GetTransactionByID(ctx, ID, false)
+		require.NoError(t, err)
+		assert.Less(t, tx.GasLimit, uint64(flow.DefaultTransactionGasLimit))
+	})
+}
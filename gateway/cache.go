@@ -0,0 +1,511 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// DefaultCacheSize is the default number of responses kept in the caching gateway memory.
+const DefaultCacheSize = 10_000
+
+// CacheStore is a persistent store backing the in-memory cache of the caching gateway.
+type CacheStore interface {
+	// Get returns the value stored for the key and false if the key is not found.
+	Get(key string) ([]byte, bool, error)
+	// Set stores the value for the key.
+	Set(key string, value []byte) error
+}
+
+var _ CacheStore = &FileCacheStore{}
+
+// FileCacheStore is a cache store keeping each cached response in a file inside the directory.
+type FileCacheStore struct {
+	dir string
+}
+
+// NewFileCacheStore returns a cache store using the directory, which is created if it doesn't exist.
+func NewFileCacheStore(dir string) (*FileCacheStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+
+	return &FileCacheStore{dir: dir}, nil
+}
+
+func (s *FileCacheStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func (s *FileCacheStore) Get(key string) ([]byte, bool, error) {
+	b, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return b, true, nil
+}
+
+func (s *FileCacheStore) Set(key string, value []byte) error {
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(value); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// rename the complete file, so concurrent readers never see a partially written value
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// CacheStats are the caching gateway statistics.
+type CacheStats struct {
+	// Hits is the number of responses served from the cache, including the store hits.
+	Hits uint64
+	// StoreHits is the number of responses served from the cache store.
+	StoreHits uint64
+	// Misses is the number of cacheable requests sent to the wrapped gateway.
+	Misses uint64
+	// Entries is the number of responses in memory.
+	Entries int
+}
+
+// lruCache is a cache evicting the least recently used entries once the size is reached.
+type lruCache struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key   string
+	value any
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *lruCache) get(key string) (any, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry).value, true
+}
+
+func (c *lruCache) add(key string, value any) {
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) len() int {
+	return c.order.Len()
+}
+
+var _ Gateway = &CachingGateway{}
+
+// CachingGateway is a gateway decorator caching the responses for immutable chain data.
+//
+// Blocks, collections, sealed transaction results and events from sealed block ranges are cached,
+// all other calls are passed through to the wrapped gateway. The sealed height is tracked
+// using the latest sealed block returned by the wrapped gateway.
+//
+// Cached responses are shared between the callers and must not be modified.
+type CachingGateway struct {
+	gateway      Gateway
+	network      string
+	mu           sync.Mutex
+	cache        *lruCache
+	store        CacheStore
+	sealedHeight atomic.Uint64
+	hits         atomic.Uint64
+	storeHits    atomic.Uint64
+	misses       atomic.Uint64
+}
+
+// NewCachingGateway returns a caching gateway keeping at most size responses in memory.
+func NewCachingGateway(gateway Gateway, size int, opts ...func(*CachingGateway)) *CachingGateway {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	g := &CachingGateway{
+		gateway: gateway,
+		cache:   newLRUCache(size),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// Unwrap returns the wrapped gateway.
+func (g *CachingGateway) Unwrap() Gateway {
+	return g.gateway
+}
+
+// WithCacheStore sets the store persisting the cached responses of the network, errors from the store are ignored.
+//
+// The cache keys are prefixed with the network name, so a store can be shared by the gateways of different networks.
+// The responses of an emulator must not be persisted, the emulator chain starts over so the same heights and IDs
+// can refer to different data on the next run.
+func WithCacheStore(network string, store CacheStore) func(g *CachingGateway) {
+	return func(g *CachingGateway) {
+		g.network = network
+		g.store = store
+	}
+}
+
+// Stats returns the cache statistics.
+func (g *CachingGateway) Stats() CacheStats {
+	g.mu.Lock()
+	entries := g.cache.len()
+	g.mu.Unlock()
+
+	return CacheStats{
+		Hits:      g.hits.Load(),
+		StoreHits: g.storeHits.Load(),
+		Misses:    g.misses.Load(),
+		Entries:   entries,
+	}
+}
+
+// lookup returns the cached value from memory or from the cache store.
+func lookup[T any](g *CachingGateway, key string, codec responseCodec[T]) (T, bool) {
+	g.mu.Lock()
+	value, ok := g.cache.get(key)
+	g.mu.Unlock()
+	if ok {
+		g.hits.Add(1)
+		return value.(T), true
+	}
+
+	if g.store != nil {
+		b, found, err := g.store.Get(key)
+		if err == nil && found {
+			if value, err := codec.decode(b); err == nil {
+				g.mu.Lock()
+				g.cache.add(key, value)
+				g.mu.Unlock()
+
+				g.hits.Add(1)
+				g.storeHits.Add(1)
+				return value, true
+			}
+		}
+	}
+
+	var empty T
+	return empty, false
+}
+
+// remember adds the value to the cache and the cache store.
+func remember[T any](g *CachingGateway, key string, value T, codec responseCodec[T]) {
+	g.mu.Lock()
+	g.cache.add(key, value)
+	g.mu.Unlock()
+
+	if g.store != nil {
+		if b, err := codec.encode(value); err == nil {
+			_ = g.store.Set(key, b)
+		}
+	}
+}
+
+// isSealed returns true if the block at the height is sealed, the latest sealed block is
+// only fetched if the height is above the known sealed height.
+func (g *CachingGateway) isSealed(ctx context.Context, height uint64) bool {
+	if height <= g.sealedHeight.Load() {
+		return true
+	}
+
+	block, err := g.GetLatestBlock(ctx)
+	if err != nil {
+		return false
+	}
+
+	return height <= block.Height
+}
+
+func (g *CachingGateway) updateSealedHeight(height uint64) {
+	for {
+		current := g.sealedHeight.Load()
+		if height <= current || g.sealedHeight.CompareAndSwap(current, height) {
+			return
+		}
+	}
+}
+
+// key returns the cache key scoped to the network, so the responses of different networks never share a key.
+func (g *CachingGateway) key(format string, args ...any) string {
+	return fmt.Sprintf("%s/%s", g.network, fmt.Sprintf(format, args...))
+}
+
+func (g *CachingGateway) blockIDKey(ID flow.Identifier) string {
+	return g.key("block/id/%s", ID)
+}
+
+func (g *CachingGateway) blockHeightKey(height uint64) string {
+	return g.key("block/height/%d", height)
+}
+
+func (g *CachingGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	return g.gateway.GetAccount(ctx, address)
+}
+
+func (g *CachingGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	return g.gateway.GetAccountAtBlockHeight(ctx, address, height)
+}
+
+func (g *CachingGateway) SendSignedTransaction(ctx context.Context, tx *flow.Transaction) (*flow.Transaction, error) {
+	return g.gateway.SendSignedTransaction(ctx, tx)
+}
+
+func (g *CachingGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	return g.gateway.GetTransaction(ctx, ID)
+}
+
+func (g *CachingGateway) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionResult, error) {
+	return g.gateway.GetTransactionResultsByBlockID(ctx, blockID)
+}
+
+// GetTransactionResult caches the result once the transaction is sealed.
+func (g *CachingGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	key := g.key("result/%s", ID)
+	if result, ok := lookup(g, key, resultCodec); ok {
+		return result, nil
+	}
+
+	g.misses.Add(1)
+	result, err := g.gateway.GetTransactionResult(ctx, ID, waitSeal)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status == flow.TransactionStatusSealed {
+		remember(g, key, result, resultCodec)
+	}
+
+	return result, nil
+}
+
+func (g *CachingGateway) GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.Transaction, error) {
+	return g.gateway.GetTransactionsByBlockID(ctx, blockID)
+}
+
+func (g *CachingGateway) GetSystemTransaction(ctx context.Context, blockID flow.Identifier) (*flow.Transaction, error) {
+	return g.gateway.GetSystemTransaction(ctx, blockID)
+}
+
+func (g *CachingGateway) GetSystemTransactionResult(ctx context.Context, blockID flow.Identifier) (*flow.TransactionResult, error) {
+	return g.gateway.GetSystemTransactionResult(ctx, blockID)
+}
+
+func (g *CachingGateway) GetSystemTransactionWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.Transaction, error) {
+	return g.gateway.GetSystemTransactionWithID(ctx, blockID, systemTxID)
+}
+
+func (g *CachingGateway) GetSystemTransactionResultWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.TransactionResult, error) {
+	return g.gateway.GetSystemTransactionResultWithID(ctx, blockID, systemTxID)
+}
+
+func (g *CachingGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	return g.gateway.ExecuteScript(ctx, script, arguments)
+}
+
+func (g *CachingGateway) ExecuteScriptAtHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	return g.gateway.ExecuteScriptAtHeight(ctx, script, arguments, height)
+}
+
+func (g *CachingGateway) ExecuteScriptAtID(ctx context.Context, script []byte, arguments []cadence.Value, ID flow.Identifier) (cadence.Value, error) {
+	return g.gateway.ExecuteScriptAtID(ctx, script, arguments, ID)
+}
+
+// GetLatestBlock is not cached, but it updates the known sealed height.
+func (g *CachingGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	block, err := g.gateway.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.updateSealedHeight(block.Height)
+	return block, nil
+}
+
+// GetBlockByHeight caches blocks at or below the sealed height.
+func (g *CachingGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	key := g.blockHeightKey(height)
+	if block, ok := lookup(g, key, blockCodec); ok {
+		return block, nil
+	}
+
+	g.misses.Add(1)
+	block, err := g.gateway.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.isSealed(ctx, height) {
+		remember(g, key, block, blockCodec)
+		remember(g, g.blockIDKey(block.ID), block, blockCodec)
+	}
+
+	return block, nil
+}
+
+func (g *CachingGateway) GetBlockByID(ctx context.Context, ID flow.Identifier) (*flow.Block, error) {
+	key := g.blockIDKey(ID)
+	if block, ok := lookup(g, key, blockCodec); ok {
+		return block, nil
+	}
+
+	g.misses.Add(1)
+	block, err := g.gateway.GetBlockByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	remember(g, key, block, blockCodec)
+	return block, nil
+}
+
+// GetEvents caches the events if the whole height range is sealed.
+func (g *CachingGateway) GetEvents(ctx context.Context, eventType string, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	key := g.key("events/%s/%d/%d", eventType, startHeight, endHeight)
+	if events, ok := lookup(g, key, eventsCodec); ok {
+		return events, nil
+	}
+
+	g.misses.Add(1)
+	events, err := g.gateway.GetEvents(ctx, eventType, startHeight, endHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.isSealed(ctx, endHeight) {
+		remember(g, key, events, eventsCodec)
+	}
+
+	return events, nil
+}
+
+func (g *CachingGateway) GetCollection(ctx context.Context, ID flow.Identifier) (*flow.Collection, error) {
+	key := g.key("collection/%s", ID)
+	if collection, ok := lookup(g, key, collectionCodec); ok {
+		return collection, nil
+	}
+
+	g.misses.Add(1)
+	collection, err := g.gateway.GetCollection(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	remember(g, key, collection, collectionCodec)
+	return collection, nil
+}
+
+func (g *CachingGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	return g.gateway.GetLatestProtocolStateSnapshot(ctx)
+}
+
+func (g *CachingGateway) GetNodeVersionInfo(ctx context.Context) (*flow.NodeVersionInfo, error) {
+	return g.gateway.GetNodeVersionInfo(ctx)
+}
+
+// GetExecutionResultForBlockID isn't cached, the result of a block that isn't sealed yet can still change.
+func (g *CachingGateway) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	return g.gateway.GetExecutionResultForBlockID(ctx, blockID)
+}
+
+// GetExecutionDataByBlockID isn't cached, the execution data of a block that isn't sealed yet can still change.
+func (g *CachingGateway) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	return g.gateway.GetExecutionDataByBlockID(ctx, blockID)
+}
+
+// SubscribeBlocks isn't cached, the subscription is established with the wrapped gateway.
+func (g *CachingGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return g.gateway.SubscribeBlocks(ctx, startHeight)
+}
+
+func (g *CachingGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return g.gateway.SubscribeBlockHeaders(ctx, startHeight)
+}
+
+func (g *CachingGateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	return g.gateway.SubscribeEvents(ctx, startHeight, filter)
+}
+
+func (g *CachingGateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return g.gateway.SubscribeAccountStatuses(ctx, startHeight, filter)
+}
+
+func (g *CachingGateway) Ping() error {
+	return g.gateway.Ping()
+}
+
+func (g *CachingGateway) WaitServer(ctx context.Context) error {
+	return g.gateway.WaitServer(ctx)
+}
+
+func (g *CachingGateway) SecureConnection() bool {
+	return g.gateway.SecureConnection()
+}
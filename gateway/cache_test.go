@@ -0,0 +1,247 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+	"github.com/onflow/flowkit/v2/tests"
+)
+
+func TestCachingGateway(t *testing.T) {
+	ctx := context.Background()
+
+	latest := func(height uint64) *flow.Block {
+		return &flow.Block{BlockHeader: flow.BlockHeader{Height: height}}
+	}
+
+	t.Run("Cache blocks by ID", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		block := tests.NewBlock()
+		g.On("GetBlockByID", ctx, block.ID).Return(block, nil).Once()
+
+		cache := NewCachingGateway(g, 10)
+		for i := 0; i < 3; i++ {
+			res, err := cache.GetBlockByID(ctx, block.ID)
+			require.NoError(t, err)
+			assert.Equal(t, block, res)
+		}
+
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 1}, cache.Stats())
+	})
+
+	t.Run("Cache blocks by height only when sealed", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		sealed := tests.NewBlock()
+		sealed.Height = 5
+		unsealed := tests.NewBlock()
+		unsealed.Height = 20
+
+		g.On("GetLatestBlock", ctx).Return(latest(10), nil).Once()
+		g.On("GetBlockByHeight", ctx, uint64(5)).Return(sealed, nil).Once()
+		g.On("GetBlockByHeight", ctx, uint64(20)).Return(unsealed, nil).Once()
+
+		cache := NewCachingGateway(g, 10)
+		_, err := cache.GetBlockByHeight(ctx, 5)
+		require.NoError(t, err)
+		_, err = cache.GetBlockByHeight(ctx, 5)
+		require.NoError(t, err)
+
+		// sealed block is also cached by ID
+		res, err := cache.GetBlockByID(ctx, sealed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sealed, res)
+
+		g.On("GetLatestBlock", ctx).Return(latest(10), nil).Twice()
+		g.On("GetBlockByHeight", ctx, uint64(20)).Return(unsealed, nil).Once()
+		_, err = cache.GetBlockByHeight(ctx, 20)
+		require.NoError(t, err)
+		_, err = cache.GetBlockByHeight(ctx, 20)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2), cache.Stats().Hits)
+		assert.Equal(t, uint64(3), cache.Stats().Misses)
+	})
+
+	t.Run("Cache only sealed transaction results", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		result := tests.NewAccountCreateResult(flow.HexToAddress("01"))
+		pending := *result
+		pending.Status = flow.TransactionStatusExecuted
+		result.Status = flow.TransactionStatusSealed
+
+		g.On("GetTransactionResult", ctx, result.TransactionID, false).Return(&pending, nil).Once()
+		g.On("GetTransactionResult", ctx, result.TransactionID, true).Return(result, nil).Once()
+
+		cache := NewCachingGateway(g, 10)
+		res, err := cache.GetTransactionResult(ctx, result.TransactionID, false)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusExecuted, res.Status)
+
+		for i := 0; i < 2; i++ {
+			res, err = cache.GetTransactionResult(ctx, result.TransactionID, true)
+			require.NoError(t, err)
+			assert.Equal(t, flow.TransactionStatusSealed, res.Status)
+		}
+	})
+
+	t.Run("Cache events for sealed ranges", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		events := []flow.BlockEvents{{Height: 5, Events: tests.NewAccountCreateResult(flow.HexToAddress("01")).Events}}
+
+		g.On("GetLatestBlock", ctx).Return(latest(10), nil)
+		g.On("GetEvents", ctx, "flow.AccountCreated", uint64(0), uint64(10)).Return(events, nil).Once()
+		g.On("GetEvents", ctx, "flow.AccountCreated", uint64(0), uint64(11)).Return(events, nil).Twice()
+
+		cache := NewCachingGateway(g, 10)
+		for i := 0; i < 2; i++ {
+			res, err := cache.GetEvents(ctx, "flow.AccountCreated", 0, 10)
+			require.NoError(t, err)
+			assert.Equal(t, events, res)
+
+			_, err = cache.GetEvents(ctx, "flow.AccountCreated", 0, 11)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("Pass through mutable calls", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		account := tests.NewAccountWithAddress("01")
+		g.On("GetAccount", ctx, account.Address).Return(account, nil).Twice()
+		g.On("GetLatestBlock", ctx).Return(latest(10), nil).Twice()
+
+		cache := NewCachingGateway(g, 10)
+		for i := 0; i < 2; i++ {
+			_, err := cache.GetAccount(ctx, account.Address)
+			require.NoError(t, err)
+			_, err = cache.GetLatestBlock(ctx)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, CacheStats{}, cache.Stats())
+	})
+
+	t.Run("Evict least recently used", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		collections := []*flow.Collection{
+			{TransactionIDs: []flow.Identifier{flow.HexToID("01")}},
+			{TransactionIDs: []flow.Identifier{flow.HexToID("02")}},
+			{TransactionIDs: []flow.Identifier{flow.HexToID("03")}},
+		}
+		for _, c := range collections {
+			g.On("GetCollection", ctx, c.ID()).Return(c, nil).Once()
+		}
+		g.On("GetCollection", ctx, collections[1].ID()).Return(collections[1], nil).Once()
+
+		cache := NewCachingGateway(g, 2)
+		for _, c := range []*flow.Collection{collections[0], collections[1], collections[0], collections[2], collections[0], collections[1]} {
+			res, err := cache.GetCollection(ctx, c.ID())
+			require.NoError(t, err)
+			assert.Equal(t, c, res)
+		}
+
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 4, Entries: 2}, cache.Stats())
+	})
+
+	t.Run("Persist to cache store", func(t *testing.T) {
+		store, err := NewFileCacheStore(t.TempDir())
+		require.NoError(t, err)
+
+		g := mocks.NewGateway(t)
+		block := tests.NewBlock()
+		collection := tests.NewCollection()
+		result := tests.NewAccountCreateResult(flow.HexToAddress("01"))
+		result.Status = flow.TransactionStatusSealed
+		events := []flow.BlockEvents{{BlockID: block.ID, Height: 5, Events: result.Events}}
+
+		g.On("GetBlockByID", ctx, block.ID).Return(block, nil).Once()
+		g.On("GetCollection", ctx, collection.ID()).Return(collection, nil).Once()
+		g.On("GetTransactionResult", ctx, result.TransactionID, true).Return(result, nil).Once()
+		g.On("GetLatestBlock", ctx).Return(latest(10), nil).Once()
+		g.On("GetEvents", ctx, "flow.AccountCreated", uint64(0), uint64(10)).Return(events, nil).Once()
+
+		first := NewCachingGateway(g, 10, WithCacheStore("mainnet", store))
+		_, err = first.GetBlockByID(ctx, block.ID)
+		require.NoError(t, err)
+		_, err = first.GetCollection(ctx, collection.ID())
+		require.NoError(t, err)
+		_, err = first.GetTransactionResult(ctx, result.TransactionID, true)
+		require.NoError(t, err)
+		_, err = first.GetEvents(ctx, "flow.AccountCreated", 0, 10)
+		require.NoError(t, err)
+
+		// a new gateway with an empty memory cache is served from the store
+		second := NewCachingGateway(mocks.NewGateway(t), 10, WithCacheStore("mainnet", store))
+		resBlock, err := second.GetBlockByID(ctx, block.ID)
+		require.NoError(t, err)
+		assert.Equal(t, block.ID, resBlock.ID)
+		assert.Equal(t, block.Height, resBlock.Height)
+
+		resCollection, err := second.GetCollection(ctx, collection.ID())
+		require.NoError(t, err)
+		assert.Equal(t, collection, resCollection)
+
+		resResult, err := second.GetTransactionResult(ctx, result.TransactionID, true)
+		require.NoError(t, err)
+		assert.Equal(t, result.Status, resResult.Status)
+		require.Len(t, resResult.Events, 1)
+		assert.Equal(t, result.Events[0].Value.String(), resResult.Events[0].Value.String())
+
+		resEvents, err := second.GetEvents(ctx, "flow.AccountCreated", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, resEvents, 1)
+		assert.Equal(t, block.ID, resEvents[0].BlockID)
+
+		assert.Equal(t, CacheStats{Hits: 4, StoreHits: 4, Entries: 4}, second.Stats())
+	})
+
+	t.Run("Scope cache store to the network", func(t *testing.T) {
+		store, err := NewFileCacheStore(t.TempDir())
+		require.NoError(t, err)
+
+		mainnetBlock := tests.NewBlock()
+		mainnet := mocks.NewGateway(t)
+		mainnet.On("GetLatestBlock", ctx).Return(latest(10), nil).Once()
+		mainnet.On("GetBlockByHeight", ctx, uint64(5)).Return(mainnetBlock, nil).Once()
+
+		testnetBlock := tests.NewBlock()
+		testnetBlock.ID = flow.HexToID("02")
+		testnet := mocks.NewGateway(t)
+		testnet.On("GetLatestBlock", ctx).Return(latest(10), nil).Once()
+		testnet.On("GetBlockByHeight", ctx, uint64(5)).Return(testnetBlock, nil).Once()
+
+		// the same height is fetched from each network
+		for _, g := range []struct {
+			network string
+			gateway *mocks.Gateway
+			block   *flow.Block
+		}{{"mainnet", mainnet, mainnetBlock}, {"testnet", testnet, testnetBlock}} {
+			cache := NewCachingGateway(g.gateway, 10, WithCacheStore(g.network, store))
+			res, err := cache.GetBlockByHeight(ctx, 5)
+			require.NoError(t, err)
+			assert.Equal(t, g.block.ID, res.ID)
+		}
+	})
+}
@@ -0,0 +1,456 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+)
+
+// The codec encodes gateway responses to JSON. Flow types containing Cadence values or errors are
+// converted to JSON structures with the Cadence values encoded as JSON-CDC, other types are encoded as they are.
+
+func decodeCadence(b []byte) (cadence.Value, error) {
+	return jsoncdc.Decode(nil, b, jsoncdc.WithAllowUnstructuredStaticTypes(true))
+}
+
+type jsonEvent struct {
+	Type             string          `json:"type"`
+	TransactionID    flow.Identifier `json:"transactionId"`
+	TransactionIndex int             `json:"transactionIndex"`
+	EventIndex       int             `json:"eventIndex"`
+	Value            json.RawMessage `json:"value"`
+}
+
+func newJSONEvent(e flow.Event) (jsonEvent, error) {
+	value, err := jsoncdc.Encode(e.Value)
+	if err != nil {
+		return jsonEvent{}, fmt.Errorf("failed to encode event %s: %w", e.Type, err)
+	}
+
+	return jsonEvent{
+		Type:             e.Type,
+		TransactionID:    e.TransactionID,
+		TransactionIndex: e.TransactionIndex,
+		EventIndex:       e.EventIndex,
+		Value:            value,
+	}, nil
+}
+
+func (e jsonEvent) toFlow() (flow.Event, error) {
+	value, err := decodeCadence(e.Value)
+	if err != nil {
+		return flow.Event{}, fmt.Errorf("failed to decode event %s: %w", e.Type, err)
+	}
+
+	event, ok := value.(cadence.Event)
+	if !ok {
+		return flow.Event{}, fmt.Errorf("failed to decode event %s: value is not an event", e.Type)
+	}
+
+	return flow.Event{
+		Type:             e.Type,
+		TransactionID:    e.TransactionID,
+		TransactionIndex: e.TransactionIndex,
+		EventIndex:       e.EventIndex,
+		Value:            event,
+		Payload:          e.Value,
+	}, nil
+}
+
+func newJSONEvents(events []flow.Event) ([]jsonEvent, error) {
+	jsonEvents := make([]jsonEvent, 0, len(events))
+	for _, e := range events {
+		jsonEvent, err := newJSONEvent(e)
+		if err != nil {
+			return nil, err
+		}
+		jsonEvents = append(jsonEvents, jsonEvent)
+	}
+
+	return jsonEvents, nil
+}
+
+func toFlowEvents(jsonEvents []jsonEvent) ([]flow.Event, error) {
+	events := make([]flow.Event, 0, len(jsonEvents))
+	for _, e := range jsonEvents {
+		event, err := e.toFlow()
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+type jsonTransactionResult struct {
+	Status           flow.TransactionStatus `json:"status"`
+	Error            string                 `json:"error,omitempty"`
+	Events           []jsonEvent            `json:"events"`
+	BlockID          flow.Identifier        `json:"blockId"`
+	BlockHeight      uint64                 `json:"blockHeight"`
+	TransactionID    flow.Identifier        `json:"transactionId"`
+	CollectionID     flow.Identifier        `json:"collectionId"`
+	ComputationUsage uint64                 `json:"computationUsage"`
+}
+
+func newJSONTransactionResult(result *flow.TransactionResult) (*jsonTransactionResult, error) {
+	if result == nil {
+		return nil, nil
+	}
+
+	events, err := newJSONEvents(result.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &jsonTransactionResult{
+		Status:           result.Status,
+		Events:           events,
+		BlockID:          result.BlockID,
+		BlockHeight:      result.BlockHeight,
+		TransactionID:    result.TransactionID,
+		CollectionID:     result.CollectionID,
+		ComputationUsage: result.ComputationUsage,
+	}
+	if result.Error != nil {
+		res.Error = result.Error.Error()
+	}
+
+	return res, nil
+}
+
+func (r *jsonTransactionResult) toFlow() (*flow.TransactionResult, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	events, err := toFlowEvents(r.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &flow.TransactionResult{
+		Status:           r.Status,
+		Events:           events,
+		BlockID:          r.BlockID,
+		BlockHeight:      r.BlockHeight,
+		TransactionID:    r.TransactionID,
+		CollectionID:     r.CollectionID,
+		ComputationUsage: r.ComputationUsage,
+	}
+	if r.Error != "" {
+		result.Error = errors.New(r.Error)
+	}
+
+	return result, nil
+}
+
+type jsonBlockEvents struct {
+	BlockID        flow.Identifier `json:"blockId"`
+	Height         uint64          `json:"height"`
+	BlockTimestamp time.Time       `json:"blockTimestamp"`
+	Events         []jsonEvent     `json:"events"`
+}
+
+func newJSONBlockEvents(blockEvents []flow.BlockEvents) ([]jsonBlockEvents, error) {
+	res := make([]jsonBlockEvents, 0, len(blockEvents))
+	for _, b := range blockEvents {
+		events, err := newJSONEvents(b.Events)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, jsonBlockEvents{
+			BlockID:        b.BlockID,
+			Height:         b.Height,
+			BlockTimestamp: b.BlockTimestamp,
+			Events:         events,
+		})
+	}
+
+	return res, nil
+}
+
+func toFlowBlockEvents(jsonBlockEvents []jsonBlockEvents) ([]flow.BlockEvents, error) {
+	res := make([]flow.BlockEvents, 0, len(jsonBlockEvents))
+	for _, b := range jsonBlockEvents {
+		events, err := toFlowEvents(b.Events)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, flow.BlockEvents{
+			BlockID:        b.BlockID,
+			Height:         b.Height,
+			BlockTimestamp: b.BlockTimestamp,
+			Events:         events,
+		})
+	}
+
+	return res, nil
+}
+
+type jsonChunkExecutionData struct {
+	Transactions       []*flow.Transaction            `json:"transactions"`
+	Events             []jsonEvent                    `json:"events"`
+	TrieUpdate         *flow.TrieUpdate               `json:"trieUpdate,omitempty"`
+	TransactionResults []*flow.LightTransactionResult `json:"transactionResults"`
+}
+
+type jsonExecutionData struct {
+	BlockID flow.Identifier          `json:"blockId"`
+	Chunks  []jsonChunkExecutionData `json:"chunks"`
+}
+
+func newJSONExecutionData(data *flow.ExecutionData) (*jsonExecutionData, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	chunks := make([]jsonChunkExecutionData, 0, len(data.ChunkExecutionData))
+	for _, c := range data.ChunkExecutionData {
+		events := make([]jsonEvent, 0, len(c.Events))
+		for _, e := range c.Events {
+			event, err := newJSONEvent(*e)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+
+		chunks = append(chunks, jsonChunkExecutionData{
+			Transactions:       c.Transactions,
+			Events:             events,
+			TrieUpdate:         c.TrieUpdate,
+			TransactionResults: c.TransactionResults,
+		})
+	}
+
+	return &jsonExecutionData{BlockID: data.BlockID, Chunks: chunks}, nil
+}
+
+func (d *jsonExecutionData) toFlow() (*flow.ExecutionData, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	chunks := make([]*flow.ChunkExecutionData, 0, len(d.Chunks))
+	for _, c := range d.Chunks {
+		events := make([]*flow.Event, 0, len(c.Events))
+		for _, e := range c.Events {
+			event, err := e.toFlow()
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, &event)
+		}
+
+		chunks = append(chunks, &flow.ChunkExecutionData{
+			Transactions:       c.Transactions,
+			Events:             events,
+			TrieUpdate:         c.TrieUpdate,
+			TransactionResults: c.TransactionResults,
+		})
+	}
+
+	return &flow.ExecutionData{BlockID: d.BlockID, ChunkExecutionData: chunks}, nil
+}
+
+type jsonAccountKey struct {
+	Index          uint32                    `json:"index"`
+	PublicKey      string                    `json:"publicKey"`
+	SigAlgo        crypto.SignatureAlgorithm `json:"sigAlgo"`
+	HashAlgo       crypto.HashAlgorithm      `json:"hashAlgo"`
+	Weight         int                       `json:"weight"`
+	SequenceNumber uint64                    `json:"sequenceNumber"`
+	Revoked        bool                      `json:"revoked"`
+}
+
+type jsonAccount struct {
+	Address   flow.Address      `json:"address"`
+	Balance   uint64            `json:"balance"`
+	Code      []byte            `json:"code,omitempty"`
+	Keys      []jsonAccountKey  `json:"keys"`
+	Contracts map[string][]byte `json:"contracts"`
+}
+
+func newJSONAccount(account *flow.Account) *jsonAccount {
+	if account == nil {
+		return nil
+	}
+
+	keys := make([]jsonAccountKey, 0, len(account.Keys))
+	for _, k := range account.Keys {
+		keys = append(keys, jsonAccountKey{
+			Index:          k.Index,
+			PublicKey:      hex.EncodeToString(k.PublicKey.Encode()),
+			SigAlgo:        k.SigAlgo,
+			HashAlgo:       k.HashAlgo,
+			Weight:         k.Weight,
+			SequenceNumber: k.SequenceNumber,
+			Revoked:        k.Revoked,
+		})
+	}
+
+	return &jsonAccount{
+		Address:   account.Address,
+		Balance:   account.Balance,
+		Code:      account.Code,
+		Keys:      keys,
+		Contracts: account.Contracts,
+	}
+}
+
+func (a *jsonAccount) toFlow() (*flow.Account, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	keys := make([]*flow.AccountKey, 0, len(a.Keys))
+	for _, k := range a.Keys {
+		b, err := hex.DecodeString(k.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode account key %d: %w", k.Index, err)
+		}
+
+		publicKey, err := crypto.DecodePublicKey(k.SigAlgo, b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode account key %d: %w", k.Index, err)
+		}
+
+		keys = append(keys, &flow.AccountKey{
+			Index:          k.Index,
+			PublicKey:      publicKey,
+			SigAlgo:        k.SigAlgo,
+			HashAlgo:       k.HashAlgo,
+			Weight:         k.Weight,
+			SequenceNumber: k.SequenceNumber,
+			Revoked:        k.Revoked,
+		})
+	}
+
+	return &flow.Account{
+		Address:   a.Address,
+		Balance:   a.Balance,
+		Code:      a.Code,
+		Keys:      keys,
+		Contracts: a.Contracts,
+	}, nil
+}
+
+// responseCodec encodes the gateway responses to bytes and decodes them back.
+type responseCodec[T any] struct {
+	encode func(T) ([]byte, error)
+	decode func([]byte) (T, error)
+}
+
+// jsonCodec is a codec for types that can be encoded to JSON as they are.
+func jsonCodec[T any]() responseCodec[T] {
+	return responseCodec[T]{
+		encode: func(v T) ([]byte, error) {
+			return json.Marshal(v)
+		},
+		decode: func(b []byte) (T, error) {
+			var v T
+			err := json.Unmarshal(b, &v)
+			return v, err
+		},
+	}
+}
+
+// convertingCodec is a codec for types that are converted to a JSON structure before encoding.
+func convertingCodec[T any, J any](to func(T) (J, error), from func(J) (T, error)) responseCodec[T] {
+	return responseCodec[T]{
+		encode: func(v T) ([]byte, error) {
+			j, err := to(v)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(j)
+		},
+		decode: func(b []byte) (T, error) {
+			var j J
+			if err := json.Unmarshal(b, &j); err != nil {
+				var empty T
+				return empty, err
+			}
+			return from(j)
+		},
+	}
+}
+
+var (
+	blockCodec           = jsonCodec[*flow.Block]()
+	collectionCodec      = jsonCodec[*flow.Collection]()
+	transactionCodec     = jsonCodec[*flow.Transaction]()
+	transactionsCodec    = jsonCodec[[]*flow.Transaction]()
+	versionCodec         = jsonCodec[*flow.NodeVersionInfo]()
+	bytesCodec           = jsonCodec[[]byte]()
+	executionResultCodec = jsonCodec[*flow.ExecutionResult]()
+	executionDataCodec   = convertingCodec(newJSONExecutionData, (*jsonExecutionData).toFlow)
+	accountCodec         = convertingCodec(
+		func(a *flow.Account) (*jsonAccount, error) { return newJSONAccount(a), nil },
+		(*jsonAccount).toFlow,
+	)
+	resultCodec = convertingCodec(
+		newJSONTransactionResult,
+		(*jsonTransactionResult).toFlow,
+	)
+	resultsCodec = convertingCodec(
+		func(results []*flow.TransactionResult) ([]*jsonTransactionResult, error) {
+			res := make([]*jsonTransactionResult, 0, len(results))
+			for _, r := range results {
+				j, err := newJSONTransactionResult(r)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, j)
+			}
+			return res, nil
+		},
+		func(results []*jsonTransactionResult) ([]*flow.TransactionResult, error) {
+			res := make([]*flow.TransactionResult, 0, len(results))
+			for _, r := range results {
+				result, err := r.toFlow()
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, result)
+			}
+			return res, nil
+		},
+	)
+	eventsCodec = convertingCodec(newJSONBlockEvents, toFlowBlockEvents)
+	valueCodec  = responseCodec[cadence.Value]{
+		encode: func(v cadence.Value) ([]byte, error) {
+			return jsoncdc.Encode(v)
+		},
+		decode: decodeCadence,
+	}
+)
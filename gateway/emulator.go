@@ -20,19 +20,36 @@ package gateway
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/flow-emulator/adapters"
+	"github.com/onflow/flow-emulator/convert"
 	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+	"github.com/onflow/flow-emulator/types"
+	accessmodel "github.com/onflow/flow-go/model/access"
+	flowGo "github.com/onflow/flow-go/model/flow"
 	"github.com/pkg/errors"
 
 	"github.com/onflow/flow-go-sdk"
 	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flowkit/v2/config"
 )
 
 type EmulatorKey struct {
@@ -47,8 +64,22 @@ type EmulatorGateway struct {
 	accessAdapter   *adapters.AccessAdapter
 	logger          *zerolog.Logger
 	emulatorOptions []emulator.Option
+	manualMining    bool
+	commits         *commitNotifier
+	snapshotMu      sync.Mutex
+	store           *sqlite.Store
+	storageDir      string
+	snapshots       map[string]struct{}
+	snapshotCurrent string
+	forkConn        *grpc.ClientConn
+	fork            *forkClients
+	key             *EmulatorKey
+	copyDir         string
 }
 
+var _ Snapshotter = &EmulatorGateway{}
+var _ ManualMiner = &EmulatorGateway{}
+
 func UnwrapStatusError(err error) error {
 	return errors.New(status.Convert(err).Message())
 }
@@ -58,20 +89,153 @@ func NewEmulatorGateway(key *EmulatorKey) *EmulatorGateway {
 }
 
 func NewEmulatorGatewayWithOpts(key *EmulatorKey, opts ...func(*EmulatorGateway)) *EmulatorGateway {
+	gateway, err := newEmulatorGateway(key, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return gateway
+}
+
+// NewEmulatorGatewayFromConfig creates an emulator gateway with the chain settings and storage of the emulator
+// configuration, the provided options are applied after the configuration. If the configuration has a persistent
+// storage the emulator state is loaded from the directory and kept there, the directory is created if it doesn't exist.
+// Close must be called to close the storage.
+func NewEmulatorGatewayFromConfig(
+	key *EmulatorKey,
+	conf config.Emulator,
+	opts ...func(*EmulatorGateway),
+) (*EmulatorGateway, error) {
+	emulatorOptions, err := configEmulatorOptions(conf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid emulator %s configuration: %w", conf.Name, err)
+	}
+
+	configOptions := []func(*EmulatorGateway){WithEmulatorOptions(emulatorOptions...)}
+	if dir := conf.PersistentStorage(); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+		store, err := sqlite.New(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open storage: %w", err)
+		}
+		configOptions = append(configOptions, withStorage(store, dir), WithEmulatorOptions(emulator.WithStore(store)))
+	}
+
+	// the storage is closed by newEmulatorGateway if the gateway can't be created
+	return newEmulatorGateway(key, append(configOptions, opts...)...)
+}
+
+// configEmulatorOptions converts the emulator configuration to emulator options, unset settings keep the emulator defaults.
+func configEmulatorOptions(conf config.Emulator) ([]emulator.Option, error) {
+	var opts []emulator.Option
+
+	if conf.ChainID != "" {
+		opts = append(opts, emulator.WithChainID(flowGo.ChainID(conf.ChainID)))
+	}
+
+	if conf.MinimumAccountBalance != "" {
+		balance, err := cadence.NewUFix64(conf.MinimumAccountBalance)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum account balance: %w", err)
+		}
+		opts = append(opts, emulator.WithMinimumStorageReservation(balance))
+	}
+
+	if conf.StorageLimit != nil {
+		opts = append(opts, emulator.WithStorageLimitEnabled(*conf.StorageLimit))
+	}
+
+	if conf.ScriptGasLimit != 0 {
+		opts = append(opts, emulator.WithScriptGasLimit(conf.ScriptGasLimit))
+	}
+
+	return append(
+		opts,
+		emulator.WithTransactionFeesEnabled(conf.TransactionFees),
+		emulator.WithContractRemovalEnabled(conf.ContractRemoval),
+	), nil
+}
+
+func newEmulatorGateway(key *EmulatorKey, opts ...func(*EmulatorGateway)) (*EmulatorGateway, error) {
 	noopLogger := zerolog.Nop()
 	gateway := &EmulatorGateway{
 		logger:          &noopLogger,
 		emulatorOptions: []emulator.Option{},
+		snapshots:       make(map[string]struct{}),
+		key:             key,
 	}
 	for _, opt := range opts {
 		opt(gateway)
 	}
 
-	gateway.emulator = newEmulator(key, gateway.emulatorOptions...)
+	// the gateway creates the default storage so it can manage the snapshots in it, the storage is applied
+	// before the other emulator options so they can still replace it
+	defaultStorage := gateway.store == nil
+	if defaultStorage {
+		store, err := sqlite.New(sqlite.InMemory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create storage: %w", err)
+		}
+		gateway.store = store
+		gateway.emulatorOptions = append([]emulator.Option{emulator.WithStore(store)}, gateway.emulatorOptions...)
+	}
+
+	var err error
+	gateway.emulator, err = newEmulator(key, gateway.emulatorOptions...)
+	if err != nil {
+		if gateway.store != nil {
+			_ = gateway.store.Close()
+		}
+		return nil, err
+	}
+
+	// an unused default storage has no blocks, the snapshots aren't supported by a storage of the emulator options
+	if defaultStorage {
+		if _, err := gateway.store.LatestBlock(context.Background()); err != nil {
+			_ = gateway.store.Close()
+			gateway.store = nil
+		}
+	}
 	gateway.adapter = adapters.NewSDKAdapter(gateway.logger, gateway.emulator)
 	gateway.accessAdapter = adapters.NewAccessAdapter(gateway.logger, gateway.emulator)
-	gateway.emulator.EnableAutoMine()
-	return gateway
+	gateway.commits = &commitNotifier{subscribers: make(map[chan struct{}]struct{})}
+	gateway.emulator.Broadcaster().Subscribe(gateway.commits)
+	if !gateway.manualMining {
+		gateway.emulator.EnableAutoMine()
+	}
+	return gateway, nil
+}
+
+// withStorage sets the sqlite storage the emulator runs on, so the gateway can manage the snapshots in it.
+// The directory is the storage directory of file storage, or empty for in-memory storage.
+//
+// The gateway takes ownership of the storage, which is closed by Close or if the gateway can't be created.
+func withStorage(store *sqlite.Store, dir string) func(g *EmulatorGateway) {
+	return func(g *EmulatorGateway) {
+		g.store = store
+		g.storageDir = dir
+	}
+}
+
+// Close closes the storage of the emulator and the connection to the network if the emulator was forked,
+// the storage of a copy is removed. The gateway can't be used after it's closed.
+func (g *EmulatorGateway) Close() error {
+	var err error
+	if g.store != nil {
+		err = g.store.Close()
+	}
+	if g.forkConn != nil {
+		if connErr := g.forkConn.Close(); err == nil {
+			err = connErr
+		}
+	}
+	if g.copyDir != "" {
+		if removeErr := os.RemoveAll(g.copyDir); err == nil {
+			err = removeErr
+		}
+	}
+	return err
 }
 
 func WithLogger(logger *zerolog.Logger) func(g *EmulatorGateway) {
@@ -80,13 +244,21 @@ func WithLogger(logger *zerolog.Logger) func(g *EmulatorGateway) {
 	}
 }
 
+// WithManualMining turns off auto-mining, the sent transactions stay in the pending block until it's committed
+// with CommitBlock, so several transactions can be included in the same block.
+func WithManualMining() func(g *EmulatorGateway) {
+	return func(g *EmulatorGateway) {
+		g.manualMining = true
+	}
+}
+
 func WithEmulatorOptions(options ...emulator.Option) func(g *EmulatorGateway) {
 	return func(g *EmulatorGateway) {
 		g.emulatorOptions = append(g.emulatorOptions, options...)
 	}
 }
 
-func newEmulator(key *EmulatorKey, emulatorOptions ...emulator.Option) *emulator.Blockchain {
+func newEmulator(key *EmulatorKey, emulatorOptions ...emulator.Option) (*emulator.Blockchain, error) {
 	var opts []emulator.Option
 
 	if key != nil {
@@ -97,10 +269,10 @@ func newEmulator(key *EmulatorKey, emulatorOptions ...emulator.Option) *emulator
 
 	b, err := emulator.New(opts...)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to create emulator: %w", err)
 	}
 
-	return b
+	return b, nil
 }
 
 func (g *EmulatorGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
@@ -128,11 +300,23 @@ func (g *EmulatorGateway) SendSignedTransaction(ctx context.Context, tx *flow.Tr
 	return tx, nil
 }
 
-func (g *EmulatorGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, _ bool) (*flow.TransactionResult, error) {
+// GetTransactionResult returns the transaction result from the emulator.
+//
+// With manual mining nothing else might ever commit the pending block, so waiting for the seal of a pending
+// transaction commits the pending block instead of waiting forever.
+func (g *EmulatorGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
 	result, err := g.adapter.GetTransactionResult(ctx, ID)
 	if err != nil {
 		return nil, UnwrapStatusError(err)
 	}
+
+	if waitSeal && g.manualMining && result.Status == flow.TransactionStatusPending {
+		if _, err := g.CommitBlock(); err != nil {
+			return nil, err
+		}
+		return g.GetTransactionResult(ctx, ID, false)
+	}
+
 	return result, nil
 }
 
@@ -160,20 +344,42 @@ func (g *EmulatorGateway) GetTransactionsByBlockID(ctx context.Context, id flow.
 	return txr, nil
 }
 
+// GetSystemTransaction returns the system chunk transaction executed at the end of the block.
 func (g *EmulatorGateway) GetSystemTransaction(ctx context.Context, blockID flow.Identifier) (*flow.Transaction, error) {
-	return nil, errors.New("GetSystemTransaction is not implemented")
+	return g.GetSystemTransactionWithID(ctx, blockID, flow.EmptyID)
 }
 
+// GetSystemTransactionResult returns the result of the system chunk transaction executed at the end of the block.
 func (g *EmulatorGateway) GetSystemTransactionResult(ctx context.Context, blockID flow.Identifier) (*flow.TransactionResult, error) {
-	return nil, errors.New("GetSystemTransactionResult is not implemented")
+	return g.GetSystemTransactionResultWithID(ctx, blockID, flow.EmptyID)
 }
 
+// GetSystemTransactionWithID returns a system transaction executed in the block, such as a scheduled transaction,
+// if the ID is empty the system chunk transaction is returned.
 func (g *EmulatorGateway) GetSystemTransactionWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.Transaction, error) {
-	return nil, errors.New("GetSystemTransactionWithID is not implemented")
+	tx, err := g.accessAdapter.GetSystemTransaction(ctx, convert.SDKIdentifierToFlow(systemTxID), convert.SDKIdentifierToFlow(blockID))
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+
+	sdkTx := convert.FlowTransactionToSDK(*tx)
+	return &sdkTx, nil
 }
 
+// GetSystemTransactionResultWithID returns the result of a system transaction executed in the block,
+// if the ID is empty the result of the system chunk transaction is returned.
 func (g *EmulatorGateway) GetSystemTransactionResultWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.TransactionResult, error) {
-	return nil, errors.New("GetSystemTransactionResultWithID is not implemented")
+	result, err := g.accessAdapter.GetSystemTransactionResult(
+		ctx,
+		convert.SDKIdentifierToFlow(systemTxID),
+		convert.SDKIdentifierToFlow(blockID),
+		entities.EventEncodingVersion_CCF_V0,
+	)
+	if err != nil {
+		return nil, UnwrapStatusError(err)
+	}
+
+	return convert.FlowTransactionResultToSDK(result)
 }
 
 func (g *EmulatorGateway) Ping() error {
@@ -339,11 +545,188 @@ func (g *EmulatorGateway) GetNodeVersionInfo(context.Context) (*flow.NodeVersion
 	return &flow.NodeVersionInfo{}, nil
 }
 
+// GetExecutionResultForBlockID returns the execution result of the block with a chunk for every collection and
+// a last chunk for the system transactions. The emulator doesn't compute state commitments or chunk computation,
+// so the chunks only describe the transactions they executed.
+func (g *EmulatorGateway) GetExecutionResultForBlockID(_ context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	chunks, err := g.chunkTransactions(convert.SDKIdentifierToFlow(blockID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &flow.ExecutionResult{BlockID: blockID}
+	for i, txIDs := range chunks {
+		result.Chunks = append(result.Chunks, &flow.Chunk{
+			CollectionIndex:      uint(i),
+			BlockID:              blockID,
+			NumberOfTransactions: uint16(len(txIDs)),
+			Index:                uint64(i),
+		})
+	}
+
+	return result, nil
+}
+
+// GetExecutionDataByBlockID returns the transactions, results and events of every chunk of the block,
+// the emulator doesn't keep the register changes so the chunks have no trie updates.
+func (g *EmulatorGateway) GetExecutionDataByBlockID(_ context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	id := convert.SDKIdentifierToFlow(blockID)
+	chunks, err := g.chunkTransactions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &flow.ExecutionData{BlockID: blockID}
+	for i, txIDs := range chunks {
+		system := i == len(chunks)-1
+		chunk := &flow.ChunkExecutionData{}
+
+		for _, txID := range txIDs {
+			var tx *flowGo.TransactionBody
+			var result *accessmodel.TransactionResult
+			if system {
+				tx, err = g.emulator.GetSystemTransaction(txID, id)
+				if err == nil {
+					result, err = g.emulator.GetSystemTransactionResult(txID, id)
+				}
+			} else {
+				tx, err = g.emulator.GetTransaction(txID)
+				if err == nil {
+					result, err = g.emulator.GetTransactionResult(txID)
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get transaction %s: %w", txID, err)
+			}
+
+			events, err := convert.FlowEventsToSDK(result.Events)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert events of transaction %s: %w", txID, err)
+			}
+
+			sdkTx := convert.FlowTransactionToSDK(*tx)
+			chunk.Transactions = append(chunk.Transactions, &sdkTx)
+			chunk.TransactionResults = append(chunk.TransactionResults, &flow.LightTransactionResult{
+				TransactionID: convert.FlowIdentifierToSDK(txID),
+				Failed:        result.ErrorMessage != "",
+			})
+			for j := range events {
+				chunk.Events = append(chunk.Events, &events[j])
+			}
+		}
+
+		data.ChunkExecutionData = append(data.ChunkExecutionData, chunk)
+	}
+
+	return data, nil
+}
+
+// chunkTransactions returns the transaction IDs executed by each chunk of the block,
+// a chunk for every collection and a last chunk for the system transactions.
+func (g *EmulatorGateway) chunkTransactions(blockID flowGo.Identifier) ([][]flowGo.Identifier, error) {
+	block, err := g.emulator.GetBlockByID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", blockID, err)
+	}
+
+	var chunks [][]flowGo.Identifier
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := g.emulator.GetCollectionByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get collection %s: %w", guarantee.CollectionID, err)
+		}
+		chunks = append(chunks, collection.Transactions)
+	}
+
+	systemTxIDs, err := g.emulator.GetSystemTransactionsForBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(chunks, systemTxIDs), nil
+}
+
 // SecureConnection placeholder func to complete gateway interface implementation
 func (g *EmulatorGateway) SecureConnection() bool {
 	return false
 }
 
+func (g *EmulatorGateway) subscriptions() subscriptions {
+	return subscriptions{
+		gateway:  g,
+		notifier: g.commits.subscribe,
+	}
+}
+
+// SubscribeBlocks sends the new blocks as they are committed by the emulator.
+func (g *EmulatorGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return g.subscriptions().blocks(ctx, startHeight)
+}
+
+// SubscribeBlockHeaders sends the new block headers as the blocks are committed by the emulator.
+func (g *EmulatorGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return g.subscriptions().blockHeaders(ctx, startHeight)
+}
+
+// SubscribeEvents sends the events matching the filter as the blocks are committed by the emulator.
+func (g *EmulatorGateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	return g.subscriptions().events(ctx, startHeight, filter)
+}
+
+// SubscribeAccountStatuses sends the core account events as the blocks are committed by the emulator.
+func (g *EmulatorGateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return g.subscriptions().accountStatuses(ctx, startHeight, filter)
+}
+
+// commitNotifier fans the emulator block commit notifications out to the active subscriptions.
+type commitNotifier struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+// Notify is called by the emulator after every committed block.
+func (n *commitNotifier) Notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for commits := range n.subscribers {
+		select {
+		case commits <- struct{}{}:
+		default: // a notification is already pending
+		}
+	}
+}
+
+func (n *commitNotifier) subscribe() blockNotifier {
+	commits := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	n.subscribers[commits] = struct{}{}
+	n.mu.Unlock()
+
+	return &commitSubscriber{notifier: n, commits: commits}
+}
+
+type commitSubscriber struct {
+	notifier *commitNotifier
+	commits  chan struct{}
+}
+
+func (s *commitSubscriber) wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.commits:
+		return true
+	}
+}
+
+func (s *commitSubscriber) stop() {
+	s.notifier.mu.Lock()
+	delete(s.notifier.subscribers, s.commits)
+	s.notifier.mu.Unlock()
+}
+
 func (g *EmulatorGateway) CoverageReport() *runtime.CoverageReport {
 	return g.emulator.CoverageReport()
 }
@@ -351,3 +734,367 @@ func (g *EmulatorGateway) CoverageReport() *runtime.CoverageReport {
 func (g *EmulatorGateway) RollbackToBlockHeight(height uint64) error {
 	return g.emulator.RollbackToBlockHeight(height)
 }
+
+// ChainID returns the ID of the chain the emulator is running.
+func (g *EmulatorGateway) ChainID() flow.ChainID {
+	return flow.ChainID(g.emulator.GetChain().ChainID())
+}
+
+// ManualMining returns whether the blocks are only committed on request.
+func (g *EmulatorGateway) ManualMining() bool {
+	return g.manualMining
+}
+
+// ExecuteNextTransaction executes the next transaction in the pending block without committing the block.
+func (g *EmulatorGateway) ExecuteNextTransaction() (*flow.TransactionResult, error) {
+	result, err := g.emulator.ExecuteNextTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	return &flow.TransactionResult{
+		Status:           flow.TransactionStatusExecuted,
+		Error:            result.Error,
+		Events:           result.Events,
+		TransactionID:    result.TransactionID,
+		ComputationUsage: result.ComputationUsed,
+	}, nil
+}
+
+// CommitBlock executes the remaining transactions in the pending block and commits it.
+func (g *EmulatorGateway) CommitBlock() (*flow.Block, error) {
+	// the block fails to execute if all its transactions were already executed with ExecuteNextTransaction
+	var exhausted *types.PendingBlockTransactionsExhaustedError
+	if _, err := g.emulator.ExecuteBlock(); err != nil && !errors.As(err, &exhausted) {
+		return nil, err
+	}
+
+	block, err := g.emulator.CommitBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.GetBlockByID(context.Background(), flow.Identifier(block.ID()))
+}
+
+// Copy creates an emulator gateway running on a copy of the committed emulator state, the transactions in the pending
+// block aren't copied. The copy has its own temporary storage so nothing executed on it affects the emulator, and the
+// copy of a forked emulator keeps reading the registers it doesn't have from the network at the same fork height.
+//
+// The options are applied to the copy after the options of the emulator, and Close must be called to remove the copy.
+func (g *EmulatorGateway) Copy(opts ...func(*EmulatorGateway)) (*EmulatorGateway, error) {
+	g.snapshotMu.Lock()
+	defer g.snapshotMu.Unlock()
+
+	if err := g.checkSnapshotStorage(); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "flowkit-emulator-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the copy storage directory: %w", err)
+	}
+
+	copied, err := g.copyStorage(dir, opts)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return copied, nil
+}
+
+// copyStorage copies the committed emulator state to the directory and creates the emulator gateway running on it.
+func (g *EmulatorGateway) copyStorage(dir string, opts []func(*EmulatorGateway)) (*EmulatorGateway, error) {
+	if _, err := g.store.DB().Exec("VACUUM main INTO ?", filepath.Join(dir, "emulator.sqlite")); err != nil {
+		return nil, fmt.Errorf("failed to copy the emulator storage: %w", err)
+	}
+
+	store, err := sqlite.New(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the copy storage: %w", err)
+	}
+
+	var emulatorStore emulator.Option = emulator.WithStore(store)
+	if g.fork != nil {
+		remoteStore, err := g.fork.store(store)
+		if err != nil {
+			_ = store.Close()
+			return nil, err
+		}
+		emulatorStore = emulator.WithStore(remoteStore)
+	}
+
+	// the copy storage replaces the storage of the emulator options, the storage is closed by newEmulatorGateway
+	// if the gateway can't be created
+	copied, err := newEmulatorGateway(g.key, append([]func(*EmulatorGateway){
+		WithLogger(g.logger),
+		WithEmulatorOptions(g.emulatorOptions...),
+		WithEmulatorOptions(emulatorStore),
+		withStorage(store, dir),
+	}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	copied.fork = g.fork
+	copied.copyDir = dir
+	return copied, nil
+}
+
+// SetBlockTime fixes the timestamp of the pending block and the following blocks to the provided time.
+func (g *EmulatorGateway) SetBlockTime(t time.Time) {
+	g.emulator.SetClock(fixedClock(t))
+}
+
+// AdvanceBlockTime moves the timestamp of the pending block forward by the duration,
+// the following blocks keep the new timestamp until it's changed again.
+func (g *EmulatorGateway) AdvanceBlockTime(d time.Duration) {
+	g.SetBlockTime(g.emulator.PendingBlockTimestamp().Add(d))
+}
+
+// fixedClock is an emulator clock that always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// workingSnapshot is the stored snapshot the emulator runs on once a snapshot is loaded. The emulator keeps writing to
+// the snapshot it loaded, so a saved snapshot is copied to the working snapshot which is loaded instead, and the
+// working snapshot is overwritten by the next load. The name doesn't match the snapshot names so it can't be saved over.
+const workingSnapshot = "flowkit.working"
+
+// CreateSnapshot saves the current emulator state under the name, an existing snapshot with the same name is replaced.
+func (g *EmulatorGateway) CreateSnapshot(name string) error {
+	if !snapshotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name \"%s\", only letters, digits, dashes and underscores are allowed", name)
+	}
+
+	g.snapshotMu.Lock()
+	defer g.snapshotMu.Unlock()
+
+	if err := g.checkSnapshotStorage(); err != nil {
+		return err
+	}
+
+	if err := g.replaceSnapshot(name); err != nil {
+		return fmt.Errorf("failed to create snapshot %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot resets the emulator to the state saved in the snapshot, the snapshot can be loaded again with the same state.
+func (g *EmulatorGateway) LoadSnapshot(name string) error {
+	g.snapshotMu.Lock()
+	defer g.snapshotMu.Unlock()
+
+	if err := g.checkSnapshot(name); err != nil {
+		return err
+	}
+
+	err := g.switchSnapshot(name)
+	if err == nil {
+		err = g.replaceSnapshot(workingSnapshot)
+	}
+	if err == nil {
+		err = g.switchSnapshot(workingSnapshot)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Snapshots returns the names of the snapshots in the emulator storage, for file storage this includes the
+// snapshots saved by previous emulators using the same directory.
+func (g *EmulatorGateway) Snapshots() ([]string, error) {
+	g.snapshotMu.Lock()
+	defer g.snapshotMu.Unlock()
+
+	if err := g.checkSnapshotStorage(); err != nil {
+		return nil, err
+	}
+
+	return g.storedSnapshots()
+}
+
+// DeleteSnapshot removes the snapshot and its saved state from the emulator storage.
+func (g *EmulatorGateway) DeleteSnapshot(name string) error {
+	g.snapshotMu.Lock()
+	defer g.snapshotMu.Unlock()
+
+	if err := g.checkSnapshot(name); err != nil {
+		return err
+	}
+
+	if err := g.clearSnapshot(name); err != nil {
+		return fmt.Errorf("failed to delete snapshot %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// checkSnapshotStorage returns an error if the snapshots can't be managed in the emulator storage, which is the case
+// when the storage was replaced with the emulator options.
+func (g *EmulatorGateway) checkSnapshotStorage() error {
+	if g.store == nil {
+		return fmt.Errorf("snapshots are not supported by the emulator storage")
+	}
+	return nil
+}
+
+// checkSnapshot returns an error if the snapshot doesn't exist.
+func (g *EmulatorGateway) checkSnapshot(name string) error {
+	if err := g.checkSnapshotStorage(); err != nil {
+		return err
+	}
+
+	names, err := g.storedSnapshots()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(names, name) {
+		return fmt.Errorf("snapshot %s does not exist", name)
+	}
+
+	return nil
+}
+
+// storedSnapshots returns the sorted names of the saved snapshots. The file storage is listed from the directory,
+// while the in-memory storage can only list the snapshots it ever created, so the gateway keeps track of them.
+func (g *EmulatorGateway) storedSnapshots() ([]string, error) {
+	stored := slices.Collect(maps.Keys(g.snapshots))
+	if g.storageDir != "" {
+		var err error
+		stored, err = g.emulator.Snapshots()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots: %w", err)
+		}
+	}
+
+	var names []string
+	for _, name := range stored {
+		if snapshotNamePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// switchSnapshot makes the emulator run on the stored snapshot.
+func (g *EmulatorGateway) switchSnapshot(stored string) error {
+	if err := g.emulator.LoadSnapshot(stored); err != nil {
+		return err
+	}
+	g.snapshotCurrent = stored
+	return nil
+}
+
+// replaceSnapshot saves the current state to the stored snapshot, the previous state of the snapshot is removed first
+// since the emulator storage can't overwrite a snapshot.
+func (g *EmulatorGateway) replaceSnapshot(stored string) error {
+	if err := g.clearSnapshot(stored); err != nil {
+		return err
+	}
+	if err := g.emulator.CreateSnapshot(stored); err != nil {
+		return err
+	}
+	if g.storageDir == "" {
+		g.snapshots[stored] = struct{}{}
+	}
+	return nil
+}
+
+// clearSnapshot removes the state of a stored snapshot the emulator isn't running on, it does nothing if the
+// snapshot doesn't exist.
+//
+// The file storage saves each snapshot to its own file which is removed. The in-memory storage keeps the snapshots
+// as long as the storage exists, so their tables are dropped instead, which requires running on the snapshot for a while.
+// The emulator storage has no API to delete snapshots, so both depend on the sqlite storage layout, which is covered
+// by the gateway tests.
+func (g *EmulatorGateway) clearSnapshot(stored string) error {
+	if g.storageDir != "" {
+		err := os.Remove(snapshotFile(g.storageDir, stored))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if _, ok := g.snapshots[stored]; !ok {
+		return nil
+	}
+
+	// the initial in-memory state is lost once another snapshot is loaded, so it's moved to the working snapshot first
+	if g.snapshotCurrent == "" {
+		if err := g.emulator.CreateSnapshot(workingSnapshot); err != nil {
+			return err
+		}
+		g.snapshots[workingSnapshot] = struct{}{}
+		if err := g.switchSnapshot(workingSnapshot); err != nil {
+			return err
+		}
+	}
+
+	// the emulator is switched back to the current snapshot even if clearing fails, so it never keeps
+	// running on a partly dropped database
+	current := g.snapshotCurrent
+	err := g.emulator.LoadSnapshot(stored)
+	if err == nil {
+		err = dropTables(g.store.DB())
+	}
+	if restoreErr := g.emulator.LoadSnapshot(current); restoreErr != nil {
+		return fmt.Errorf("failed to restore snapshot %s: %w", current, restoreErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	delete(g.snapshots, stored)
+	return nil
+}
+
+// snapshotFile returns the path of the file the sqlite file storage saves the snapshot to.
+func snapshotFile(dir string, stored string) string {
+	return filepath.Join(dir, "snapshot_"+stored)
+}
+
+// dropTables removes all the tables of the database and frees their space.
+func dropTables(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM sqlite_schema WHERE type = 'table'")
+	if err != nil {
+		return err
+	}
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if _, err := db.Exec(fmt.Sprintf("DROP TABLE \"%s\"", table)); err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Exec("VACUUM")
+	return err
+}
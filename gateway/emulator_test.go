@@ -0,0 +1,122 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmulatorSnapshotStorage covers the sqlite storage layout the snapshots are cleared with,
+// since the emulator storage has no API to delete a snapshot.
+func TestEmulatorSnapshotStorage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Remove snapshot files", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := sqlite.New(dir)
+		require.NoError(t, err)
+
+		g, err := newEmulatorGateway(nil, withStorage(store, dir), WithEmulatorOptions(emulator.WithStore(store)))
+		require.NoError(t, err)
+		defer g.Close()
+
+		require.NoError(t, g.CreateSnapshot("fixture"))
+		assert.FileExists(t, snapshotFile(dir, "fixture"))
+
+		require.NoError(t, g.DeleteSnapshot("fixture"))
+		assert.NoFileExists(t, snapshotFile(dir, "fixture"))
+		assert.EqualError(t, g.emulator.LoadSnapshot("fixture"), "snapshot fixture does not exist")
+	})
+
+	t.Run("Drop in-memory snapshot tables", func(t *testing.T) {
+		g, err := newEmulatorGateway(nil)
+		require.NoError(t, err)
+		defer g.Close()
+
+		require.NoError(t, g.CreateSnapshot("fixture"))
+		require.NoError(t, g.DeleteSnapshot("fixture"))
+
+		// the emulator storage reports a snapshot without tables as missing
+		assert.EqualError(t, g.emulator.LoadSnapshot("fixture"), "snapshot fixture does not exist")
+
+		// a dropped snapshot can be created again
+		require.NoError(t, g.CreateSnapshot("fixture"))
+		require.NoError(t, g.LoadSnapshot("fixture"))
+		_, err = g.GetLatestBlock(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Restore current snapshot if clearing fails", func(t *testing.T) {
+		g, err := newEmulatorGateway(nil)
+		require.NoError(t, err)
+		defer g.Close()
+
+		require.NoError(t, g.CreateSnapshot("fixture"))
+		require.NoError(t, g.LoadSnapshot("fixture"))
+		current := g.snapshotCurrent
+
+		// the snapshot is known to the gateway but missing in the storage
+		g.snapshots["missing"] = struct{}{}
+		assert.EqualError(t, g.clearSnapshot("missing"), "snapshot missing does not exist")
+
+		assert.Equal(t, current, g.snapshotCurrent)
+		_, err = g.GetLatestBlock(ctx)
+		assert.NoError(t, err)
+	})
+}
+
+func TestEmulatorCopy(t *testing.T) {
+	ctx := context.Background()
+
+	g, err := newEmulatorGateway(nil, WithManualMining())
+	require.NoError(t, err)
+	defer g.Close()
+
+	_, err = g.CommitBlock()
+	require.NoError(t, err)
+	latest, err := g.GetLatestBlock(ctx)
+	require.NoError(t, err)
+
+	copied, err := g.Copy()
+	require.NoError(t, err)
+
+	block, err := copied.GetLatestBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest.ID, block.ID)
+
+	// blocks committed on the copy don't change the emulator
+	_, err = copied.CommitBlock()
+	require.NoError(t, err)
+	block, err = copied.GetLatestBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest.Height+1, block.Height)
+
+	block, err = g.GetLatestBlock(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest.ID, block.ID)
+
+	require.NoError(t, copied.Close())
+	assert.NoDirExists(t, copied.copyDir)
+}
@@ -0,0 +1,589 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+// latencyWeight is the weight of a new latency sample in the endpoint moving average latency.
+const latencyWeight = 0.3
+
+// pinTTL is how long a submitted transaction stays pinned to its endpoint if its result is never polled
+// to a final status, which is longer than a transaction takes to expire on the network.
+const pinTTL = 15 * time.Minute
+
+// maxPinned is the maximum number of pinned transactions, the oldest pin is dropped when it's exceeded.
+var maxPinned = 10_000
+
+// FailoverEndpoint is an access node endpoint used by the failover gateway.
+type FailoverEndpoint struct {
+	Host    string
+	Gateway Gateway
+}
+
+// EndpointHealth describes the health of a failover gateway endpoint.
+type EndpointHealth struct {
+	Host        string
+	Healthy     bool
+	Latency     time.Duration
+	LastError   error
+	LastChecked time.Time
+}
+
+type failoverEndpoint struct {
+	host    string
+	gateway Gateway
+	healthy bool
+	latency time.Duration
+	lastErr error
+	checked time.Time
+}
+
+type transactionPin struct {
+	endpoint *failoverEndpoint
+	expires  time.Time
+}
+
+var _ Gateway = &FailoverGateway{}
+
+// FailoverGateway is a gateway routing requests across multiple access node endpoints.
+//
+// Reads are sent to the healthiest endpoint, which is a healthy endpoint with the lowest latency.
+// The latency is sampled from pings and short reads, calls that wait on the network such as waiting
+// for a transaction to be sealed only update the endpoint health.
+// If an endpoint fails with a retryable error it is marked as unhealthy and the request
+// fails over to the next endpoint. Unhealthy endpoints are only used if no healthy endpoint is left,
+// and are marked as healthy again by a successful call or health check.
+//
+// A submitted transaction is pinned to the endpoint that accepted it, so fetching the transaction
+// and polling its result uses the same endpoint until the result is final. Pins whose result is never
+// polled to a final status are dropped after a while, and the number of pinned transactions is bounded.
+type FailoverGateway struct {
+	mu        sync.RWMutex
+	endpoints []*failoverEndpoint
+	pinned    map[flow.Identifier]transactionPin
+}
+
+// NewFailoverGateway returns a failover gateway for the provided endpoints, with the first endpoint preferred.
+func NewFailoverGateway(endpoints ...FailoverEndpoint) (*FailoverGateway, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+
+	g := &FailoverGateway{
+		pinned: make(map[flow.Identifier]transactionPin),
+	}
+	for _, e := range endpoints {
+		g.endpoints = append(g.endpoints, &failoverEndpoint{
+			host:    e.Host,
+			gateway: e.Gateway,
+			healthy: true,
+		})
+	}
+
+	return g, nil
+}
+
+// NewNetworkFailoverGateway creates a failover gateway with an endpoint for each of the network access hosts.
+//
+// The newGateway function creates the gateway for a single host, for example NewGrpcGateway.
+// The network key identifies a single access node, so it can't be used with multiple hosts.
+func NewNetworkFailoverGateway(
+	network config.Network,
+	newGateway func(network config.Network) (Gateway, error),
+) (*FailoverGateway, error) {
+	hosts := network.AccessHosts()
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("network %s doesn't define any hosts", network.Name)
+	}
+	if network.Key != "" && len(hosts) > 1 {
+		return nil, fmt.Errorf("network %s key can't be used with multiple hosts", network.Name)
+	}
+
+	endpoints := make([]FailoverEndpoint, 0, len(hosts))
+	for _, host := range hosts {
+		hostNetwork := network
+		hostNetwork.Host = host
+		hostNetwork.Hosts = nil
+
+		gw, err := newGateway(hostNetwork)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gateway for host %s: %w", host, err)
+		}
+		endpoints = append(endpoints, FailoverEndpoint{Host: host, Gateway: gw})
+	}
+
+	return NewFailoverGateway(endpoints...)
+}
+
+// CheckHealth checks all the endpoints by pinging them and fetching the node version info.
+func (g *FailoverGateway) CheckHealth(ctx context.Context) []EndpointHealth {
+	var wg sync.WaitGroup
+	for _, e := range g.endpoints {
+		wg.Add(1)
+		go func(e *failoverEndpoint) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := e.gateway.Ping()
+			if err == nil {
+				_, err = e.gateway.GetNodeVersionInfo(ctx)
+			}
+			g.record(e, err)
+			if err == nil {
+				g.sample(e, time.Since(start))
+			}
+		}(e)
+	}
+	wg.Wait()
+
+	return g.Health()
+}
+
+// StartHealthChecks periodically checks the endpoints health until the context is cancelled.
+func (g *FailoverGateway) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				g.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// Health returns the current health of all the endpoints.
+func (g *FailoverGateway) Health() []EndpointHealth {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	health := make([]EndpointHealth, 0, len(g.endpoints))
+	for _, e := range g.endpoints {
+		health = append(health, EndpointHealth{
+			Host:        e.host,
+			Healthy:     e.healthy,
+			Latency:     e.latency,
+			LastError:   e.lastErr,
+			LastChecked: e.checked,
+		})
+	}
+
+	return health
+}
+
+// record updates the endpoint health with the outcome of a call.
+func (g *FailoverGateway) record(e *failoverEndpoint, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	e.checked = time.Now()
+	e.lastErr = err
+	e.healthy = err == nil
+}
+
+// sample adds the duration of a successful short call to the endpoint moving average latency.
+//
+// Only short reads and pings are sampled, calls that wait on the network, such as waiting for a transaction
+// to be sealed, would otherwise rank the endpoint they wait on as the slowest.
+func (g *FailoverGateway) sample(e *failoverEndpoint, latency time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(e.latency))
+	}
+}
+
+// ranked returns the endpoints ordered by preference, healthy endpoints with lower latency first.
+func (g *FailoverGateway) ranked() []*failoverEndpoint {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	endpoints := slices.Clone(g.endpoints)
+	slices.SortStableFunc(endpoints, func(a, b *failoverEndpoint) int {
+		if a.healthy != b.healthy {
+			if a.healthy {
+				return -1
+			}
+			return 1
+		}
+		if !a.healthy {
+			return 0
+		}
+		return cmp.Compare(a.latency, b.latency)
+	})
+
+	return endpoints
+}
+
+// withFailover calls the endpoints in order of preference until the call doesn't fail with an endpoint error.
+// The call duration is sampled as the endpoint latency if sampled is set.
+func withFailover[T any](
+	ctx context.Context,
+	g *FailoverGateway,
+	endpoints []*failoverEndpoint,
+	failover func(error) bool,
+	sampled bool,
+	call func(Gateway) (T, error),
+) (T, *failoverEndpoint, error) {
+	var res T
+	var err error
+	for _, e := range endpoints {
+		start := time.Now()
+		res, err = call(e.gateway)
+		if err == nil || !failover(err) {
+			// terminal errors are returned by a responsive node so they don't affect the endpoint health
+			var endpointErr error
+			if err != nil && IsRetryableError(err) {
+				endpointErr = err
+			}
+			g.record(e, endpointErr)
+			if sampled && endpointErr == nil {
+				g.sample(e, time.Since(start))
+			}
+			return res, e, err
+		}
+
+		g.record(e, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return res, nil, err
+}
+
+// read sends a short read to the endpoints, its duration is sampled as the endpoint latency.
+func read[T any](ctx context.Context, g *FailoverGateway, call func(Gateway) (T, error)) (T, error) {
+	res, _, err := withFailover(ctx, g, g.ranked(), IsRetryableError, true, call)
+	return res, err
+}
+
+// readWaiting sends a read whose duration depends on more than the endpoint latency, like executing a script
+// or fetching an event range, so it only updates the endpoint health.
+func readWaiting[T any](ctx context.Context, g *FailoverGateway, call func(Gateway) (T, error)) (T, error) {
+	res, _, err := withFailover(ctx, g, g.ranked(), IsRetryableError, false, call)
+	return res, err
+}
+
+// readPinned sends the request for the transaction to its pinned endpoint first, its duration is sampled as the
+// endpoint latency if sampled is set.
+func readPinned[T any](
+	ctx context.Context,
+	g *FailoverGateway,
+	ID flow.Identifier,
+	sampled bool,
+	call func(Gateway) (T, error),
+) (T, error) {
+	endpoints := g.ranked()
+
+	g.mu.RLock()
+	pin, ok := g.pinned[ID]
+	g.mu.RUnlock()
+	if ok && time.Now().Before(pin.expires) {
+		endpoints = slices.DeleteFunc(endpoints, func(e *failoverEndpoint) bool { return e == pin.endpoint })
+		endpoints = append([]*failoverEndpoint{pin.endpoint}, endpoints...)
+	}
+
+	res, _, err := withFailover(ctx, g, endpoints, IsRetryableError, sampled, call)
+	return res, err
+}
+
+// pin pins the transaction to the endpoint, dropping the expired pins and the oldest pin if the limit is reached.
+func (g *FailoverGateway) pin(ID flow.Identifier, e *failoverEndpoint) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for pinnedID, pin := range g.pinned {
+		if !now.Before(pin.expires) {
+			delete(g.pinned, pinnedID)
+		}
+	}
+
+	if len(g.pinned) >= maxPinned {
+		var oldestID flow.Identifier
+		var oldest time.Time
+		for pinnedID, pin := range g.pinned {
+			if oldest.IsZero() || pin.expires.Before(oldest) {
+				oldestID, oldest = pinnedID, pin.expires
+			}
+		}
+		delete(g.pinned, oldestID)
+	}
+
+	g.pinned[ID] = transactionPin{endpoint: e, expires: now.Add(pinTTL)}
+}
+
+func (g *FailoverGateway) unpin(ID flow.Identifier) {
+	g.mu.Lock()
+	delete(g.pinned, ID)
+	g.mu.Unlock()
+}
+
+func (g *FailoverGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Account, error) {
+		return gw.GetAccount(ctx, address)
+	})
+}
+
+func (g *FailoverGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Account, error) {
+		return gw.GetAccountAtBlockHeight(ctx, address, height)
+	})
+}
+
+// SendSignedTransaction submits the transaction to the healthiest endpoint and pins the transaction to it.
+//
+// The submission only fails over to another endpoint if it never reached the node.
+func (g *FailoverGateway) SendSignedTransaction(ctx context.Context, tx *flow.Transaction) (*flow.Transaction, error) {
+	sent, e, err := withFailover(ctx, g, g.ranked(), isUndeliveredError, false, func(gw Gateway) (*flow.Transaction, error) {
+		return gw.SendSignedTransaction(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	g.pin(tx.ID(), e)
+
+	return sent, nil
+}
+
+func (g *FailoverGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	return readPinned(ctx, g, ID, true, func(gw Gateway) (*flow.Transaction, error) {
+		return gw.GetTransaction(ctx, ID)
+	})
+}
+
+func (g *FailoverGateway) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionResult, error) {
+	return read(ctx, g, func(gw Gateway) ([]*flow.TransactionResult, error) {
+		return gw.GetTransactionResultsByBlockID(ctx, blockID)
+	})
+}
+
+// GetTransactionResult gets the result from the endpoint the transaction was submitted to,
+// the transaction is unpinned once the result is final.
+func (g *FailoverGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	// waiting for the seal blocks until the transaction is sealed, so only the unsealed reads are sampled
+	result, err := readPinned(ctx, g, ID, !waitSeal, func(gw Gateway) (*flow.TransactionResult, error) {
+		return gw.GetTransactionResult(ctx, ID, waitSeal)
+	})
+	if err == nil && (result.Status == flow.TransactionStatusSealed || result.Status == flow.TransactionStatusExpired) {
+		g.unpin(ID)
+	}
+
+	return result, err
+}
+
+func (g *FailoverGateway) GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.Transaction, error) {
+	return read(ctx, g, func(gw Gateway) ([]*flow.Transaction, error) {
+		return gw.GetTransactionsByBlockID(ctx, blockID)
+	})
+}
+
+func (g *FailoverGateway) GetSystemTransaction(ctx context.Context, blockID flow.Identifier) (*flow.Transaction, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Transaction, error) {
+		return gw.GetSystemTransaction(ctx, blockID)
+	})
+}
+
+func (g *FailoverGateway) GetSystemTransactionResult(ctx context.Context, blockID flow.Identifier) (*flow.TransactionResult, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.TransactionResult, error) {
+		return gw.GetSystemTransactionResult(ctx, blockID)
+	})
+}
+
+func (g *FailoverGateway) GetSystemTransactionWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.Transaction, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Transaction, error) {
+		return gw.GetSystemTransactionWithID(ctx, blockID, systemTxID)
+	})
+}
+
+func (g *FailoverGateway) GetSystemTransactionResultWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.TransactionResult, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.TransactionResult, error) {
+		return gw.GetSystemTransactionResultWithID(ctx, blockID, systemTxID)
+	})
+}
+
+func (g *FailoverGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	return readWaiting(ctx, g, func(gw Gateway) (cadence.Value, error) {
+		return gw.ExecuteScript(ctx, script, arguments)
+	})
+}
+
+func (g *FailoverGateway) ExecuteScriptAtHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	return readWaiting(ctx, g, func(gw Gateway) (cadence.Value, error) {
+		return gw.ExecuteScriptAtHeight(ctx, script, arguments, height)
+	})
+}
+
+func (g *FailoverGateway) ExecuteScriptAtID(ctx context.Context, script []byte, arguments []cadence.Value, ID flow.Identifier) (cadence.Value, error) {
+	return readWaiting(ctx, g, func(gw Gateway) (cadence.Value, error) {
+		return gw.ExecuteScriptAtID(ctx, script, arguments, ID)
+	})
+}
+
+func (g *FailoverGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Block, error) {
+		return gw.GetLatestBlock(ctx)
+	})
+}
+
+func (g *FailoverGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Block, error) {
+		return gw.GetBlockByHeight(ctx, height)
+	})
+}
+
+func (g *FailoverGateway) GetBlockByID(ctx context.Context, ID flow.Identifier) (*flow.Block, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Block, error) {
+		return gw.GetBlockByID(ctx, ID)
+	})
+}
+
+func (g *FailoverGateway) GetEvents(ctx context.Context, eventType string, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	return readWaiting(ctx, g, func(gw Gateway) ([]flow.BlockEvents, error) {
+		return gw.GetEvents(ctx, eventType, startHeight, endHeight)
+	})
+}
+
+func (g *FailoverGateway) GetCollection(ctx context.Context, ID flow.Identifier) (*flow.Collection, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.Collection, error) {
+		return gw.GetCollection(ctx, ID)
+	})
+}
+
+func (g *FailoverGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	return readWaiting(ctx, g, func(gw Gateway) ([]byte, error) {
+		return gw.GetLatestProtocolStateSnapshot(ctx)
+	})
+}
+
+func (g *FailoverGateway) GetNodeVersionInfo(ctx context.Context) (*flow.NodeVersionInfo, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.NodeVersionInfo, error) {
+		return gw.GetNodeVersionInfo(ctx)
+	})
+}
+
+func (g *FailoverGateway) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	return read(ctx, g, func(gw Gateway) (*flow.ExecutionResult, error) {
+		return gw.GetExecutionResultForBlockID(ctx, blockID)
+	})
+}
+
+func (g *FailoverGateway) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	return readWaiting(ctx, g, func(gw Gateway) (*flow.ExecutionData, error) {
+		return gw.GetExecutionDataByBlockID(ctx, blockID)
+	})
+}
+
+// subscribeFailover establishes the subscription with the preferred endpoint, once it's established
+// the subscription stays on that endpoint and its errors are sent to the error channel.
+func subscribeFailover[T any](ctx context.Context, g *FailoverGateway, subscribe func(Gateway) (<-chan T, <-chan error, error)) (<-chan T, <-chan error, error) {
+	sub, err := readWaiting(ctx, g, func(gw Gateway) (subscription[T], error) {
+		return newSubscription(subscribe(gw))
+	})
+	return sub.items, sub.errs, err
+}
+
+func (g *FailoverGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return subscribeFailover(ctx, g, func(gw Gateway) (<-chan *flow.Block, <-chan error, error) {
+		return gw.SubscribeBlocks(ctx, startHeight)
+	})
+}
+
+func (g *FailoverGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return subscribeFailover(ctx, g, func(gw Gateway) (<-chan *flow.BlockHeader, <-chan error, error) {
+		return gw.SubscribeBlockHeaders(ctx, startHeight)
+	})
+}
+
+func (g *FailoverGateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	return subscribeFailover(ctx, g, func(gw Gateway) (<-chan flow.BlockEvents, <-chan error, error) {
+		return gw.SubscribeEvents(ctx, startHeight, filter)
+	})
+}
+
+func (g *FailoverGateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return subscribeFailover(ctx, g, func(gw Gateway) (<-chan *flow.AccountStatus, <-chan error, error) {
+		return gw.SubscribeAccountStatuses(ctx, startHeight, filter)
+	})
+}
+
+// Ping succeeds if any of the endpoints is reachable.
+func (g *FailoverGateway) Ping() error {
+	var err error
+	for _, e := range g.ranked() {
+		start := time.Now()
+		err = e.gateway.Ping()
+		g.record(e, err)
+		if err == nil {
+			g.sample(e, time.Since(start))
+		}
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// WaitServer waits until any of the endpoints is reachable.
+func (g *FailoverGateway) WaitServer(ctx context.Context) error {
+	for {
+		if err := g.Ping(); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// SecureConnection returns true only if connections to all the endpoints are secure.
+func (g *FailoverGateway) SecureConnection() bool {
+	for _, e := range g.endpoints {
+		if !e.gateway.SecureConnection() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,255 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+)
+
+func TestFailoverGateway(t *testing.T) {
+	ctx := context.Background()
+	unavailable := status.Error(codes.Unavailable, "node is overloaded")
+	block := &flow.Block{BlockHeader: flow.BlockHeader{Height: 10}}
+
+	newFailover := func(t *testing.T) (*FailoverGateway, *mocks.Gateway, *mocks.Gateway) {
+		primary := mocks.NewGateway(t)
+		secondary := mocks.NewGateway(t)
+		g, err := NewFailoverGateway(
+			FailoverEndpoint{Host: "primary:9000", Gateway: primary},
+			FailoverEndpoint{Host: "secondary:9000", Gateway: secondary},
+		)
+		require.NoError(t, err)
+		return g, primary, secondary
+	}
+
+	t.Run("Require endpoints", func(t *testing.T) {
+		_, err := NewFailoverGateway()
+		assert.EqualError(t, err, "at least one endpoint is required")
+	})
+
+	t.Run("Fail over unavailable endpoint", func(t *testing.T) {
+		g, primary, secondary := newFailover(t)
+		primary.On("GetLatestBlock", ctx).Return(nil, unavailable).Once()
+		secondary.On("GetLatestBlock", ctx).Return(block, nil).Twice()
+
+		res, err := g.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, block, res)
+
+		health := g.Health()
+		assert.False(t, health[0].Healthy)
+		assert.ErrorIs(t, health[0].LastError, unavailable)
+		assert.True(t, health[1].Healthy)
+
+		// unhealthy endpoint is not used while a healthy endpoint is available
+		_, err = g.GetLatestBlock(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("Don't fail over terminal errors", func(t *testing.T) {
+		g, primary, _ := newFailover(t)
+		notFound := status.Error(codes.NotFound, "not found")
+		primary.On("GetCollection", ctx, flow.EmptyID).Return(nil, notFound).Once()
+
+		_, err := g.GetCollection(ctx, flow.EmptyID)
+		assert.ErrorIs(t, err, notFound)
+		assert.True(t, g.Health()[0].Healthy)
+	})
+
+	t.Run("Return last error if all endpoints fail", func(t *testing.T) {
+		g, primary, secondary := newFailover(t)
+		primary.On("GetLatestBlock", ctx).Return(nil, unavailable).Once()
+		secondary.On("GetLatestBlock", ctx).Return(nil, unavailable).Once()
+
+		_, err := g.GetLatestBlock(ctx)
+		assert.ErrorIs(t, err, unavailable)
+	})
+
+	t.Run("Check health", func(t *testing.T) {
+		g, primary, secondary := newFailover(t)
+		primary.On("Ping").Return(unavailable).Once()
+		secondary.On("Ping").Return(nil).Once()
+		secondary.On("GetNodeVersionInfo", ctx).Return(&flow.NodeVersionInfo{}, nil).Once()
+
+		health := g.CheckHealth(ctx)
+		require.Len(t, health, 2)
+		assert.Equal(t, "primary:9000", health[0].Host)
+		assert.False(t, health[0].Healthy)
+		assert.True(t, health[1].Healthy)
+		assert.False(t, health[1].LastChecked.IsZero())
+
+		// healthy secondary endpoint is preferred now
+		secondary.On("GetLatestBlock", ctx).Return(block, nil).Once()
+		_, err := g.GetLatestBlock(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("Pin transaction to submitting endpoint", func(t *testing.T) {
+		g, primary, secondary := newFailover(t)
+		tx := flow.NewTransaction().SetScript([]byte("transaction {}"))
+		undelivered := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+		primary.On("SendSignedTransaction", ctx, tx).Return(nil, undelivered).Once()
+		secondary.On("SendSignedTransaction", ctx, tx).Return(tx, nil).Once()
+		_, err := g.SendSignedTransaction(ctx, tx)
+		require.NoError(t, err)
+
+		// primary becomes healthy again, but the transaction stays pinned to the secondary endpoint
+		primary.On("Ping").Return(nil).Once()
+		primary.On("GetNodeVersionInfo", ctx).Return(&flow.NodeVersionInfo{}, nil).Once()
+		secondary.On("Ping").Return(nil).Once()
+		secondary.On("GetNodeVersionInfo", ctx).Return(&flow.NodeVersionInfo{}, nil).Once()
+		g.CheckHealth(ctx)
+
+		secondary.On("GetTransactionResult", ctx, tx.ID(), true).
+			Return(&flow.TransactionResult{Status: flow.TransactionStatusSealed}, nil).Once()
+		result, err := g.GetTransactionResult(ctx, tx.ID(), true)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+		assert.Empty(t, g.pinned)
+	})
+
+	t.Run("Bound pinned transactions", func(t *testing.T) {
+		defaultMax := maxPinned
+		defer func() { maxPinned = defaultMax }()
+		maxPinned = 2
+
+		primary := mocks.NewGateway(t)
+		g, err := NewFailoverGateway(FailoverEndpoint{Host: "primary:9000", Gateway: primary})
+		require.NoError(t, err)
+
+		txs := make([]*flow.Transaction, 3)
+		for i := range txs {
+			txs[i] = flow.NewTransaction().SetScript([]byte("transaction {}")).SetComputeLimit(uint64(i + 1))
+			primary.On("SendSignedTransaction", ctx, txs[i]).Return(txs[i], nil).Once()
+			_, err := g.SendSignedTransaction(ctx, txs[i])
+			require.NoError(t, err)
+		}
+
+		// the oldest pin is dropped when the limit is reached
+		require.Len(t, g.pinned, 2)
+		assert.NotContains(t, g.pinned, txs[0].ID())
+
+		// expired pins are dropped when a transaction is pinned
+		for ID, pin := range g.pinned {
+			pin.expires = time.Now()
+			g.pinned[ID] = pin
+		}
+		tx := flow.NewTransaction().SetScript([]byte("transaction {}"))
+		primary.On("SendSignedTransaction", ctx, tx).Return(tx, nil).Once()
+		_, err = g.SendSignedTransaction(ctx, tx)
+		require.NoError(t, err)
+		assert.Len(t, g.pinned, 1)
+	})
+
+	t.Run("Sample latency only from short reads", func(t *testing.T) {
+		primary := mocks.NewGateway(t)
+		g, err := NewFailoverGateway(FailoverEndpoint{Host: "primary:9000", Gateway: primary})
+		require.NoError(t, err)
+
+		primary.On("GetLatestBlock", ctx).Return(block, nil).Once()
+		_, err = g.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		latency := g.Health()[0].Latency
+
+		ID := flow.HexToID("01")
+		primary.On("GetTransactionResult", ctx, ID, true).
+			After(50*time.Millisecond).
+			Return(&flow.TransactionResult{Status: flow.TransactionStatusSealed}, nil).Once()
+		_, err = g.GetTransactionResult(ctx, ID, true)
+		require.NoError(t, err)
+
+		health := g.Health()[0]
+		assert.True(t, health.Healthy)
+		assert.Equal(t, latency, health.Latency)
+	})
+
+	t.Run("Don't resubmit possibly delivered transaction", func(t *testing.T) {
+		g, primary, _ := newFailover(t)
+		tx := flow.NewTransaction().SetScript([]byte("transaction {}"))
+		primary.On("SendSignedTransaction", ctx, tx).Return(nil, unavailable).Once()
+
+		_, err := g.SendSignedTransaction(ctx, tx)
+		assert.ErrorIs(t, err, unavailable)
+		assert.False(t, g.Health()[0].Healthy)
+	})
+
+	t.Run("Ping any endpoint", func(t *testing.T) {
+		g, primary, secondary := newFailover(t)
+		primary.On("Ping").Return(unavailable).Once()
+		secondary.On("Ping").Return(nil).Once()
+
+		assert.NoError(t, g.Ping())
+	})
+
+	t.Run("Secure connection", func(t *testing.T) {
+		g, primary, secondary := newFailover(t)
+		primary.On("SecureConnection").Return(true).Once()
+		secondary.On("SecureConnection").Return(false).Once()
+
+		assert.False(t, g.SecureConnection())
+	})
+
+	t.Run("Create from network", func(t *testing.T) {
+		network := config.Network{
+			Name:  "testnet",
+			Host:  "access-1:9000",
+			Hosts: []string{"access-2:9000", "access-1:9000"},
+		}
+
+		var hosts []string
+		g, err := NewNetworkFailoverGateway(network, func(n config.Network) (Gateway, error) {
+			hosts = append(hosts, n.Host)
+			assert.Empty(t, n.Hosts)
+			return mocks.NewGateway(t), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"access-1:9000", "access-2:9000"}, hosts)
+		assert.Len(t, g.Health(), 2)
+
+		_, err = NewNetworkFailoverGateway(config.Network{Name: "empty"}, func(n config.Network) (Gateway, error) {
+			return nil, nil
+		})
+		assert.Error(t, err)
+
+		_, err = NewNetworkFailoverGateway(network, func(n config.Network) (Gateway, error) {
+			return nil, errors.New("failed")
+		})
+		assert.Error(t, err)
+		keyNetwork := network
+		keyNetwork.Key = "5000676131ad3e22d853a3f75a5b5d0db4236d08dd6612e2baad771014b5266a242bccecc3522ff7207ac357dbe4f225c709d9b273ac484fed5d13976a39bdcd"
+		_, err = NewNetworkFailoverGateway(keyNetwork, func(n config.Network) (Gateway, error) {
+			return mocks.NewGateway(t), nil
+		})
+		assert.ErrorContains(t, err, "key can't be used with multiple hosts")
+	})
+}
@@ -0,0 +1,114 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-emulator/storage/remote"
+	"github.com/onflow/flow-emulator/storage/sqlite"
+	flowGo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/executiondata"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+// NewForkedEmulatorGateway creates an emulator gateway forked from the latest sealed state of the network.
+//
+// The emulator reads the registers it doesn't have yet from the network, so it starts with the accounts and
+// contracts of the network, while nothing executed on the emulator is ever sent to the network.
+// The network must use the gRPC API, and Close must be called to close the connection to the network.
+func NewForkedEmulatorGateway(network config.Network, opts ...func(*EmulatorGateway)) (*EmulatorGateway, error) {
+	conn, err := dialNetwork(network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to network %s: %w", network.Name, err)
+	}
+
+	gateway, err := newForkedEmulatorGateway(
+		access.NewAccessAPIClient(conn),
+		executiondata.NewExecutionDataAPIClient(conn),
+		opts...,
+	)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to fork network %s: %w", network.Name, err)
+	}
+
+	gateway.forkConn = conn
+	return gateway, nil
+}
+
+func newForkedEmulatorGateway(
+	accessClient access.AccessAPIClient,
+	executionClient executiondata.ExecutionDataAPIClient,
+	opts ...func(*EmulatorGateway),
+) (*EmulatorGateway, error) {
+	params, err := accessClient.GetNetworkParameters(context.Background(), &access.GetNetworkParametersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network parameters: %w", err)
+	}
+
+	base, err := sqlite.New(sqlite.InMemory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage: %w", err)
+	}
+
+	fork := &forkClients{access: accessClient, execution: executionClient}
+	store, err := fork.store(base)
+	if err != nil {
+		_ = base.Close()
+		return nil, err
+	}
+
+	// the forked storage and chain are applied last so they can't be replaced by the provided options
+	gateway, err := newEmulatorGateway(nil, append(opts, withStorage(base, ""), WithEmulatorOptions(
+		emulator.WithStore(store),
+		emulator.WithChainID(flowGo.ChainID(params.ChainId)),
+	))...)
+	if err != nil {
+		return nil, err
+	}
+
+	gateway.fork = fork
+
+	// commit a block on top of the forked state so the sent transactions have a reference block
+	if _, _, err := gateway.emulator.ExecuteAndCommitBlock(); err != nil {
+		_ = gateway.Close()
+		return nil, fmt.Errorf("failed to commit the initial block: %w", err)
+	}
+
+	return gateway, nil
+}
+
+// forkClients are the clients a forked emulator reads the network state with.
+type forkClients struct {
+	access    access.AccessAPIClient
+	execution executiondata.ExecutionDataAPIClient
+}
+
+// store returns the storage reading the registers missing from the base storage from the network, at the fork height
+// saved in the base storage or at the latest sealed height if the base storage wasn't forked yet.
+func (c *forkClients) store(base *sqlite.Store) (*remote.Store, error) {
+	logger := zerolog.Nop()
+	return remote.New(base, &logger, remote.WithClient(c.execution, c.access))
+}
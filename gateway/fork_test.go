@@ -0,0 +1,172 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	flowGo "github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/executiondata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+// forkSource serves the access and execution data APIs a forked emulator reads the network with
+// from the state of another emulator.
+type forkSource struct {
+	access.AccessAPIClient
+	executiondata.ExecutionDataAPIClient
+	gateway *EmulatorGateway
+	reads   atomic.Int64
+}
+
+func (s *forkSource) GetNetworkParameters(
+	context.Context,
+	*access.GetNetworkParametersRequest,
+	...grpc.CallOption,
+) (*access.GetNetworkParametersResponse, error) {
+	return &access.GetNetworkParametersResponse{ChainId: s.gateway.ChainID().String()}, nil
+}
+
+func (s *forkSource) GetLatestBlockHeader(
+	context.Context,
+	*access.GetLatestBlockHeaderRequest,
+	...grpc.CallOption,
+) (*access.BlockHeaderResponse, error) {
+	block, err := s.gateway.emulator.GetLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	return blockHeaderResponse(block)
+}
+
+func (s *forkSource) GetBlockHeaderByHeight(
+	_ context.Context,
+	req *access.GetBlockHeaderByHeightRequest,
+	_ ...grpc.CallOption,
+) (*access.BlockHeaderResponse, error) {
+	block, err := s.gateway.emulator.GetBlockByHeight(req.Height)
+	if err != nil {
+		return nil, err
+	}
+	return blockHeaderResponse(block)
+}
+
+func (s *forkSource) GetRegisterValues(
+	ctx context.Context,
+	req *executiondata.GetRegisterValuesRequest,
+	_ ...grpc.CallOption,
+) (*executiondata.GetRegisterValuesResponse, error) {
+	s.reads.Add(1)
+
+	ledger, err := s.gateway.store.LedgerByHeight(ctx, req.BlockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &executiondata.GetRegisterValuesResponse{}
+	for _, id := range req.RegisterIds {
+		value, err := ledger.Get(flowGo.RegisterID{Owner: string(id.Owner), Key: string(id.Key)})
+		if err != nil {
+			return nil, err
+		}
+		res.Values = append(res.Values, value)
+	}
+	return res, nil
+}
+
+func blockHeaderResponse(block *flowGo.Block) (*access.BlockHeaderResponse, error) {
+	header, err := convert.BlockHeaderToMessage(block.ToHeader(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &access.BlockHeaderResponse{Block: header}, nil
+}
+
+func TestForkedEmulatorGateway(t *testing.T) {
+	ctx := context.Background()
+
+	source, err := newEmulatorGateway(nil, WithManualMining())
+	require.NoError(t, err)
+	defer source.Close()
+
+	// the fork starts a few blocks below the latest sealed block of the network
+	for range 12 {
+		_, err := source.CommitBlock()
+		require.NoError(t, err)
+	}
+	serviceAddress := flow.Address(source.emulator.GetChain().ServiceAddress())
+	serviceAccount, err := source.GetAccount(ctx, serviceAddress)
+	require.NoError(t, err)
+
+	network := &forkSource{gateway: source}
+	g, err := newForkedEmulatorGateway(network, network, WithManualMining())
+	require.NoError(t, err)
+
+	t.Run("Fork the network chain and state", func(t *testing.T) {
+		assert.Equal(t, source.ChainID(), g.ChainID())
+
+		account, err := g.GetAccount(ctx, serviceAddress)
+		require.NoError(t, err)
+		assert.Equal(t, serviceAccount.Keys[0].PublicKey, account.Keys[0].PublicKey)
+		assert.Positive(t, network.reads.Load())
+	})
+
+	t.Run("Keep the blocks local", func(t *testing.T) {
+		latest, err := source.GetLatestBlock(ctx)
+		require.NoError(t, err)
+
+		_, err = g.CommitBlock()
+		require.NoError(t, err)
+
+		block, err := source.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, latest.ID, block.ID)
+	})
+
+	t.Run("Copy reads the network state", func(t *testing.T) {
+		copied, err := g.Copy()
+		require.NoError(t, err)
+		defer copied.Close()
+
+		account, err := copied.GetAccount(ctx, serviceAddress)
+		require.NoError(t, err)
+		assert.Equal(t, serviceAccount.Keys[0].PublicKey, account.Keys[0].PublicKey)
+	})
+
+	t.Run("Require a gRPC host", func(t *testing.T) {
+		_, err := NewForkedEmulatorGateway(config.Network{Name: "testnet", Host: "https://rest-testnet.onflow.org"})
+		assert.ErrorContains(t, err, "network testnet uses the REST API but a gRPC host is required")
+	})
+
+	t.Run("Close the storage", func(t *testing.T) {
+		require.NoError(t, g.Close())
+
+		_, err := g.GetLatestBlock(ctx)
+		assert.Error(t, err)
+	})
+}
@@ -50,7 +50,56 @@ type Gateway interface {
 	GetCollection(context.Context, flow.Identifier) (*flow.Collection, error)
 	GetLatestProtocolStateSnapshot(context.Context) ([]byte, error)
 	GetNodeVersionInfo(context.Context) (*flow.NodeVersionInfo, error)
+	GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error)
+	GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionData, error)
+
+	// The subscriptions stream sealed data starting at the provided height, or at the latest sealed block if the height is zero.
+	// The channels are closed once the context is cancelled or after an error is sent to the error channel.
+	SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error)
+	SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error)
+	SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error)
+	SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error)
+
 	Ping() error
 	WaitServer(context.Context) error
 	SecureConnection() bool
 }
+
+// Wrapper is implemented by the gateway decorators wrapping a single gateway.
+type Wrapper interface {
+	Unwrap() Gateway
+}
+
+// As finds the first gateway implementing T in the chain of gateways wrapped by the decorators, starting with
+// the provided gateway, so the capabilities of a wrapped gateway like ManualMiner are found through the decorators.
+func As[T any](gateway Gateway) (T, bool) {
+	for gateway != nil {
+		if found, ok := gateway.(T); ok {
+			return found, true
+		}
+		wrapper, ok := gateway.(Wrapper)
+		if !ok {
+			break
+		}
+		gateway = wrapper.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
+// ManualMiner is implemented by the gateways that can produce blocks on request instead of automatically.
+// While manual mining is on, the sent transactions stay pending until the block is committed.
+type ManualMiner interface {
+	ManualMining() bool
+	CommitBlock() (*flow.Block, error)
+}
+
+// Snapshotter is implemented by the gateways that can save the chain state under a name and restore it later.
+// Services users can check whether the gateway supports snapshots with As.
+type Snapshotter interface {
+	CreateSnapshot(name string) error
+	LoadSnapshot(name string) error
+	Snapshots() ([]string, error)
+	DeleteSnapshot(name string) error
+}
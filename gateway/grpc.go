@@ -45,13 +45,25 @@ type GrpcGateway struct {
 }
 
 // NewGrpcGateway returns a new gRPC gateway.
-
+//
+// The connection settings of the network are applied to the client, the connection is
+// insecure unless TLS is enabled for the network.
 func NewGrpcGateway(network config.Network, opts ...grpcAccess.ClientOption) (*GrpcGateway, error) {
+	dialOpts, err := networkDialOptions(network)
+	if err != nil {
+		return nil, err
+	}
+	if network.TLS.Enabled {
+		creds, err := networkTransportCredentials(network.TLS)
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+	}
+
 	options := append(
 		[]grpcAccess.ClientOption{
-			grpcAccess.WithGRPCDialOptions(
-				grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxGRPCMessageSize)),
-			),
+			grpcAccess.WithGRPCDialOptions(dialOpts...),
 		},
 		opts...,
 	)
@@ -67,21 +79,29 @@ func NewGrpcGateway(network config.Network, opts ...grpcAccess.ClientOption) (*G
 
 	return &GrpcGateway{
 		client:       gClient,
-		secureClient: false,
+		secureClient: network.TLS.Enabled,
 	}, nil
 }
 
 // NewSecureGrpcGateway returns a new gRPC gateway with a secure client connection.
+//
+// The connection is secured with the network node key, so the network TLS settings can't be used.
 func NewSecureGrpcGateway(network config.Network, opts ...grpc.DialOption) (*GrpcGateway, error) {
+	if network.TLS.Enabled {
+		return nil, fmt.Errorf("TLS settings can not be used with the secure connection of network %s", network.Name)
+	}
+
 	secureDialOpts, err := grpcutils.SecureGRPCDialOpt(strings.TrimPrefix(network.Key, "0x"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create secure GRPC dial options with network key \"%s\": %w", network.Key, err)
 	}
 
-	options := []grpc.DialOption{
-		secureDialOpts,
-		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxGRPCMessageSize)),
+	dialOpts, err := networkDialOptions(network)
+	if err != nil {
+		return nil, err
 	}
+
+	options := append([]grpc.DialOption{secureDialOpts}, dialOpts...)
 	options = append(options, opts...)
 
 	gClient, err := grpcAccess.NewClient(
@@ -279,6 +299,16 @@ func (g *GrpcGateway) GetCollection(ctx context.Context, id flow.Identifier) (*f
 	return g.client.GetCollection(ctx, id)
 }
 
+// GetExecutionResultForBlockID gets the execution result of the block from the Flow Access API.
+func (g *GrpcGateway) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	return g.client.GetExecutionResultForBlockID(ctx, blockID)
+}
+
+// GetExecutionDataByBlockID gets the execution data of the block chunks from the Flow Access API.
+func (g *GrpcGateway) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	return g.client.GetExecutionDataByBlockID(ctx, blockID)
+}
+
 // GetLatestProtocolStateSnapshot gets the latest finalized protocol state snapshot
 func (g *GrpcGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
 	return g.client.GetLatestProtocolStateSnapshot(ctx)
@@ -289,6 +319,83 @@ func (g *GrpcGateway) GetNodeVersionInfo(ctx context.Context) (*flow.NodeVersion
 	return g.client.GetNodeVersionInfo(ctx)
 }
 
+// SubscribeBlocks streams the sealed blocks through the Access API streaming endpoint.
+func (g *GrpcGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	var blocks <-chan *flow.Block
+	var errs <-chan error
+	var err error
+	if startHeight == 0 {
+		blocks, errs, err = g.client.SubscribeBlocksFromLatest(ctx, flow.BlockStatusSealed)
+	} else {
+		blocks, errs, err = g.client.SubscribeBlocksFromStartHeight(ctx, startHeight, flow.BlockStatusSealed)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to subscribe to blocks: %w", err)
+	}
+
+	return blocks, errs, nil
+}
+
+// SubscribeBlockHeaders streams the sealed block headers through the Access API streaming endpoint.
+func (g *GrpcGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	var headers <-chan *flow.BlockHeader
+	var errs <-chan error
+	var err error
+	if startHeight == 0 {
+		headers, errs, err = g.client.SubscribeBlockHeadersFromLatest(ctx, flow.BlockStatusSealed)
+	} else {
+		headers, errs, err = g.client.SubscribeBlockHeadersFromStartHeight(ctx, startHeight, flow.BlockStatusSealed)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to subscribe to block headers: %w", err)
+	}
+
+	return headers, errs, nil
+}
+
+// SubscribeEvents streams the events matching the filter through the Access API streaming endpoint.
+func (g *GrpcGateway) SubscribeEvents(
+	ctx context.Context,
+	startHeight uint64,
+	filter flow.EventFilter,
+) (<-chan flow.BlockEvents, <-chan error, error) {
+	if startHeight == 0 {
+		latest, err := g.GetLatestBlock(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		startHeight = latest.Height
+	}
+
+	events, errs, err := g.client.SubscribeEventsByBlockHeight(ctx, startHeight, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+
+	return events, errs, nil
+}
+
+// SubscribeAccountStatuses streams the account status updates matching the filter through the Access API streaming endpoint.
+func (g *GrpcGateway) SubscribeAccountStatuses(
+	ctx context.Context,
+	startHeight uint64,
+	filter flow.AccountStatusFilter,
+) (<-chan *flow.AccountStatus, <-chan error, error) {
+	var statuses <-chan *flow.AccountStatus
+	var errs <-chan error
+	var err error
+	if startHeight == 0 {
+		statuses, errs, err = g.client.SubscribeAccountStatusesFromLatestBlock(ctx, filter)
+	} else {
+		statuses, errs, err = g.client.SubscribeAccountStatusesFromStartHeight(ctx, startHeight, filter)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to subscribe to account statuses: %w", err)
+	}
+
+	return statuses, errs, nil
+}
+
 // Ping is used to check if the access node is alive and healthy.
 func (g *GrpcGateway) Ping() error {
 	ctx := context.Background()
@@ -287,6 +287,66 @@ func (_m *Gateway) GetEvents(_a0 context.Context, _a1 string, _a2 uint64, _a3 ui
 	return r0, r1
 }
 
+// GetExecutionDataByBlockID provides a mock function with given fields: ctx, blockID
+func (_m *Gateway) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	ret := _m.Called(ctx, blockID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExecutionDataByBlockID")
+	}
+
+	var r0 *flow.ExecutionData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) (*flow.ExecutionData, error)); ok {
+		return rf(ctx, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flow.ExecutionData); ok {
+		r0 = rf(ctx, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.ExecutionData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(ctx, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExecutionResultForBlockID provides a mock function with given fields: ctx, blockID
+func (_m *Gateway) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	ret := _m.Called(ctx, blockID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExecutionResultForBlockID")
+	}
+
+	var r0 *flow.ExecutionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) (*flow.ExecutionResult, error)); ok {
+		return rf(ctx, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flow.ExecutionResult); ok {
+		r0 = rf(ctx, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.ExecutionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(ctx, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLatestBlock provides a mock function with given fields: _a0
 func (_m *Gateway) GetLatestBlock(_a0 context.Context) (*flow.Block, error) {
 	ret := _m.Called(_a0)
@@ -683,6 +743,162 @@ func (_m *Gateway) SendSignedTransaction(_a0 context.Context, _a1 *flow.Transact
 	return r0, r1
 }
 
+// SubscribeAccountStatuses provides a mock function with given fields: ctx, startHeight, filter
+func (_m *Gateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	ret := _m.Called(ctx, startHeight, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeAccountStatuses")
+	}
+
+	var r0 <-chan *flow.AccountStatus
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error)); ok {
+		return rf(ctx, startHeight, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.AccountStatusFilter) <-chan *flow.AccountStatus); ok {
+		r0 = rf(ctx, startHeight, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *flow.AccountStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, flow.AccountStatusFilter) <-chan error); ok {
+		r1 = rf(ctx, startHeight, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, flow.AccountStatusFilter) error); ok {
+		r2 = rf(ctx, startHeight, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SubscribeBlockHeaders provides a mock function with given fields: ctx, startHeight
+func (_m *Gateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	ret := _m.Called(ctx, startHeight)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeBlockHeaders")
+	}
+
+	var r0 <-chan *flow.BlockHeader
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (<-chan *flow.BlockHeader, <-chan error, error)); ok {
+		return rf(ctx, startHeight)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) <-chan *flow.BlockHeader); ok {
+		r0 = rf(ctx, startHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *flow.BlockHeader)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) <-chan error); ok {
+		r1 = rf(ctx, startHeight)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64) error); ok {
+		r2 = rf(ctx, startHeight)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SubscribeBlocks provides a mock function with given fields: ctx, startHeight
+func (_m *Gateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	ret := _m.Called(ctx, startHeight)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeBlocks")
+	}
+
+	var r0 <-chan *flow.Block
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (<-chan *flow.Block, <-chan error, error)); ok {
+		return rf(ctx, startHeight)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) <-chan *flow.Block); ok {
+		r0 = rf(ctx, startHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *flow.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) <-chan error); ok {
+		r1 = rf(ctx, startHeight)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64) error); ok {
+		r2 = rf(ctx, startHeight)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SubscribeEvents provides a mock function with given fields: ctx, startHeight, filter
+func (_m *Gateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	ret := _m.Called(ctx, startHeight, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeEvents")
+	}
+
+	var r0 <-chan flow.BlockEvents
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error)); ok {
+		return rf(ctx, startHeight, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.EventFilter) <-chan flow.BlockEvents); ok {
+		r0 = rf(ctx, startHeight, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan flow.BlockEvents)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, flow.EventFilter) <-chan error); ok {
+		r1 = rf(ctx, startHeight, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, flow.EventFilter) error); ok {
+		r2 = rf(ctx, startHeight, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // WaitServer provides a mock function with given fields: _a0
 func (_m *Gateway) WaitServer(_a0 context.Context) error {
 	ret := _m.Called(_a0)
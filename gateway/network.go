@@ -0,0 +1,227 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onflow/flow-go/utils/grpcutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+// NewNetworkGateway creates the gateway for the network configuration.
+//
+// Hosts with an http or https scheme use the REST API, any other host uses the gRPC API and
+// is secured with the network node key if one is defined. REST hosts are sent the network headers
+// and timeout, while the TLS, keepalive and max message size settings are only supported by gRPC hosts.
+// Networks with additional hosts get a failover gateway across all the hosts, and the gateway is
+// wrapped with the network retry policy.
+func NewNetworkGateway(network config.Network) (Gateway, error) {
+	hosts := network.AccessHosts()
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("network %s doesn't define any hosts", network.Name)
+	}
+
+	var gw Gateway
+	var err error
+	if len(hosts) > 1 {
+		gw, err = NewNetworkFailoverGateway(network, newHostGateway)
+	} else {
+		gw, err = newHostGateway(network)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNetworkRetryGateway(gw, network), nil
+}
+
+// newHostGateway creates the gateway for the primary host of the network.
+func newHostGateway(network config.Network) (Gateway, error) {
+	if isRestHost(network.Host) {
+		if settings := grpcConnectionSettings(network); len(settings) > 0 {
+			return nil, fmt.Errorf(
+				"gRPC connection settings %s of network %s are not supported by the REST gateway",
+				strings.Join(settings, ", "),
+				network.Name,
+			)
+		}
+		return NewRestGateway(network)
+	}
+
+	if network.Key != "" {
+		return NewSecureGrpcGateway(network)
+	}
+	return NewGrpcGateway(network)
+}
+
+// grpcConnectionSettings returns the names of the defined network connection settings that only apply to gRPC
+// connections, the headers and timeout are applied by the REST gateway as well.
+func grpcConnectionSettings(network config.Network) []string {
+	var settings []string
+	if !network.TLS.IsEmpty() {
+		settings = append(settings, "tls")
+	}
+	if !network.Keepalive.IsEmpty() {
+		settings = append(settings, "keepalive")
+	}
+	if network.MaxMessageSize != 0 {
+		settings = append(settings, "maxMessageSize")
+	}
+	return settings
+}
+
+// isRestHost returns true if the host is a URL of the REST API.
+func isRestHost(host string) bool {
+	return strings.HasPrefix(host, "http://") || strings.HasPrefix(host, "https://")
+}
+
+// dialNetwork opens a gRPC connection to the primary host of the network with the network connection settings,
+// the connection is secured with the node key or TLS settings of the network if defined.
+func dialNetwork(network config.Network) (*grpc.ClientConn, error) {
+	if isRestHost(network.Host) {
+		return nil, fmt.Errorf("network %s uses the REST API but a gRPC host is required", network.Name)
+	}
+
+	options, err := networkDialOptions(network)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case network.Key != "":
+		secureDialOpts, err := grpcutils.SecureGRPCDialOpt(strings.TrimPrefix(network.Key, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create secure GRPC dial options with network key \"%s\": %w", network.Key, err)
+		}
+		options = append(options, secureDialOpts)
+	case network.TLS.Enabled:
+		creds, err := networkTransportCredentials(network.TLS)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.WithTransportCredentials(creds))
+	default:
+		options = append(options, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	return grpc.NewClient(network.Host, options...)
+}
+
+// networkDialOptions returns the gRPC dial options for the network connection settings, except for the transport credentials.
+func networkDialOptions(network config.Network) ([]grpc.DialOption, error) {
+	if network.MaxMessageSize < 0 {
+		return nil, fmt.Errorf("invalid max message size %d for network %s", network.MaxMessageSize, network.Name)
+	}
+
+	maxMessageSize := maxGRPCMessageSize
+	if network.MaxMessageSize > 0 {
+		maxMessageSize = network.MaxMessageSize
+	}
+
+	options := []grpc.DialOption{
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize)),
+	}
+
+	if !network.Keepalive.IsEmpty() {
+		options = append(options, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                network.Keepalive.Time,
+			Timeout:             network.Keepalive.Timeout,
+			PermitWithoutStream: network.Keepalive.PermitWithoutStream,
+		}))
+	}
+
+	if len(network.Headers) > 0 || network.Timeout > 0 {
+		options = append(
+			options,
+			grpc.WithChainUnaryInterceptor(unaryCallInterceptor(network)),
+			grpc.WithChainStreamInterceptor(streamCallInterceptor(network)),
+		)
+	}
+
+	return options, nil
+}
+
+// withHeaders returns the context with the network headers added to the outgoing metadata.
+func withHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+
+	pairs := make([]string, 0, len(headers)*2)
+	for name, value := range headers {
+		pairs = append(pairs, name, value)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// unaryCallInterceptor adds the network headers to the calls and limits each call to the network timeout.
+func unaryCallInterceptor(network config.Network) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if network.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, network.Timeout)
+			defer cancel()
+		}
+
+		return invoker(withHeaders(ctx, network.Headers), method, req, reply, cc, opts...)
+	}
+}
+
+// streamCallInterceptor adds the network headers to the streams, the timeout isn't applied since streams are long-lived.
+func streamCallInterceptor(network config.Network) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withHeaders(ctx, network.Headers), desc, cc, method, opts...)
+	}
+}
+
+// networkTransportCredentials returns the TLS credentials verifying the server with the system
+// certificate pool, or with the CA bundle if the CA file is defined.
+func networkTransportCredentials(settings config.NetworkTLS) (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: settings.ServerName,
+	}
+
+	if settings.CAFile != "" {
+		ca, err := os.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", settings.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
@@ -0,0 +1,166 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+func TestNewNetworkGateway(t *testing.T) {
+	t.Run("gRPC host", func(t *testing.T) {
+		gw, err := NewNetworkGateway(config.EmulatorNetwork)
+		require.NoError(t, err)
+		assert.IsType(t, &GrpcGateway{}, gw)
+		assert.False(t, gw.SecureConnection())
+	})
+
+	t.Run("gRPC host with TLS", func(t *testing.T) {
+		network := config.TestnetNetwork
+		network.TLS = config.NetworkTLS{Enabled: true}
+
+		gw, err := NewNetworkGateway(network)
+		require.NoError(t, err)
+		assert.IsType(t, &GrpcGateway{}, gw)
+		assert.True(t, gw.SecureConnection())
+	})
+
+	t.Run("REST host", func(t *testing.T) {
+		gw, err := NewNetworkGateway(config.Network{Name: "testnet", Host: "https://rest-testnet.onflow.org/v1"})
+		require.NoError(t, err)
+		assert.IsType(t, &RestGateway{}, gw)
+	})
+
+	t.Run("Failover and retry", func(t *testing.T) {
+		network := config.Network{
+			Name:  "testnet",
+			Host:  "127.0.0.1:3569",
+			Hosts: []string{"127.0.0.1:3570"},
+		}
+		gw, err := NewNetworkGateway(network)
+		require.NoError(t, err)
+		assert.IsType(t, &FailoverGateway{}, gw)
+
+		network.Retry = config.NetworkRetry{MaxAttempts: 3}
+		gw, err = NewNetworkGateway(network)
+		require.NoError(t, err)
+		assert.IsType(t, &RetryGateway{}, gw)
+	})
+
+	t.Run("Fail without hosts", func(t *testing.T) {
+		_, err := NewNetworkGateway(config.Network{Name: "empty"})
+		assert.EqualError(t, err, "network empty doesn't define any hosts")
+	})
+
+	t.Run("REST host with headers and timeout", func(t *testing.T) {
+		gw, err := NewNetworkGateway(config.Network{
+			Name:    "testnet",
+			Host:    "https://rest-testnet.onflow.org/v1",
+			Headers: map[string]string{"x-api-key": "secret"},
+			Timeout: time.Second,
+		})
+		require.NoError(t, err)
+		assert.IsType(t, &RestGateway{}, gw)
+	})
+
+	t.Run("Fail REST host with gRPC connection settings", func(t *testing.T) {
+		_, err := NewNetworkGateway(config.Network{
+			Name:           "testnet",
+			Host:           "https://rest-testnet.onflow.org/v1",
+			Headers:        map[string]string{"x-api-key": "secret"},
+			Keepalive:      config.NetworkKeepalive{Time: time.Minute},
+			MaxMessageSize: 1024,
+		})
+		assert.EqualError(t, err, "gRPC connection settings keepalive, maxMessageSize of network testnet are not supported by the REST gateway")
+	})
+
+	t.Run("Fail TLS with node key", func(t *testing.T) {
+		_, err := NewNetworkGateway(config.Network{
+			Name: "testnet",
+			Host: "127.0.0.1:3569",
+			Key:  "5000676131ad3e22d853a3f75a5b5d0db4236d08dd6612e2baad771014b5266a242bccecc3522ff7207ac357dbe4f225c709d9b273ac484fed5d13976a39bdcd",
+			TLS:  config.NetworkTLS{Enabled: true},
+		})
+		assert.EqualError(t, err, "TLS settings can not be used with the secure connection of network testnet")
+	})
+
+	t.Run("Fail invalid CA file", func(t *testing.T) {
+		network := config.Network{Name: "testnet", Host: "127.0.0.1:3569"}
+
+		network.TLS = config.NetworkTLS{Enabled: true, CAFile: filepath.Join(t.TempDir(), "missing.pem")}
+		_, err := NewNetworkGateway(network)
+		assert.ErrorContains(t, err, "failed to read CA file")
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0644))
+		network.TLS = config.NetworkTLS{Enabled: true, CAFile: caFile}
+		_, err = NewNetworkGateway(network)
+		assert.ErrorContains(t, err, "no valid certificates found in CA file")
+	})
+}
+
+func TestNetworkCallInterceptors(t *testing.T) {
+	network := config.Network{
+		Name:    "testnet",
+		Headers: map[string]string{"x-api-key": "secret"},
+		Timeout: time.Minute,
+	}
+
+	t.Run("Unary calls", func(t *testing.T) {
+		invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, []string{"secret"}, md.Get("x-api-key"))
+
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+			return nil
+		}
+
+		err := unaryCallInterceptor(network)(context.Background(), "/flow.access.AccessAPI/Ping", nil, nil, nil, invoker)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Stream calls", func(t *testing.T) {
+		streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, []string{"secret"}, md.Get("x-api-key"))
+
+			// streams are long-lived so the call timeout isn't applied
+			_, ok = ctx.Deadline()
+			assert.False(t, ok)
+			return nil, nil
+		}
+
+		_, err := streamCallInterceptor(network)(context.Background(), &grpc.StreamDesc{}, nil, "/flow.access.AccessAPI/SubscribeBlocksFromLatest", streamer)
+		assert.NoError(t, err)
+	})
+}
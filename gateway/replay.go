@@ -0,0 +1,761 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Interaction is a recorded gateway request with its response or error.
+type Interaction struct {
+	Method   string            `json:"method"`
+	Request  json.RawMessage   `json:"request,omitempty"`
+	Response json.RawMessage   `json:"response,omitempty"`
+	Error    *InteractionError `json:"error,omitempty"`
+}
+
+// InteractionError is a recorded gateway error, the gRPC status code is kept so the replayed error can be classified.
+type InteractionError struct {
+	Code    codes.Code `json:"code,omitempty"`
+	Message string     `json:"message"`
+}
+
+func newInteractionError(err error) *InteractionError {
+	return &InteractionError{
+		Code:    status.Code(err),
+		Message: err.Error(),
+	}
+}
+
+// replayedError is the replayed gateway error with the recorded message and gRPC status.
+type replayedError struct {
+	message string
+	code    codes.Code
+}
+
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+func (e *replayedError) GRPCStatus() *status.Status {
+	return status.New(e.code, e.message)
+}
+
+func (e *InteractionError) err() error {
+	if e.Code == codes.Unknown || e.Code == codes.OK {
+		return errors.New(e.Message)
+	}
+	return &replayedError{message: e.Message, code: e.Code}
+}
+
+// Fixture is a recorded gateway session.
+//
+// Responses are encoded as JSON, with the Cadence values encoded as JSON-CDC.
+type Fixture struct {
+	SecureConnection bool          `json:"secureConnection"`
+	Interactions     []Interaction `json:"interactions"`
+}
+
+// LoadFixture loads the fixture from the file.
+func LoadFixture(path string) (*Fixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+
+	// requests are matched by their encoding, so the indentation of the file is removed
+	for i, interaction := range fixture.Interactions {
+		if len(interaction.Request) == 0 {
+			continue
+		}
+
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, interaction.Request); err != nil {
+			return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+		}
+		fixture.Interactions[i].Request = compact.Bytes()
+	}
+
+	return &fixture, nil
+}
+
+// Save saves the fixture to the file.
+func (f *Fixture) Save(path string) error {
+	b, err := json.MarshalIndent(f, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("failed to write fixture %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// request describes a gateway request, encoded as the interaction request.
+type request map[string]any
+
+func encodeRequest(req request) (json.RawMessage, error) {
+	if len(req) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(req)
+}
+
+func encodeArguments(arguments []cadence.Value) ([]json.RawMessage, error) {
+	args := make([]json.RawMessage, 0, len(arguments))
+	for _, arg := range arguments {
+		b, err := jsoncdc.Encode(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument: %w", err)
+		}
+		args = append(args, b)
+	}
+
+	return args, nil
+}
+
+func scriptRequest(script []byte, arguments []cadence.Value) (request, error) {
+	args, err := encodeArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return request{"script": string(script), "arguments": args}, nil
+}
+
+// transactionRequest describes the submitted transaction by its payload. Signatures are left out,
+// since signing the same payload again produces different signatures.
+func transactionRequest(tx *flow.Transaction) request {
+	args := make([]json.RawMessage, 0, len(tx.Arguments))
+	for _, arg := range tx.Arguments {
+		args = append(args, arg)
+	}
+
+	return request{
+		"script":           string(tx.Script),
+		"arguments":        args,
+		"referenceBlockId": tx.ReferenceBlockID.String(),
+		"gasLimit":         tx.GasLimit,
+		"proposalKey":      tx.ProposalKey,
+		"payer":            tx.Payer,
+		"authorizers":      tx.Authorizers,
+	}
+}
+
+// emptyCodec is used for calls that only return an error.
+var emptyCodec = responseCodec[struct{}]{
+	encode: func(struct{}) ([]byte, error) { return nil, nil },
+	decode: func([]byte) (struct{}, error) { return struct{}{}, nil },
+}
+
+var _ Gateway = &RecordingGateway{}
+
+// RecordingGateway is a gateway decorator recording all requests to the wrapped gateway
+// and their responses, so they can be replayed by the replay gateway.
+type RecordingGateway struct {
+	gateway Gateway
+	mu      sync.Mutex
+	fixture Fixture
+	err     error
+}
+
+// NewRecordingGateway returns a gateway recording the calls to the provided gateway.
+func NewRecordingGateway(gateway Gateway) *RecordingGateway {
+	return &RecordingGateway{
+		gateway: gateway,
+		fixture: Fixture{
+			SecureConnection: gateway.SecureConnection(),
+			Interactions:     make([]Interaction, 0),
+		},
+	}
+}
+
+// Unwrap returns the wrapped gateway.
+func (g *RecordingGateway) Unwrap() Gateway {
+	return g.gateway
+}
+
+// Fixture returns the recorded session, or an error if any of the interactions failed to be encoded.
+func (g *RecordingGateway) Fixture() (*Fixture, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	fixture := g.fixture
+	fixture.Interactions = append([]Interaction(nil), g.fixture.Interactions...)
+	return &fixture, nil
+}
+
+// Save saves the recorded session to the fixture file.
+func (g *RecordingGateway) Save(path string) error {
+	fixture, err := g.Fixture()
+	if err != nil {
+		return err
+	}
+
+	return fixture.Save(path)
+}
+
+// fail keeps the first error that prevented an interaction from being recorded.
+func (g *RecordingGateway) fail(method string, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err == nil {
+		g.err = fmt.Errorf("failed to record %s: %w", method, err)
+	}
+}
+
+func (g *RecordingGateway) scriptRequest(method string, script []byte, arguments []cadence.Value) request {
+	req, err := scriptRequest(script, arguments)
+	if err != nil {
+		g.fail(method, err)
+		return request{}
+	}
+	return req
+}
+
+// record calls the wrapped gateway and records the interaction.
+func record[T any](g *RecordingGateway, method string, req request, codec responseCodec[T], call func() (T, error)) (T, error) {
+	res, err := call()
+
+	interaction := Interaction{Method: method}
+	encoded, encodeErr := encodeRequest(req)
+	interaction.Request = encoded
+	if err != nil {
+		interaction.Error = newInteractionError(err)
+	} else if encodeErr == nil {
+		interaction.Response, encodeErr = codec.encode(res)
+	}
+
+	if encodeErr != nil {
+		g.fail(method, encodeErr)
+	}
+
+	g.mu.Lock()
+	g.fixture.Interactions = append(g.fixture.Interactions, interaction)
+	g.mu.Unlock()
+
+	return res, err
+}
+
+func (g *RecordingGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	return record(g, "GetAccount", request{"address": address}, accountCodec, func() (*flow.Account, error) {
+		return g.gateway.GetAccount(ctx, address)
+	})
+}
+
+func (g *RecordingGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	req := request{"address": address, "height": height}
+	return record(g, "GetAccountAtBlockHeight", req, accountCodec, func() (*flow.Account, error) {
+		return g.gateway.GetAccountAtBlockHeight(ctx, address, height)
+	})
+}
+
+func (g *RecordingGateway) SendSignedTransaction(ctx context.Context, tx *flow.Transaction) (*flow.Transaction, error) {
+	return record(g, "SendSignedTransaction", transactionRequest(tx), transactionCodec, func() (*flow.Transaction, error) {
+		return g.gateway.SendSignedTransaction(ctx, tx)
+	})
+}
+
+func (g *RecordingGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	return record(g, "GetTransaction", request{"id": ID.String()}, transactionCodec, func() (*flow.Transaction, error) {
+		return g.gateway.GetTransaction(ctx, ID)
+	})
+}
+
+func (g *RecordingGateway) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionResult, error) {
+	req := request{"blockId": blockID.String()}
+	return record(g, "GetTransactionResultsByBlockID", req, resultsCodec, func() ([]*flow.TransactionResult, error) {
+		return g.gateway.GetTransactionResultsByBlockID(ctx, blockID)
+	})
+}
+
+func (g *RecordingGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	req := request{"id": ID.String(), "waitSeal": waitSeal}
+	return record(g, "GetTransactionResult", req, resultCodec, func() (*flow.TransactionResult, error) {
+		return g.gateway.GetTransactionResult(ctx, ID, waitSeal)
+	})
+}
+
+func (g *RecordingGateway) GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.Transaction, error) {
+	req := request{"blockId": blockID.String()}
+	return record(g, "GetTransactionsByBlockID", req, transactionsCodec, func() ([]*flow.Transaction, error) {
+		return g.gateway.GetTransactionsByBlockID(ctx, blockID)
+	})
+}
+
+func (g *RecordingGateway) GetSystemTransaction(ctx context.Context, blockID flow.Identifier) (*flow.Transaction, error) {
+	req := request{"blockId": blockID.String()}
+	return record(g, "GetSystemTransaction", req, transactionCodec, func() (*flow.Transaction, error) {
+		return g.gateway.GetSystemTransaction(ctx, blockID)
+	})
+}
+
+func (g *RecordingGateway) GetSystemTransactionResult(ctx context.Context, blockID flow.Identifier) (*flow.TransactionResult, error) {
+	req := request{"blockId": blockID.String()}
+	return record(g, "GetSystemTransactionResult", req, resultCodec, func() (*flow.TransactionResult, error) {
+		return g.gateway.GetSystemTransactionResult(ctx, blockID)
+	})
+}
+
+func (g *RecordingGateway) GetSystemTransactionWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.Transaction, error) {
+	req := request{"blockId": blockID.String(), "id": systemTxID.String()}
+	return record(g, "GetSystemTransactionWithID", req, transactionCodec, func() (*flow.Transaction, error) {
+		return g.gateway.GetSystemTransactionWithID(ctx, blockID, systemTxID)
+	})
+}
+
+func (g *RecordingGateway) GetSystemTransactionResultWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.TransactionResult, error) {
+	req := request{"blockId": blockID.String(), "id": systemTxID.String()}
+	return record(g, "GetSystemTransactionResultWithID", req, resultCodec, func() (*flow.TransactionResult, error) {
+		return g.gateway.GetSystemTransactionResultWithID(ctx, blockID, systemTxID)
+	})
+}
+
+func (g *RecordingGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	req := g.scriptRequest("ExecuteScript", script, arguments)
+	return record(g, "ExecuteScript", req, valueCodec, func() (cadence.Value, error) {
+		return g.gateway.ExecuteScript(ctx, script, arguments)
+	})
+}
+
+func (g *RecordingGateway) ExecuteScriptAtHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	req := g.scriptRequest("ExecuteScriptAtHeight", script, arguments)
+	req["height"] = height
+	return record(g, "ExecuteScriptAtHeight", req, valueCodec, func() (cadence.Value, error) {
+		return g.gateway.ExecuteScriptAtHeight(ctx, script, arguments, height)
+	})
+}
+
+func (g *RecordingGateway) ExecuteScriptAtID(ctx context.Context, script []byte, arguments []cadence.Value, ID flow.Identifier) (cadence.Value, error) {
+	req := g.scriptRequest("ExecuteScriptAtID", script, arguments)
+	req["blockId"] = ID.String()
+	return record(g, "ExecuteScriptAtID", req, valueCodec, func() (cadence.Value, error) {
+		return g.gateway.ExecuteScriptAtID(ctx, script, arguments, ID)
+	})
+}
+
+func (g *RecordingGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	return record(g, "GetLatestBlock", nil, blockCodec, func() (*flow.Block, error) {
+		return g.gateway.GetLatestBlock(ctx)
+	})
+}
+
+func (g *RecordingGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	return record(g, "GetBlockByHeight", request{"height": height}, blockCodec, func() (*flow.Block, error) {
+		return g.gateway.GetBlockByHeight(ctx, height)
+	})
+}
+
+func (g *RecordingGateway) GetBlockByID(ctx context.Context, ID flow.Identifier) (*flow.Block, error) {
+	return record(g, "GetBlockByID", request{"id": ID.String()}, blockCodec, func() (*flow.Block, error) {
+		return g.gateway.GetBlockByID(ctx, ID)
+	})
+}
+
+func (g *RecordingGateway) GetEvents(ctx context.Context, eventType string, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	req := request{"type": eventType, "startHeight": startHeight, "endHeight": endHeight}
+	return record(g, "GetEvents", req, eventsCodec, func() ([]flow.BlockEvents, error) {
+		return g.gateway.GetEvents(ctx, eventType, startHeight, endHeight)
+	})
+}
+
+func (g *RecordingGateway) GetCollection(ctx context.Context, ID flow.Identifier) (*flow.Collection, error) {
+	return record(g, "GetCollection", request{"id": ID.String()}, collectionCodec, func() (*flow.Collection, error) {
+		return g.gateway.GetCollection(ctx, ID)
+	})
+}
+
+func (g *RecordingGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	return record(g, "GetLatestProtocolStateSnapshot", nil, bytesCodec, func() ([]byte, error) {
+		return g.gateway.GetLatestProtocolStateSnapshot(ctx)
+	})
+}
+
+func (g *RecordingGateway) GetNodeVersionInfo(ctx context.Context) (*flow.NodeVersionInfo, error) {
+	return record(g, "GetNodeVersionInfo", nil, versionCodec, func() (*flow.NodeVersionInfo, error) {
+		return g.gateway.GetNodeVersionInfo(ctx)
+	})
+}
+
+func (g *RecordingGateway) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	req := request{"blockId": blockID.String()}
+	return record(g, "GetExecutionResultForBlockID", req, executionResultCodec, func() (*flow.ExecutionResult, error) {
+		return g.gateway.GetExecutionResultForBlockID(ctx, blockID)
+	})
+}
+
+func (g *RecordingGateway) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	req := request{"blockId": blockID.String()}
+	return record(g, "GetExecutionDataByBlockID", req, executionDataCodec, func() (*flow.ExecutionData, error) {
+		return g.gateway.GetExecutionDataByBlockID(ctx, blockID)
+	})
+}
+
+func (g *RecordingGateway) Ping() error {
+	_, err := record(g, "Ping", nil, emptyCodec, func() (struct{}, error) {
+		return struct{}{}, g.gateway.Ping()
+	})
+	return err
+}
+
+// SubscribeBlocks polls the recording gateway for new blocks, so the polling requests are recorded
+// and the subscription can be replayed.
+func (g *RecordingGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return polling(g, DefaultPollInterval).blocks(ctx, startHeight)
+}
+
+func (g *RecordingGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return polling(g, DefaultPollInterval).blockHeaders(ctx, startHeight)
+}
+
+func (g *RecordingGateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	return polling(g, DefaultPollInterval).events(ctx, startHeight, filter)
+}
+
+func (g *RecordingGateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return polling(g, DefaultPollInterval).accountStatuses(ctx, startHeight, filter)
+}
+
+// WaitServer is not recorded, the replay gateway is always ready.
+func (g *RecordingGateway) WaitServer(ctx context.Context) error {
+	return g.gateway.WaitServer(ctx)
+}
+
+func (g *RecordingGateway) SecureConnection() bool {
+	return g.fixture.SecureConnection
+}
+
+// ReplayMode defines how the requests are matched with the recorded interactions.
+type ReplayMode int
+
+const (
+	// ReplayStrict expects the requests in the recorded order, any other request fails.
+	ReplayStrict ReplayMode = iota
+	// ReplayLenient matches the requests with the recorded interactions in any order,
+	// and an interaction can be replayed multiple times.
+	ReplayLenient
+)
+
+// replayPollInterval is how often the replayed subscriptions poll, there is no reason to wait for recorded blocks.
+const replayPollInterval = 10 * time.Millisecond
+
+var _ Gateway = &ReplayGateway{}
+
+// ReplayGateway is a gateway serving the responses from a recorded session without a network.
+//
+// Transactions are matched by their payload, since signatures differ each time a transaction is signed.
+// The IDs of the replayed transactions are translated to the recorded IDs for the following requests.
+type ReplayGateway struct {
+	fixture *Fixture
+	mode    ReplayMode
+	mu      sync.Mutex
+	next    int
+	used    []bool
+	txIDs   map[flow.Identifier]flow.Identifier
+}
+
+// NewReplayGateway returns a gateway replaying the recorded session.
+func NewReplayGateway(fixture *Fixture, mode ReplayMode) *ReplayGateway {
+	return &ReplayGateway{
+		fixture: fixture,
+		mode:    mode,
+		used:    make([]bool, len(fixture.Interactions)),
+		txIDs:   make(map[flow.Identifier]flow.Identifier),
+	}
+}
+
+// Unused returns the recorded interactions that were not replayed.
+func (g *ReplayGateway) Unused() []Interaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range g.fixture.Interactions {
+		if !g.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
+
+func (g *ReplayGateway) match(method string, req json.RawMessage) (*Interaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	matches := func(i Interaction) bool {
+		return i.Method == method && bytes.Equal(i.Request, req)
+	}
+
+	if g.mode == ReplayStrict {
+		if g.next >= len(g.fixture.Interactions) {
+			return nil, fmt.Errorf("unexpected %s request %s, all recorded interactions were replayed", method, req)
+		}
+
+		interaction := g.fixture.Interactions[g.next]
+		if !matches(interaction) {
+			return nil, fmt.Errorf(
+				"unexpected %s request %s, expected %s request %s",
+				method, req, interaction.Method, interaction.Request,
+			)
+		}
+
+		g.used[g.next] = true
+		g.next++
+		return &interaction, nil
+	}
+
+	last := -1
+	for i, interaction := range g.fixture.Interactions {
+		if !matches(interaction) {
+			continue
+		}
+		if !g.used[i] {
+			g.used[i] = true
+			return &interaction, nil
+		}
+		last = i
+	}
+
+	if last < 0 {
+		return nil, fmt.Errorf("no recorded %s interaction matches the request %s", method, req)
+	}
+
+	return &g.fixture.Interactions[last], nil
+}
+
+// replay returns the recorded response matching the request.
+func replay[T any](g *ReplayGateway, method string, req request, codec responseCodec[T]) (T, error) {
+	var empty T
+
+	encoded, err := encodeRequest(req)
+	if err != nil {
+		return empty, fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	interaction, err := g.match(method, encoded)
+	if err != nil {
+		return empty, err
+	}
+
+	if interaction.Error != nil {
+		return empty, interaction.Error.err()
+	}
+
+	res, err := codec.decode(interaction.Response)
+	if err != nil {
+		return empty, fmt.Errorf("failed to decode recorded %s response: %w", method, err)
+	}
+
+	return res, nil
+}
+
+// recordedID returns the recorded ID of the replayed transaction.
+func (g *ReplayGateway) recordedID(ID flow.Identifier) flow.Identifier {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if recorded, ok := g.txIDs[ID]; ok {
+		return recorded
+	}
+	return ID
+}
+
+func (g *ReplayGateway) GetAccount(_ context.Context, address flow.Address) (*flow.Account, error) {
+	return replay(g, "GetAccount", request{"address": address}, accountCodec)
+}
+
+func (g *ReplayGateway) GetAccountAtBlockHeight(_ context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	return replay(g, "GetAccountAtBlockHeight", request{"address": address, "height": height}, accountCodec)
+}
+
+func (g *ReplayGateway) SendSignedTransaction(_ context.Context, tx *flow.Transaction) (*flow.Transaction, error) {
+	recorded, err := replay(g, "SendSignedTransaction", transactionRequest(tx), transactionCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	g.txIDs[tx.ID()] = recorded.ID()
+	g.mu.Unlock()
+
+	return tx, nil
+}
+
+func (g *ReplayGateway) GetTransaction(_ context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	return replay(g, "GetTransaction", request{"id": g.recordedID(ID).String()}, transactionCodec)
+}
+
+func (g *ReplayGateway) GetTransactionResultsByBlockID(_ context.Context, blockID flow.Identifier) ([]*flow.TransactionResult, error) {
+	return replay(g, "GetTransactionResultsByBlockID", request{"blockId": blockID.String()}, resultsCodec)
+}
+
+func (g *ReplayGateway) GetTransactionResult(_ context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	req := request{"id": g.recordedID(ID).String(), "waitSeal": waitSeal}
+	result, err := replay(g, "GetTransactionResult", req, resultCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	if result != nil {
+		result.TransactionID = ID
+	}
+	return result, nil
+}
+
+func (g *ReplayGateway) GetTransactionsByBlockID(_ context.Context, blockID flow.Identifier) ([]*flow.Transaction, error) {
+	return replay(g, "GetTransactionsByBlockID", request{"blockId": blockID.String()}, transactionsCodec)
+}
+
+func (g *ReplayGateway) GetSystemTransaction(_ context.Context, blockID flow.Identifier) (*flow.Transaction, error) {
+	return replay(g, "GetSystemTransaction", request{"blockId": blockID.String()}, transactionCodec)
+}
+
+func (g *ReplayGateway) GetSystemTransactionResult(_ context.Context, blockID flow.Identifier) (*flow.TransactionResult, error) {
+	return replay(g, "GetSystemTransactionResult", request{"blockId": blockID.String()}, resultCodec)
+}
+
+func (g *ReplayGateway) GetSystemTransactionWithID(_ context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.Transaction, error) {
+	req := request{"blockId": blockID.String(), "id": systemTxID.String()}
+	return replay(g, "GetSystemTransactionWithID", req, transactionCodec)
+}
+
+func (g *ReplayGateway) GetSystemTransactionResultWithID(_ context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.TransactionResult, error) {
+	req := request{"blockId": blockID.String(), "id": systemTxID.String()}
+	return replay(g, "GetSystemTransactionResultWithID", req, resultCodec)
+}
+
+func (g *ReplayGateway) ExecuteScript(_ context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	req, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+	return replay(g, "ExecuteScript", req, valueCodec)
+}
+
+func (g *ReplayGateway) ExecuteScriptAtHeight(_ context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	req, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+	req["height"] = height
+	return replay(g, "ExecuteScriptAtHeight", req, valueCodec)
+}
+
+func (g *ReplayGateway) ExecuteScriptAtID(_ context.Context, script []byte, arguments []cadence.Value, ID flow.Identifier) (cadence.Value, error) {
+	req, err := scriptRequest(script, arguments)
+	if err != nil {
+		return nil, err
+	}
+	req["blockId"] = ID.String()
+	return replay(g, "ExecuteScriptAtID", req, valueCodec)
+}
+
+func (g *ReplayGateway) GetLatestBlock(_ context.Context) (*flow.Block, error) {
+	return replay(g, "GetLatestBlock", nil, blockCodec)
+}
+
+func (g *ReplayGateway) GetBlockByHeight(_ context.Context, height uint64) (*flow.Block, error) {
+	return replay(g, "GetBlockByHeight", request{"height": height}, blockCodec)
+}
+
+func (g *ReplayGateway) GetBlockByID(_ context.Context, ID flow.Identifier) (*flow.Block, error) {
+	return replay(g, "GetBlockByID", request{"id": ID.String()}, blockCodec)
+}
+
+func (g *ReplayGateway) GetEvents(_ context.Context, eventType string, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	req := request{"type": eventType, "startHeight": startHeight, "endHeight": endHeight}
+	return replay(g, "GetEvents", req, eventsCodec)
+}
+
+func (g *ReplayGateway) GetCollection(_ context.Context, ID flow.Identifier) (*flow.Collection, error) {
+	return replay(g, "GetCollection", request{"id": ID.String()}, collectionCodec)
+}
+
+func (g *ReplayGateway) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, error) {
+	return replay(g, "GetLatestProtocolStateSnapshot", nil, bytesCodec)
+}
+
+func (g *ReplayGateway) GetNodeVersionInfo(_ context.Context) (*flow.NodeVersionInfo, error) {
+	return replay(g, "GetNodeVersionInfo", nil, versionCodec)
+}
+
+func (g *ReplayGateway) GetExecutionResultForBlockID(_ context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	return replay(g, "GetExecutionResultForBlockID", request{"blockId": blockID.String()}, executionResultCodec)
+}
+
+func (g *ReplayGateway) GetExecutionDataByBlockID(_ context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	return replay(g, "GetExecutionDataByBlockID", request{"blockId": blockID.String()}, executionDataCodec)
+}
+
+func (g *ReplayGateway) Ping() error {
+	_, err := replay(g, "Ping", nil, emptyCodec)
+	return err
+}
+
+// SubscribeBlocks replays the recorded polling requests. The recorded time between the polls isn't kept,
+// so the subscription ends with an error once the recorded requests are used up in strict mode.
+func (g *ReplayGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return polling(g, replayPollInterval).blocks(ctx, startHeight)
+}
+
+func (g *ReplayGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return polling(g, replayPollInterval).blockHeaders(ctx, startHeight)
+}
+
+func (g *ReplayGateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	return polling(g, replayPollInterval).events(ctx, startHeight, filter)
+}
+
+func (g *ReplayGateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return polling(g, replayPollInterval).accountStatuses(ctx, startHeight, filter)
+}
+
+func (g *ReplayGateway) WaitServer(_ context.Context) error {
+	return nil
+}
+
+func (g *ReplayGateway) SecureConnection() bool {
+	return g.fixture.SecureConnection
+}
@@ -0,0 +1,200 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+	"github.com/onflow/flowkit/v2/tests"
+)
+
+func TestRecordReplayGateway(t *testing.T) {
+	ctx := context.Background()
+	script := []byte("access(all) fun main(a: Int): Int { return a }")
+	args := []cadence.Value{cadence.NewInt(1)}
+	account := tests.NewAccountWithAddress("01")
+	block := tests.NewBlock()
+	result := tests.NewAccountCreateResult(flow.HexToAddress("02"))
+	result.Status = flow.TransactionStatusSealed
+	notFound := status.Error(codes.NotFound, "account not found")
+
+	tx := flow.NewTransaction().
+		SetScript([]byte("transaction {}")).
+		SetPayer(flow.HexToAddress("01")).
+		SetProposalKey(flow.HexToAddress("01"), 0, 1)
+	tx.EnvelopeSignatures = []flow.TransactionSignature{{Address: flow.HexToAddress("01"), Signature: []byte{1}}}
+
+	// record a session and save it to the fixture file
+	fixturePath := filepath.Join(t.TempDir(), "session.json")
+	{
+		g := mocks.NewGateway(t)
+		g.On("SecureConnection").Return(true).Once()
+		g.On("GetAccount", ctx, account.Address).Return(account, nil).Once()
+		g.On("GetAccount", ctx, flow.HexToAddress("03")).Return(nil, notFound).Once()
+		g.On("GetLatestBlock", ctx).Return(block, nil).Once()
+		g.On("ExecuteScript", ctx, script, args).Return(cadence.NewInt(1), nil).Once()
+		g.On("SendSignedTransaction", ctx, tx).Return(tx, nil).Once()
+		g.On("GetTransactionResult", ctx, tx.ID(), true).Return(result, nil).Once()
+
+		recorder := NewRecordingGateway(g)
+		_, err := recorder.GetAccount(ctx, account.Address)
+		require.NoError(t, err)
+		_, err = recorder.GetAccount(ctx, flow.HexToAddress("03"))
+		require.ErrorIs(t, err, notFound)
+		_, err = recorder.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		_, err = recorder.ExecuteScript(ctx, script, args)
+		require.NoError(t, err)
+		_, err = recorder.SendSignedTransaction(ctx, tx)
+		require.NoError(t, err)
+		_, err = recorder.GetTransactionResult(ctx, tx.ID(), true)
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.Save(fixturePath))
+	}
+
+	fixture, err := LoadFixture(fixturePath)
+	require.NoError(t, err)
+	require.Len(t, fixture.Interactions, 6)
+
+	t.Run("Replay strict", func(t *testing.T) {
+		g := NewReplayGateway(fixture, ReplayStrict)
+		assert.True(t, g.SecureConnection())
+
+		resAccount, err := g.GetAccount(ctx, account.Address)
+		require.NoError(t, err)
+		assert.Equal(t, account.Address, resAccount.Address)
+		assert.Equal(t, account.Keys[0].PublicKey, resAccount.Keys[0].PublicKey)
+
+		_, err = g.GetAccount(ctx, flow.HexToAddress("03"))
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.EqualError(t, err, notFound.Error())
+
+		resBlock, err := g.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, block.ID, resBlock.ID)
+
+		value, err := g.ExecuteScript(ctx, script, args)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(1), value)
+
+		// the transaction is signed again with a different signature
+		resigned := *tx
+		resigned.EnvelopeSignatures = []flow.TransactionSignature{{Address: flow.HexToAddress("01"), Signature: []byte{2}}}
+		require.NotEqual(t, tx.ID(), resigned.ID())
+
+		sent, err := g.SendSignedTransaction(ctx, &resigned)
+		require.NoError(t, err)
+		assert.Equal(t, resigned.ID(), sent.ID())
+
+		resResult, err := g.GetTransactionResult(ctx, resigned.ID(), true)
+		require.NoError(t, err)
+		assert.Equal(t, resigned.ID(), resResult.TransactionID)
+		assert.Equal(t, flow.TransactionStatusSealed, resResult.Status)
+		assert.Equal(t, result.Events[0].Value.String(), resResult.Events[0].Value.String())
+
+		assert.Empty(t, g.Unused())
+
+		_, err = g.GetLatestBlock(ctx)
+		assert.ErrorContains(t, err, "all recorded interactions were replayed")
+	})
+
+	t.Run("Strict fails on unexpected order", func(t *testing.T) {
+		g := NewReplayGateway(fixture, ReplayStrict)
+
+		_, err := g.GetLatestBlock(ctx)
+		assert.ErrorContains(t, err, "unexpected GetLatestBlock request")
+		assert.Len(t, g.Unused(), 6)
+	})
+
+	t.Run("Replay lenient", func(t *testing.T) {
+		g := NewReplayGateway(fixture, ReplayLenient)
+
+		for i := 0; i < 2; i++ {
+			value, err := g.ExecuteScript(ctx, script, args)
+			require.NoError(t, err)
+			assert.Equal(t, cadence.NewInt(1), value)
+
+			_, err = g.GetLatestBlock(ctx)
+			require.NoError(t, err)
+		}
+
+		_, err := g.ExecuteScript(ctx, script, []cadence.Value{cadence.NewInt(2)})
+		assert.ErrorContains(t, err, "no recorded ExecuteScript interaction matches the request")
+		assert.Len(t, g.Unused(), 4)
+	})
+}
+
+func TestRecordReplayExecutionData(t *testing.T) {
+	ctx := context.Background()
+	blockID := flow.HexToID("01")
+	event := tests.NewEvent(0, "A.01.Token.Deposited", nil, nil)
+	data := &flow.ExecutionData{
+		BlockID: blockID,
+		ChunkExecutionData: []*flow.ChunkExecutionData{{
+			Transactions:       []*flow.Transaction{tests.NewTransaction()},
+			Events:             []*flow.Event{event},
+			TransactionResults: []*flow.LightTransactionResult{{TransactionID: flow.HexToID("02"), ComputationUsed: 10}},
+		}},
+	}
+	result := &flow.ExecutionResult{
+		BlockID: blockID,
+		Chunks:  []*flow.Chunk{{BlockID: blockID, NumberOfTransactions: 1}},
+	}
+
+	g := mocks.NewGateway(t)
+	g.On("SecureConnection").Return(true).Once()
+	g.On("GetExecutionResultForBlockID", ctx, blockID).Return(result, nil).Once()
+	g.On("GetExecutionDataByBlockID", ctx, blockID).Return(data, nil).Once()
+
+	recorder := NewRecordingGateway(g)
+	_, err := recorder.GetExecutionResultForBlockID(ctx, blockID)
+	require.NoError(t, err)
+	_, err = recorder.GetExecutionDataByBlockID(ctx, blockID)
+	require.NoError(t, err)
+
+	fixturePath := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, recorder.Save(fixturePath))
+	fixture, err := LoadFixture(fixturePath)
+	require.NoError(t, err)
+
+	replay := NewReplayGateway(fixture, ReplayStrict)
+	resResult, err := replay.GetExecutionResultForBlockID(ctx, blockID)
+	require.NoError(t, err)
+	assert.Equal(t, result, resResult)
+
+	resData, err := replay.GetExecutionDataByBlockID(ctx, blockID)
+	require.NoError(t, err)
+	require.Len(t, resData.ChunkExecutionData, 1)
+	chunk := resData.ChunkExecutionData[0]
+	assert.Equal(t, data.ChunkExecutionData[0].Transactions[0].ID(), chunk.Transactions[0].ID())
+	assert.Equal(t, data.ChunkExecutionData[0].TransactionResults, chunk.TransactionResults)
+	assert.Equal(t, event.Type, chunk.Events[0].Type)
+	assert.NotNil(t, chunk.Events[0].Value)
+}
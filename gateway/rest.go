@@ -0,0 +1,536 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	httpAccess "github.com/onflow/flow-go-sdk/access/http"
+	"github.com/onflow/flow-go-sdk/access/http/convert"
+	"github.com/onflow/flow-go-sdk/access/http/models"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+var _ Gateway = &RestGateway{}
+
+// sealedHeight is the special height of the REST API referring to the latest sealed block.
+const sealedHeight = "sealed"
+
+// RestGateway is a gateway implementation that uses the Flow Access HTTP/REST API.
+//
+// The network host must be the full base URL of the REST API including the version
+// path, for example "https://rest-testnet.onflow.org/v1". The network headers are sent
+// with every request, and each request is limited to the network timeout if one is defined.
+type RestGateway struct {
+	httpClient   *http.Client
+	host         string
+	headers      map[string]string
+	jsonOptions  []jsoncdc.Option
+	secureClient bool
+}
+
+// NewRestGateway returns a new REST gateway.
+func NewRestGateway(network config.Network) (*RestGateway, error) {
+	host := strings.TrimSuffix(network.Host, "/")
+
+	u, err := url.Parse(host)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid REST host %s, a full URL such as https://rest-testnet.onflow.org/v1 is required", network.Host)
+	}
+
+	httpClient := http.DefaultClient
+	if network.Timeout > 0 {
+		httpClient = &http.Client{Timeout: network.Timeout}
+	}
+
+	return &RestGateway{
+		httpClient: httpClient,
+		host:       host,
+		headers:    network.Headers,
+		jsonOptions: []jsoncdc.Option{
+			jsoncdc.WithAllowUnstructuredStaticTypes(true),
+		},
+		secureClient: u.Scheme == "https",
+	}, nil
+}
+
+// GetAccount gets an account by address from the Flow Access API.
+func (g *RestGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	account, err := g.getAccount(ctx, address, sealedHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account with address %s: %w", address, err)
+	}
+
+	return account, nil
+}
+
+// GetAccountAtBlockHeight gets an account by address at a specific block height from the Flow Access API.
+func (g *RestGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, blockHeight uint64) (*flow.Account, error) {
+	account, err := g.getAccount(ctx, address, strconv.FormatUint(blockHeight, 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account with address %s at block height %d: %w", address, blockHeight, err)
+	}
+
+	return account, nil
+}
+
+func (g *RestGateway) getAccount(ctx context.Context, address flow.Address, height string) (*flow.Account, error) {
+	var account models.Account
+	err := g.get(ctx, fmt.Sprintf("/accounts/%s", address), url.Values{
+		"height": {height},
+		"expand": {"keys,contracts"},
+	}, &account)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.ToAccount(&account)
+}
+
+// SendSignedTransaction sends a transaction to flow that is already prepared and signed.
+func (g *RestGateway) SendSignedTransaction(ctx context.Context, tx *flow.Transaction) (*flow.Transaction, error) {
+	body, err := convert.TncodeTransaction(*tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	var sent models.Transaction
+	if err := g.post(ctx, "/transactions", nil, body, &sent); err != nil {
+		return nil, fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// GetTransaction gets a transaction by ID from the Flow Access API.
+func (g *RestGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	var tx models.Transaction
+	if err := g.get(ctx, fmt.Sprintf("/transactions/%s", ID), nil, &tx); err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", ID, err)
+	}
+
+	return convert.ToTransaction(&tx)
+}
+
+// GetTransactionResultsByBlockID gets all the transaction results in the block, including system transactions.
+func (g *RestGateway) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionResult, error) {
+	var results []models.TransactionResult
+	err := g.get(ctx, "/transaction_results", url.Values{"block_id": {blockID.String()}}, &results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction results for block %s: %w", blockID, err)
+	}
+
+	txResults := make([]*flow.TransactionResult, len(results))
+	for i := range results {
+		txResults[i], err = convert.ToTransactionResult(&results[i], g.jsonOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return txResults, nil
+}
+
+// GetTransactionsByBlockID gets all the transactions in the block, including system transactions.
+func (g *RestGateway) GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.Transaction, error) {
+	var txs []models.Transaction
+	err := g.get(ctx, "/transactions", url.Values{"block_id": {blockID.String()}}, &txs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions for block %s: %w", blockID, err)
+	}
+
+	transactions := make([]*flow.Transaction, len(txs))
+	for i := range txs {
+		transactions[i], err = convert.ToTransaction(&txs[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionResult gets a transaction result by ID from the Flow Access API.
+func (g *RestGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	var tx models.Transaction
+	if err := g.get(ctx, fmt.Sprintf("/transactions/%s", ID), url.Values{"expand": {"result"}}, &tx); err != nil {
+		return nil, fmt.Errorf("failed to get transaction result %s: %w", ID, err)
+	}
+	if tx.Result == nil {
+		return nil, fmt.Errorf("no result found for transaction %s", ID)
+	}
+
+	result, err := convert.ToTransactionResult(tx.Result, g.jsonOptions)
+	if err != nil {
+		return nil, err
+	}
+	result.TransactionID = ID
+
+	if result.Status != flow.TransactionStatusSealed && waitSeal {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+		return g.GetTransactionResult(ctx, ID, waitSeal)
+	}
+
+	return result, nil
+}
+
+// GetSystemTransaction gets the system chunk transaction for the block, which is always the last transaction of the block.
+func (g *RestGateway) GetSystemTransaction(ctx context.Context, blockID flow.Identifier) (*flow.Transaction, error) {
+	txs, err := g.GetTransactionsByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("no system transaction found for block %s", blockID)
+	}
+
+	return txs[len(txs)-1], nil
+}
+
+// GetSystemTransactionResult gets the result of the system chunk transaction for the block.
+func (g *RestGateway) GetSystemTransactionResult(ctx context.Context, blockID flow.Identifier) (*flow.TransactionResult, error) {
+	results, err := g.GetTransactionResultsByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no system transaction result found for block %s", blockID)
+	}
+
+	return results[len(results)-1], nil
+}
+
+// GetSystemTransactionWithID gets the system transaction by ID in the block, if ID is empty the system chunk transaction is returned.
+func (g *RestGateway) GetSystemTransactionWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.Transaction, error) {
+	if systemTxID == flow.EmptyID {
+		return g.GetSystemTransaction(ctx, blockID)
+	}
+
+	var tx models.Transaction
+	err := g.get(ctx, fmt.Sprintf("/transactions/%s", systemTxID), url.Values{"block_id": {blockID.String()}}, &tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system transaction %s for block %s: %w", systemTxID, blockID, err)
+	}
+
+	return convert.ToTransaction(&tx)
+}
+
+// GetSystemTransactionResultWithID gets the system transaction result by ID in the block, if ID is empty the system chunk transaction result is returned.
+func (g *RestGateway) GetSystemTransactionResultWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.TransactionResult, error) {
+	if systemTxID == flow.EmptyID {
+		return g.GetSystemTransactionResult(ctx, blockID)
+	}
+
+	var result models.TransactionResult
+	err := g.get(ctx, fmt.Sprintf("/transaction_results/%s", systemTxID), url.Values{"block_id": {blockID.String()}}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system transaction result %s for block %s: %w", systemTxID, blockID, err)
+	}
+
+	txResult, err := convert.ToTransactionResult(&result, g.jsonOptions)
+	if err != nil {
+		return nil, err
+	}
+	txResult.TransactionID = systemTxID
+
+	return txResult, nil
+}
+
+// ExecuteScript executes a script on Flow through the Access API.
+func (g *RestGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	return g.executeScript(ctx, url.Values{"block_height": {sealedHeight}}, script, arguments)
+}
+
+// ExecuteScriptAtHeight executes a script at block height.
+func (g *RestGateway) ExecuteScriptAtHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	return g.executeScript(ctx, url.Values{"block_height": {strconv.FormatUint(height, 10)}}, script, arguments)
+}
+
+// ExecuteScriptAtID executes a script at block ID.
+func (g *RestGateway) ExecuteScriptAtID(ctx context.Context, script []byte, arguments []cadence.Value, ID flow.Identifier) (cadence.Value, error) {
+	return g.executeScript(ctx, url.Values{"block_id": {ID.String()}}, script, arguments)
+}
+
+func (g *RestGateway) executeScript(ctx context.Context, query url.Values, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	args, err := convert.EncodeCadenceArgs(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(models.ScriptsBody{Script: convert.EncodeScript(script), Arguments: args})
+	if err != nil {
+		return nil, err
+	}
+
+	var result string
+	if err := g.post(ctx, "/scripts", query, body, &result); err != nil {
+		return nil, fmt.Errorf("failed to execute script: %w", err)
+	}
+
+	return convert.DecodeCadenceValue(result, g.jsonOptions)
+}
+
+// GetLatestBlock gets the latest sealed block on Flow through the Access API with full block payload.
+func (g *RestGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	block, err := g.getBlock(ctx, "/blocks", url.Values{"height": {sealedHeight}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	return block, nil
+}
+
+// GetBlockByID gets a block by ID from the Flow Access API with full block payload.
+func (g *RestGateway) GetBlockByID(ctx context.Context, id flow.Identifier) (*flow.Block, error) {
+	block, err := g.getBlock(ctx, fmt.Sprintf("/blocks/%s", id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block by ID: %w", err)
+	}
+
+	return block, nil
+}
+
+// GetBlockByHeight gets a block by height from the Flow Access API with full block payload.
+func (g *RestGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	block, err := g.getBlock(ctx, "/blocks", url.Values{"height": {strconv.FormatUint(height, 10)}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block by height: %w", err)
+	}
+
+	return block, nil
+}
+
+// getBlock gets the first block with its payload from the blocks endpoint, which always responds with a list of blocks.
+func (g *RestGateway) getBlock(ctx context.Context, path string, query url.Values) (*flow.Block, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("expand", "payload")
+
+	var blocks []*models.Block
+	if err := g.get(ctx, path, query, &blocks); err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no block found")
+	}
+
+	return convert.ToBlock(blocks[0])
+}
+
+// GetEvents gets events by name and block range from the Flow Access API.
+func (g *RestGateway) GetEvents(
+	ctx context.Context,
+	eventType string,
+	startHeight uint64,
+	endHeight uint64,
+) ([]flow.BlockEvents, error) {
+	if startHeight > endHeight {
+		return nil, fmt.Errorf("start height %d must not be greater than end height %d", startHeight, endHeight)
+	}
+
+	var events []models.BlockEvents
+	err := g.get(ctx, "/events", url.Values{
+		"type":         {eventType},
+		"start_height": {strconv.FormatUint(startHeight, 10)},
+		"end_height":   {strconv.FormatUint(endHeight, 10)},
+	}, &events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events of type %s: %w", eventType, err)
+	}
+
+	return convert.ToBlockEvents(events, g.jsonOptions)
+}
+
+// GetCollection gets a collection by ID from the Flow Access API.
+func (g *RestGateway) GetCollection(ctx context.Context, id flow.Identifier) (*flow.Collection, error) {
+	var collection models.Collection
+	if err := g.get(ctx, fmt.Sprintf("/collections/%s", id), nil, &collection); err != nil {
+		return nil, fmt.Errorf("failed to get collection %s: %w", id, err)
+	}
+
+	return convert.ToCollection(&collection), nil
+}
+
+// GetLatestProtocolStateSnapshot returns an error, the REST API has no endpoint for the protocol state snapshot
+// so it can only be fetched with the gRPC gateway.
+func (g *RestGateway) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, error) {
+	return nil, fmt.Errorf("protocol state snapshot is not supported by the REST API, use a gRPC host instead")
+}
+
+// GetExecutionResultForBlockID gets the execution result of the block from the Flow Access API.
+func (g *RestGateway) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	var results []models.ExecutionResult
+	if err := g.get(ctx, "/execution_results", url.Values{"block_id": {blockID.String()}}, &results); err != nil {
+		return nil, fmt.Errorf("failed to get execution result for block %s: %w", blockID, err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no execution result found for block %s", blockID)
+	}
+
+	return convert.ToExecutionResults(results[0]), nil
+}
+
+// GetExecutionDataByBlockID returns an error, the REST API has no endpoint for the execution data
+// so it can only be fetched with the gRPC gateway.
+func (g *RestGateway) GetExecutionDataByBlockID(_ context.Context, _ flow.Identifier) (*flow.ExecutionData, error) {
+	return nil, fmt.Errorf("execution data is not supported by the REST API, use a gRPC host instead")
+}
+
+// GetNodeVersionInfo returns version information for the access node.
+func (g *RestGateway) GetNodeVersionInfo(ctx context.Context) (*flow.NodeVersionInfo, error) {
+	var info models.NodeVersionInfo
+	if err := g.get(ctx, "/node_version_info", nil, &info); err != nil {
+		return nil, fmt.Errorf("failed to get node version info: %w", err)
+	}
+
+	return convert.ToNodeVersionInfo(&info)
+}
+
+// Ping is used to check if the access node is alive and healthy.
+func (g *RestGateway) Ping() error {
+	return g.ping(context.Background())
+}
+
+func (g *RestGateway) ping(ctx context.Context) error {
+	if _, err := g.getBlock(ctx, "/blocks", url.Values{"height": {sealedHeight}}); err != nil {
+		return fmt.Errorf("ping error: %w", err)
+	}
+
+	return nil
+}
+
+// WaitServer blocks until the access node responds or the context is done.
+func (g *RestGateway) WaitServer(ctx context.Context) error {
+	for {
+		if err := g.ping(ctx); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// SubscribeBlocks polls the REST API for new sealed blocks.
+func (g *RestGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return polling(g, DefaultPollInterval).blocks(ctx, startHeight)
+}
+
+// SubscribeBlockHeaders polls the REST API for new sealed blocks.
+func (g *RestGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return polling(g, DefaultPollInterval).blockHeaders(ctx, startHeight)
+}
+
+// SubscribeEvents polls the REST API for the events matching the filter in new sealed blocks.
+func (g *RestGateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	return polling(g, DefaultPollInterval).events(ctx, startHeight, filter)
+}
+
+// SubscribeAccountStatuses polls the REST API for the core account events in new sealed blocks.
+func (g *RestGateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return polling(g, DefaultPollInterval).accountStatuses(ctx, startHeight, filter)
+}
+
+// SecureConnection is used to log warning if a service should be using a secure client but is not
+func (g *RestGateway) SecureConnection() bool {
+	return g.secureClient
+}
+
+// get requests the path relative to the REST host and decodes the JSON response into the model.
+func (g *RestGateway) get(ctx context.Context, path string, query url.Values, model any) error {
+	return g.request(ctx, http.MethodGet, path, query, nil, model)
+}
+
+// post sends the JSON body to the path relative to the REST host and decodes the JSON response into the model.
+func (g *RestGateway) post(ctx context.Context, path string, query url.Values, body []byte, model any) error {
+	return g.request(ctx, http.MethodPost, path, query, body, model)
+}
+
+// request sends the request with the network headers and decodes the JSON response into the model.
+//
+// The requests are sent with the gateway HTTP client instead of the SDK HTTP client, since the SDK client
+// ignores the request context, so calls couldn't be cancelled.
+func (g *RestGateway) request(ctx context.Context, method string, path string, query url.Values, body []byte, model any) error {
+	u := fmt.Sprintf("%s%s", g.host, path)
+	if len(query) > 0 {
+		u = fmt.Sprintf("%s?%s", u, query.Encode())
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range g.headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		httpErr := httpAccess.HTTPError{Url: u, Code: res.StatusCode}
+		if err := json.Unmarshal(resBody, &httpErr); err != nil || httpErr.Message == "" {
+			httpErr.Message = fmt.Sprintf("request to %s failed with status %d", u, res.StatusCode)
+		}
+		return httpErr
+	}
+
+	if err := json.Unmarshal(resBody, model); err != nil {
+		return fmt.Errorf("JSON decoding failed: %w", err)
+	}
+
+	return nil
+}
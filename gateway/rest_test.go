@@ -0,0 +1,453 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/access/http/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+var (
+	restBlockID = flow.HexToID("7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7")
+	restTxID    = flow.HexToID("2b4b1d45e4a1b7b1d5c2e1f0a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6")
+	restSysTxID = flow.HexToID("9a0b1c2d3e4f5061728394a5b6c7d8e9fa0b1c2d3e4f5061728394a5b6c7d8e9")
+	restAddress = flow.HexToAddress("f8d6e0586b0a20c7")
+)
+
+// restStub is a stand-in for an access node REST API serving fixed responses per path.
+type restStub struct {
+	server    *httptest.Server
+	responses map[string]any
+	posted    []byte
+}
+
+func newRestStub(t *testing.T) *restStub {
+	stub := &restStub{responses: make(map[string]any)}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			stub.posted, _ = io.ReadAll(r.Body)
+		}
+
+		res, ok := stub.responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 404, "message": "not found"})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+func (s *restStub) gateway(t *testing.T) *RestGateway {
+	gw, err := NewRestGateway(config.Network{Name: "rest", Host: s.server.URL + "/v1"})
+	require.NoError(t, err)
+	return gw
+}
+
+func restStatus(s models.TransactionStatus) *models.TransactionStatus {
+	return &s
+}
+
+func restBlock(height string) []models.Block {
+	return []models.Block{{
+		Header: &models.BlockHeader{
+			Id:        restBlockID.String(),
+			ParentId:  flow.EmptyID.String(),
+			Height:    height,
+			Timestamp: time.Unix(1700000000, 0).UTC(),
+		},
+		Payload: &models.BlockPayload{
+			CollectionGuarantees: []models.CollectionGuarantee{{
+				CollectionId: restTxID.String(),
+			}},
+			BlockSeals: []models.BlockSeal{},
+		},
+		BlockStatus: "BLOCK_SEALED",
+	}}
+}
+
+func restTransaction(id flow.Identifier, script string) models.Transaction {
+	return models.Transaction{
+		Id:               id.String(),
+		Script:           base64.StdEncoding.EncodeToString([]byte(script)),
+		Arguments:        []string{},
+		ReferenceBlockId: restBlockID.String(),
+		GasLimit:         "9999",
+		Payer:            restAddress.String(),
+		ProposalKey: &models.ProposalKey{
+			Address:        restAddress.String(),
+			KeyIndex:       "0",
+			SequenceNumber: "3",
+		},
+		Authorizers: []string{restAddress.String()},
+	}
+}
+
+func restResult(status models.TransactionStatus, errMsg string) models.TransactionResult {
+	return models.TransactionResult{
+		BlockId:      restBlockID.String(),
+		Status:       restStatus(status),
+		ErrorMessage: errMsg,
+		Events:       []models.Event{},
+	}
+}
+
+func TestRestGateway(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Invalid Host", func(t *testing.T) {
+		_, err := NewRestGateway(config.Network{Host: "127.0.0.1:8888"})
+		assert.ErrorContains(t, err, "invalid REST host")
+	})
+
+	t.Run("Secure Connection", func(t *testing.T) {
+		gw, err := NewRestGateway(config.Network{Host: "https://rest-testnet.onflow.org/v1"})
+		require.NoError(t, err)
+		assert.True(t, gw.SecureConnection())
+
+		gw, err = NewRestGateway(config.Network{Host: "http://127.0.0.1:8888/v1"})
+		require.NoError(t, err)
+		assert.False(t, gw.SecureConnection())
+	})
+
+	t.Run("Get Account", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/accounts/"+restAddress.String()] = models.Account{
+			Address:   restAddress.String(),
+			Balance:   "100000",
+			Contracts: map[string]string{"Hello": base64.StdEncoding.EncodeToString([]byte("access(all) contract Hello {}"))},
+		}
+
+		account, err := stub.gateway(t).GetAccount(ctx, restAddress)
+		require.NoError(t, err)
+		assert.Equal(t, restAddress, account.Address)
+		assert.Equal(t, uint64(100000), account.Balance)
+		assert.Equal(t, "access(all) contract Hello {}", string(account.Contracts["Hello"]))
+	})
+
+	t.Run("Get Account Failure", func(t *testing.T) {
+		stub := newRestStub(t)
+
+		_, err := stub.gateway(t).GetAccount(ctx, restAddress)
+		assert.ErrorContains(t, err, "failed to get account with address f8d6e0586b0a20c7")
+	})
+
+	t.Run("Send Transaction", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/transactions"] = restTransaction(restTxID, "transaction {}")
+
+		tx := flow.NewTransaction().
+			SetScript([]byte("transaction {}")).
+			SetReferenceBlockID(restBlockID).
+			SetProposalKey(restAddress, 0, 3).
+			SetPayer(restAddress)
+
+		sent, err := stub.gateway(t).SendSignedTransaction(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, tx.ID(), sent.ID())
+
+		var body models.TransactionsBody
+		require.NoError(t, json.Unmarshal(stub.posted, &body))
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("transaction {}")), body.Script)
+		assert.Equal(t, restAddress.String(), body.Payer)
+	})
+
+	t.Run("Get Transaction Result", func(t *testing.T) {
+		stub := newRestStub(t)
+		tx := restTransaction(restTxID, "transaction {}")
+		result := restResult(models.SEALED_TransactionStatus, "")
+		tx.Result = &result
+		stub.responses["/v1/transactions/"+restTxID.String()] = tx
+
+		res, err := stub.gateway(t).GetTransactionResult(ctx, restTxID, true)
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, res.Status)
+		assert.Equal(t, restTxID, res.TransactionID)
+		assert.Equal(t, restBlockID, res.BlockID)
+		assert.NoError(t, res.Error)
+	})
+
+	t.Run("Wait Seal Respects Context", func(t *testing.T) {
+		stub := newRestStub(t)
+		tx := restTransaction(restTxID, "transaction {}")
+		result := restResult(models.PENDING_TransactionStatus, "")
+		tx.Result = &result
+		stub.responses["/v1/transactions/"+restTxID.String()] = tx
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		_, err := stub.gateway(t).GetTransactionResult(cancelCtx, restTxID, true)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Transactions By Block", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/transactions"] = []models.Transaction{
+			restTransaction(restTxID, "transaction {}"),
+			restTransaction(restSysTxID, "transaction { execute {} }"),
+		}
+		stub.responses["/v1/transaction_results"] = []models.TransactionResult{
+			restResult(models.SEALED_TransactionStatus, ""),
+			restResult(models.SEALED_TransactionStatus, "system failure"),
+		}
+		gw := stub.gateway(t)
+
+		txs, err := gw.GetTransactionsByBlockID(ctx, restBlockID)
+		require.NoError(t, err)
+		require.Len(t, txs, 2)
+		assert.Equal(t, "transaction {}", string(txs[0].Script))
+
+		results, err := gw.GetTransactionResultsByBlockID(ctx, restBlockID)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		sysTx, err := gw.GetSystemTransaction(ctx, restBlockID)
+		require.NoError(t, err)
+		assert.Equal(t, "transaction { execute {} }", string(sysTx.Script))
+
+		sysResult, err := gw.GetSystemTransactionResult(ctx, restBlockID)
+		require.NoError(t, err)
+		assert.EqualError(t, sysResult.Error, "system failure")
+
+		sysTx, err = gw.GetSystemTransactionWithID(ctx, restBlockID, flow.EmptyID)
+		require.NoError(t, err)
+		assert.Equal(t, "transaction { execute {} }", string(sysTx.Script))
+	})
+
+	t.Run("System Transaction With ID", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/transactions/"+restSysTxID.String()] = restTransaction(restSysTxID, "transaction { execute {} }")
+		stub.responses["/v1/transaction_results/"+restSysTxID.String()] = restResult(models.SEALED_TransactionStatus, "")
+		gw := stub.gateway(t)
+
+		tx, err := gw.GetSystemTransactionWithID(ctx, restBlockID, restSysTxID)
+		require.NoError(t, err)
+		assert.Equal(t, "transaction { execute {} }", string(tx.Script))
+
+		res, err := gw.GetSystemTransactionResultWithID(ctx, restBlockID, restSysTxID)
+		require.NoError(t, err)
+		assert.Equal(t, restSysTxID, res.TransactionID)
+		assert.Equal(t, flow.TransactionStatusSealed, res.Status)
+	})
+
+	t.Run("Get Blocks", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/blocks"] = restBlock("42")
+		stub.responses["/v1/blocks/"+restBlockID.String()] = restBlock("42")
+		gw := stub.gateway(t)
+
+		latest, err := gw.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), latest.Height)
+		assert.Equal(t, restBlockID, latest.ID)
+		require.Len(t, latest.CollectionGuarantees, 1)
+
+		byHeight, err := gw.GetBlockByHeight(ctx, 42)
+		require.NoError(t, err)
+		assert.Equal(t, restBlockID, byHeight.ID)
+
+		byID, err := gw.GetBlockByID(ctx, restBlockID)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), byID.Height)
+	})
+
+	t.Run("Subscribe Blocks", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/blocks"] = restBlock("42")
+		gw := stub.gateway(t)
+
+		ctx, cancel := context.WithCancel(ctx)
+		blocks, errs, err := gw.SubscribeBlocks(ctx, 0)
+		require.NoError(t, err)
+
+		res := receive(t, blocks, errs, 1)
+		assert.Equal(t, uint64(42), res[0].Height)
+		assert.Equal(t, restBlockID, res[0].ID)
+
+		drain(cancel, blocks, errs)
+	})
+
+	t.Run("Execute Script", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/scripts"] = base64.StdEncoding.EncodeToString(jsoncdc.MustEncode(cadence.NewInt(7)))
+		gw := stub.gateway(t)
+
+		val, err := gw.ExecuteScript(ctx, []byte("access(all) fun main(): Int { return 7 }"), nil)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(7), val)
+
+		val, err = gw.ExecuteScriptAtHeight(ctx, []byte("access(all) fun main(): Int { return 7 }"), nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(7), val)
+
+		val, err = gw.ExecuteScriptAtID(ctx, []byte("access(all) fun main(): Int { return 7 }"), nil, restBlockID)
+		require.NoError(t, err)
+		assert.Equal(t, cadence.NewInt(7), val)
+	})
+
+	t.Run("Get Events", func(t *testing.T) {
+		stub := newRestStub(t)
+		eventType := cadence.NewEventType(nil, "A.f8d6e0586b0a20c7.Hello.Greeted", []cadence.Field{{
+			Identifier: "greeting",
+			Type:       cadence.StringType,
+		}}, nil)
+		payload := jsoncdc.MustEncode(cadence.NewEvent([]cadence.Value{cadence.String("hi")}).WithType(eventType))
+		stub.responses["/v1/events"] = []models.BlockEvents{{
+			BlockId:     restBlockID.String(),
+			BlockHeight: "42",
+			Events: []models.Event{{
+				Type_:            "A.f8d6e0586b0a20c7.Hello.Greeted",
+				TransactionId:    restTxID.String(),
+				TransactionIndex: "0",
+				EventIndex:       "0",
+				Payload:          base64.StdEncoding.EncodeToString(payload),
+			}},
+		}}
+
+		events, err := stub.gateway(t).GetEvents(ctx, "A.f8d6e0586b0a20c7.Hello.Greeted", 40, 42)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, uint64(42), events[0].Height)
+		require.Len(t, events[0].Events, 1)
+		assert.Equal(t, restTxID, events[0].Events[0].TransactionID)
+	})
+
+	t.Run("Get Collection", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/collections/"+restTxID.String()] = models.Collection{
+			Id:           restTxID.String(),
+			Transactions: []models.Transaction{restTransaction(restTxID, "transaction {}")},
+		}
+
+		collection, err := stub.gateway(t).GetCollection(ctx, restTxID)
+		require.NoError(t, err)
+		assert.Equal(t, []flow.Identifier{restTxID}, collection.TransactionIDs)
+	})
+
+	t.Run("Node Version Info", func(t *testing.T) {
+		stub := newRestStub(t)
+		stub.responses["/v1/node_version_info"] = models.NodeVersionInfo{
+			Semver:               "v0.37.0",
+			Commit:               "abc",
+			SporkId:              restBlockID.String(),
+			ProtocolVersion:      "32",
+			SporkRootBlockHeight: "100",
+			NodeRootBlockHeight:  "120",
+		}
+
+		info, err := stub.gateway(t).GetNodeVersionInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "v0.37.0", info.Semver)
+		assert.Equal(t, uint64(32), info.ProtocolVersion)
+		assert.Equal(t, uint64(120), info.NodeRootBlockHeight)
+	})
+
+	t.Run("Ping And Wait Server", func(t *testing.T) {
+		stub := newRestStub(t)
+		gw := stub.gateway(t)
+		assert.Error(t, gw.Ping())
+
+		stub.responses["/v1/blocks"] = restBlock("1")
+		assert.NoError(t, gw.Ping())
+		assert.NoError(t, gw.WaitServer(ctx))
+	})
+
+	t.Run("Cancel Request With Context", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		gw, err := NewRestGateway(config.Network{Name: "rest", Host: server.URL + "/v1"})
+		require.NoError(t, err)
+
+		cancelCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		_, err = gw.GetLatestBlock(cancelCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Send Network Headers", func(t *testing.T) {
+		var received http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Clone()
+			_ = json.NewEncoder(w).Encode(restBlock("42"))
+		}))
+		defer server.Close()
+
+		gw, err := NewRestGateway(config.Network{
+			Name:    "rest",
+			Host:    server.URL + "/v1",
+			Headers: map[string]string{"x-api-key": "secret"},
+		})
+		require.NoError(t, err)
+
+		_, err = gw.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", received.Get("x-api-key"))
+	})
+
+	t.Run("Limit Requests To Network Timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		gw, err := NewRestGateway(config.Network{Name: "rest", Host: server.URL + "/v1", Timeout: 100 * time.Millisecond})
+		require.NoError(t, err)
+
+		_, err = gw.GetLatestBlock(ctx)
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		assert.True(t, netErr.Timeout())
+	})
+
+	t.Run("Protocol Snapshot Not Supported", func(t *testing.T) {
+		stub := newRestStub(t)
+
+		_, err := stub.gateway(t).GetLatestProtocolStateSnapshot(ctx)
+		assert.ErrorContains(t, err, "not supported")
+	})
+}
@@ -0,0 +1,409 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+	httpAccess "github.com/onflow/flow-go-sdk/access/http"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flowkit/v2/config"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 250 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+	defaultRetryMultiplier     = 2
+	defaultRetryJitter         = 0.2
+)
+
+// RetryPolicy defines how many times and how often a failed gateway call is retried.
+//
+// The delay before attempt n+1 is InitialBackoff * Multiplier^(n-1), capped by MaxBackoff,
+// and then randomized by +/- Jitter fraction of the delay.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	// Retryable classifies errors, if not set IsRetryableError is used.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy used for values not defined in the network configuration.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		Multiplier:     defaultRetryMultiplier,
+		Jitter:         defaultRetryJitter,
+		Retryable:      IsRetryableError,
+	}
+}
+
+// NewRetryPolicy builds a retry policy from the network retry configuration, unset values use defaults.
+func NewRetryPolicy(retry config.NetworkRetry) RetryPolicy {
+	policy := DefaultRetryPolicy()
+	if retry.MaxAttempts > 0 {
+		policy.MaxAttempts = retry.MaxAttempts
+	}
+	if retry.InitialBackoff > 0 {
+		policy.InitialBackoff = retry.InitialBackoff
+	}
+	if retry.MaxBackoff > 0 {
+		policy.MaxBackoff = retry.MaxBackoff
+	}
+	if retry.Multiplier >= 1 {
+		policy.Multiplier = retry.Multiplier
+	}
+	if retry.Jitter > 0 {
+		policy.Jitter = retry.Jitter
+	}
+
+	return policy
+}
+
+// backoff returns the delay to wait after the provided failed attempt, attempts start at 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryableError(err)
+}
+
+// IsRetryableError returns true if the error is transient and the failed call can be repeated.
+//
+// Unavailable, deadline exceeded, resource exhausted and aborted gRPC errors, REST gateway
+// errors and timeouts, as well as network connection errors are considered retryable,
+// all other errors are terminal.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+
+	var httpErr httpAccess.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.Code {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isUndeliveredError returns true if the error guarantees the request never reached the node,
+// which is the only case where resending a transaction is safe.
+func isUndeliveredError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	// gRPC reports failed connection attempts as unavailable with a transport dialing message
+	return status.Code(err) == codes.Unavailable &&
+		strings.Contains(err.Error(), "Error while dialing")
+}
+
+var _ Gateway = &RetryGateway{}
+
+// RetryGateway is a gateway decorator retrying failed calls of the wrapped gateway
+// using exponential backoff defined by the retry policy.
+//
+// Transactions are only resubmitted if the failure guarantees the transaction never reached the node.
+// If a submission fails with any other retryable error the gateway looks the transaction up
+// instead, and reports the submission as successful if the node knows about it.
+type RetryGateway struct {
+	gateway Gateway
+	policy  RetryPolicy
+}
+
+// NewRetryGateway returns a gateway that retries the calls to the provided gateway.
+func NewRetryGateway(gateway Gateway, policy RetryPolicy) *RetryGateway {
+	return &RetryGateway{
+		gateway: gateway,
+		policy:  policy,
+	}
+}
+
+// Unwrap returns the wrapped gateway.
+func (g *RetryGateway) Unwrap() Gateway {
+	return g.gateway
+}
+
+// NewNetworkRetryGateway wraps the gateway with the retry policy defined by the network configuration.
+//
+// If the network doesn't define a retry policy the gateway is returned unchanged.
+func NewNetworkRetryGateway(gateway Gateway, network config.Network) Gateway {
+	if network.Retry.IsEmpty() {
+		return gateway
+	}
+	return NewRetryGateway(gateway, NewRetryPolicy(network.Retry))
+}
+
+// withRetry calls the function until it succeeds, fails with a terminal error or the attempts are exhausted.
+func withRetry[T any](ctx context.Context, policy RetryPolicy, call func() (T, error)) (T, error) {
+	attempts := max(policy.MaxAttempts, 1)
+
+	var res T
+	var err error
+	for attempt := 1; ; attempt++ {
+		res, err = call()
+		if err == nil || attempt >= attempts || !policy.retryable(err) {
+			return res, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return res, err
+		case <-time.After(policy.backoff(attempt)):
+		}
+	}
+}
+
+func (g *RetryGateway) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Account, error) {
+		return g.gateway.GetAccount(ctx, address)
+	})
+}
+
+func (g *RetryGateway) GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Account, error) {
+		return g.gateway.GetAccountAtBlockHeight(ctx, address, height)
+	})
+}
+
+// SendSignedTransaction submits the transaction without ever resending a submission that might have reached the node.
+func (g *RetryGateway) SendSignedTransaction(ctx context.Context, tx *flow.Transaction) (*flow.Transaction, error) {
+	policy := g.policy
+	policy.Retryable = isUndeliveredError
+
+	sent, err := withRetry(ctx, policy, func() (*flow.Transaction, error) {
+		return g.gateway.SendSignedTransaction(ctx, tx)
+	})
+	if err == nil || isUndeliveredError(err) || !g.policy.retryable(err) {
+		return sent, err
+	}
+
+	// the submission might have reached the node, check if the node knows the transaction instead of resending
+	if _, lookupErr := g.GetTransaction(ctx, tx.ID()); lookupErr == nil {
+		return tx, nil
+	}
+
+	return nil, err
+}
+
+func (g *RetryGateway) GetTransaction(ctx context.Context, ID flow.Identifier) (*flow.Transaction, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Transaction, error) {
+		return g.gateway.GetTransaction(ctx, ID)
+	})
+}
+
+func (g *RetryGateway) GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.TransactionResult, error) {
+	return withRetry(ctx, g.policy, func() ([]*flow.TransactionResult, error) {
+		return g.gateway.GetTransactionResultsByBlockID(ctx, blockID)
+	})
+}
+
+func (g *RetryGateway) GetTransactionResult(ctx context.Context, ID flow.Identifier, waitSeal bool) (*flow.TransactionResult, error) {
+	return withRetry(ctx, g.policy, func() (*flow.TransactionResult, error) {
+		return g.gateway.GetTransactionResult(ctx, ID, waitSeal)
+	})
+}
+
+func (g *RetryGateway) GetTransactionsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*flow.Transaction, error) {
+	return withRetry(ctx, g.policy, func() ([]*flow.Transaction, error) {
+		return g.gateway.GetTransactionsByBlockID(ctx, blockID)
+	})
+}
+
+func (g *RetryGateway) GetSystemTransaction(ctx context.Context, blockID flow.Identifier) (*flow.Transaction, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Transaction, error) {
+		return g.gateway.GetSystemTransaction(ctx, blockID)
+	})
+}
+
+func (g *RetryGateway) GetSystemTransactionResult(ctx context.Context, blockID flow.Identifier) (*flow.TransactionResult, error) {
+	return withRetry(ctx, g.policy, func() (*flow.TransactionResult, error) {
+		return g.gateway.GetSystemTransactionResult(ctx, blockID)
+	})
+}
+
+func (g *RetryGateway) GetSystemTransactionWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.Transaction, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Transaction, error) {
+		return g.gateway.GetSystemTransactionWithID(ctx, blockID, systemTxID)
+	})
+}
+
+func (g *RetryGateway) GetSystemTransactionResultWithID(ctx context.Context, blockID flow.Identifier, systemTxID flow.Identifier) (*flow.TransactionResult, error) {
+	return withRetry(ctx, g.policy, func() (*flow.TransactionResult, error) {
+		return g.gateway.GetSystemTransactionResultWithID(ctx, blockID, systemTxID)
+	})
+}
+
+func (g *RetryGateway) ExecuteScript(ctx context.Context, script []byte, arguments []cadence.Value) (cadence.Value, error) {
+	return withRetry(ctx, g.policy, func() (cadence.Value, error) {
+		return g.gateway.ExecuteScript(ctx, script, arguments)
+	})
+}
+
+func (g *RetryGateway) ExecuteScriptAtHeight(ctx context.Context, script []byte, arguments []cadence.Value, height uint64) (cadence.Value, error) {
+	return withRetry(ctx, g.policy, func() (cadence.Value, error) {
+		return g.gateway.ExecuteScriptAtHeight(ctx, script, arguments, height)
+	})
+}
+
+func (g *RetryGateway) ExecuteScriptAtID(ctx context.Context, script []byte, arguments []cadence.Value, ID flow.Identifier) (cadence.Value, error) {
+	return withRetry(ctx, g.policy, func() (cadence.Value, error) {
+		return g.gateway.ExecuteScriptAtID(ctx, script, arguments, ID)
+	})
+}
+
+func (g *RetryGateway) GetLatestBlock(ctx context.Context) (*flow.Block, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Block, error) {
+		return g.gateway.GetLatestBlock(ctx)
+	})
+}
+
+func (g *RetryGateway) GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Block, error) {
+		return g.gateway.GetBlockByHeight(ctx, height)
+	})
+}
+
+func (g *RetryGateway) GetBlockByID(ctx context.Context, ID flow.Identifier) (*flow.Block, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Block, error) {
+		return g.gateway.GetBlockByID(ctx, ID)
+	})
+}
+
+func (g *RetryGateway) GetEvents(ctx context.Context, eventType string, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	return withRetry(ctx, g.policy, func() ([]flow.BlockEvents, error) {
+		return g.gateway.GetEvents(ctx, eventType, startHeight, endHeight)
+	})
+}
+
+func (g *RetryGateway) GetCollection(ctx context.Context, ID flow.Identifier) (*flow.Collection, error) {
+	return withRetry(ctx, g.policy, func() (*flow.Collection, error) {
+		return g.gateway.GetCollection(ctx, ID)
+	})
+}
+
+func (g *RetryGateway) GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error) {
+	return withRetry(ctx, g.policy, func() ([]byte, error) {
+		return g.gateway.GetLatestProtocolStateSnapshot(ctx)
+	})
+}
+
+func (g *RetryGateway) GetNodeVersionInfo(ctx context.Context) (*flow.NodeVersionInfo, error) {
+	return withRetry(ctx, g.policy, func() (*flow.NodeVersionInfo, error) {
+		return g.gateway.GetNodeVersionInfo(ctx)
+	})
+}
+
+func (g *RetryGateway) GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	return withRetry(ctx, g.policy, func() (*flow.ExecutionResult, error) {
+		return g.gateway.GetExecutionResultForBlockID(ctx, blockID)
+	})
+}
+
+func (g *RetryGateway) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionData, error) {
+	return withRetry(ctx, g.policy, func() (*flow.ExecutionData, error) {
+		return g.gateway.GetExecutionDataByBlockID(ctx, blockID)
+	})
+}
+
+// retrySubscription retries establishing the subscription, errors sent after it's established aren't retried.
+func retrySubscription[T any](ctx context.Context, policy RetryPolicy, subscribe func() (<-chan T, <-chan error, error)) (<-chan T, <-chan error, error) {
+	sub, err := withRetry(ctx, policy, func() (subscription[T], error) {
+		return newSubscription(subscribe())
+	})
+	return sub.items, sub.errs, err
+}
+
+func (g *RetryGateway) SubscribeBlocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return retrySubscription(ctx, g.policy, func() (<-chan *flow.Block, <-chan error, error) {
+		return g.gateway.SubscribeBlocks(ctx, startHeight)
+	})
+}
+
+func (g *RetryGateway) SubscribeBlockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return retrySubscription(ctx, g.policy, func() (<-chan *flow.BlockHeader, <-chan error, error) {
+		return g.gateway.SubscribeBlockHeaders(ctx, startHeight)
+	})
+}
+
+func (g *RetryGateway) SubscribeEvents(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	return retrySubscription(ctx, g.policy, func() (<-chan flow.BlockEvents, <-chan error, error) {
+		return g.gateway.SubscribeEvents(ctx, startHeight, filter)
+	})
+}
+
+func (g *RetryGateway) SubscribeAccountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	return retrySubscription(ctx, g.policy, func() (<-chan *flow.AccountStatus, <-chan error, error) {
+		return g.gateway.SubscribeAccountStatuses(ctx, startHeight, filter)
+	})
+}
+
+// Ping is not retried so it reflects the current health of the node.
+func (g *RetryGateway) Ping() error {
+	return g.gateway.Ping()
+}
+
+func (g *RetryGateway) WaitServer(ctx context.Context) error {
+	return g.gateway.WaitServer(ctx)
+}
+
+func (g *RetryGateway) SecureConnection() bool {
+	return g.gateway.SecureConnection()
+}
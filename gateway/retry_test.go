@@ -0,0 +1,182 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	httpAccess "github.com/onflow/flow-go-sdk/access/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestRetryGateway(t *testing.T) {
+	ctx := context.Background()
+	unavailable := status.Error(codes.Unavailable, "node is overloaded")
+	undelivered := fmt.Errorf("failed to send: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+
+	t.Run("Retry until success", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(nil, unavailable).Twice()
+		g.On("GetLatestBlock", ctx).Return(&flow.Block{BlockHeader: flow.BlockHeader{Height: 10}}, nil).Once()
+
+		block, err := NewRetryGateway(g, testRetryPolicy()).GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), block.Height)
+	})
+
+	t.Run("Stop after max attempts", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		g.On("GetAccount", ctx, flow.HexToAddress("01")).Return(nil, unavailable).Times(3)
+
+		_, err := NewRetryGateway(g, testRetryPolicy()).GetAccount(ctx, flow.HexToAddress("01"))
+		assert.ErrorIs(t, err, unavailable)
+	})
+
+	t.Run("Don't retry terminal errors", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		notFound := status.Error(codes.NotFound, "account not found")
+		g.On("GetAccount", ctx, flow.HexToAddress("01")).Return(nil, notFound).Once()
+
+		_, err := NewRetryGateway(g, testRetryPolicy()).GetAccount(ctx, flow.HexToAddress("01"))
+		assert.ErrorIs(t, err, notFound)
+	})
+
+	t.Run("Stop when context is cancelled", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		g := mocks.NewGateway(t)
+		g.On("GetCollection", cancelCtx, flow.EmptyID).Return(nil, unavailable).Once()
+
+		policy := testRetryPolicy()
+		policy.InitialBackoff = time.Hour
+		policy.MaxBackoff = time.Hour
+		_, err := NewRetryGateway(g, policy).GetCollection(cancelCtx, flow.EmptyID)
+		assert.ErrorIs(t, err, unavailable)
+	})
+
+	t.Run("Resend undelivered transaction", func(t *testing.T) {
+		tx := flow.NewTransaction().SetScript([]byte("transaction {}"))
+		g := mocks.NewGateway(t)
+		g.On("SendSignedTransaction", ctx, tx).Return(nil, undelivered).Once()
+		g.On("SendSignedTransaction", ctx, tx).Return(tx, nil).Once()
+
+		sent, err := NewRetryGateway(g, testRetryPolicy()).SendSignedTransaction(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, tx.ID(), sent.ID())
+	})
+
+	t.Run("Don't resend possibly delivered transaction", func(t *testing.T) {
+		tx := flow.NewTransaction().SetScript([]byte("transaction {}"))
+		g := mocks.NewGateway(t)
+		g.On("SendSignedTransaction", ctx, tx).Return(nil, unavailable).Once()
+		g.On("GetTransaction", ctx, tx.ID()).Return(tx, nil).Once()
+
+		sent, err := NewRetryGateway(g, testRetryPolicy()).SendSignedTransaction(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, tx.ID(), sent.ID())
+		g.AssertNumberOfCalls(t, "SendSignedTransaction", 1)
+	})
+
+	t.Run("Return submission error if transaction is unknown", func(t *testing.T) {
+		tx := flow.NewTransaction().SetScript([]byte("transaction {}"))
+		g := mocks.NewGateway(t)
+		g.On("SendSignedTransaction", ctx, tx).Return(nil, unavailable).Once()
+		g.On("GetTransaction", ctx, tx.ID()).Return(nil, status.Error(codes.NotFound, "not found")).Once()
+
+		_, err := NewRetryGateway(g, testRetryPolicy()).SendSignedTransaction(ctx, tx)
+		assert.ErrorIs(t, err, unavailable)
+		g.AssertNumberOfCalls(t, "SendSignedTransaction", 1)
+	})
+
+	t.Run("Ping is not retried", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		g.On("Ping").Return(unavailable).Once()
+
+		assert.ErrorIs(t, NewRetryGateway(g, testRetryPolicy()).Ping(), unavailable)
+	})
+
+	t.Run("Network without retry is not wrapped", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		assert.Equal(t, Gateway(g), NewNetworkRetryGateway(g, config.Network{Name: "testnet"}))
+
+		wrapped := NewNetworkRetryGateway(g, config.Network{Name: "testnet", Retry: config.NetworkRetry{MaxAttempts: 5}})
+		assert.IsType(t, &RetryGateway{}, wrapped)
+		assert.Equal(t, 5, wrapped.(*RetryGateway).policy.MaxAttempts)
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("Defaults for unset values", func(t *testing.T) {
+		policy := NewRetryPolicy(config.NetworkRetry{MaxAttempts: 5, MaxBackoff: time.Second})
+		assert.Equal(t, 5, policy.MaxAttempts)
+		assert.Equal(t, time.Second, policy.MaxBackoff)
+		assert.Equal(t, defaultRetryInitialBackoff, policy.InitialBackoff)
+		assert.Equal(t, float64(defaultRetryMultiplier), policy.Multiplier)
+		assert.Equal(t, defaultRetryJitter, policy.Jitter)
+	})
+
+	t.Run("Exponential backoff", func(t *testing.T) {
+		policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+		assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+		assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+		assert.Equal(t, time.Second, policy.backoff(10))
+
+		policy.Jitter = 0.5
+		for i := 0; i < 10; i++ {
+			delay := policy.backoff(2)
+			assert.GreaterOrEqual(t, delay, 100*time.Millisecond)
+			assert.LessOrEqual(t, delay, 300*time.Millisecond)
+		}
+	})
+
+	t.Run("Retryable errors", func(t *testing.T) {
+		assert.True(t, IsRetryableError(status.Error(codes.Unavailable, "")))
+		assert.True(t, IsRetryableError(fmt.Errorf("wrapped: %w", status.Error(codes.ResourceExhausted, ""))))
+		assert.True(t, IsRetryableError(httpAccess.HTTPError{Code: http.StatusServiceUnavailable}))
+		assert.True(t, IsRetryableError(&net.OpError{Op: "dial", Err: errors.New("refused")}))
+
+		assert.False(t, IsRetryableError(status.Error(codes.InvalidArgument, "")))
+		assert.False(t, IsRetryableError(httpAccess.HTTPError{Code: http.StatusBadRequest}))
+		assert.False(t, IsRetryableError(context.Canceled))
+		assert.False(t, IsRetryableError(errors.New("execution failed")))
+	})
+}
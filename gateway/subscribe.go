@@ -0,0 +1,422 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// DefaultPollInterval is how often the polling subscriptions check for new sealed blocks.
+const DefaultPollInterval = time.Second
+
+// maxPollHeightRange is the largest height range fetched at once, it matches the Access API events range limit.
+const maxPollHeightRange = 250
+
+// accountEventFields are the fields holding the account addresses of the core account events.
+var accountEventFields = map[string][]string{
+	"flow.AccountCreated":         {"address"},
+	"flow.AccountKeyAdded":        {"address"},
+	"flow.AccountKeyRemoved":      {"address"},
+	"flow.AccountContractAdded":   {"address"},
+	"flow.AccountContractUpdated": {"address"},
+	"flow.AccountContractRemoved": {"address"},
+	"flow.InboxValuePublished":    {"provider", "recipient"},
+	"flow.InboxValueUnpublished":  {"provider"},
+	"flow.InboxValueClaimed":      {"provider", "recipient"},
+}
+
+// blockNotifier tells a subscription when new sealed blocks might be available.
+type blockNotifier interface {
+	// wait blocks until new blocks might be available, it returns false once the context is done.
+	wait(ctx context.Context) bool
+	stop()
+}
+
+// pollNotifier fires after every interval.
+type pollNotifier struct {
+	interval time.Duration
+}
+
+func (p pollNotifier) wait(ctx context.Context) bool {
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (p pollNotifier) stop() {}
+
+// subscriptions implement the gateway subscriptions on top of the request/response calls,
+// the gateway is asked for new sealed blocks every time the notifier fires.
+type subscriptions struct {
+	gateway  Gateway
+	notifier func() blockNotifier
+}
+
+// polling returns the subscriptions for the gateways that can't stream.
+func polling(gateway Gateway, interval time.Duration) subscriptions {
+	return subscriptions{
+		gateway: gateway,
+		notifier: func() blockNotifier {
+			return pollNotifier{interval: interval}
+		},
+	}
+}
+
+// follow sends the items fetched for the sealed blocks starting at the start height, or at the latest sealed block
+// if the start height is zero. It stops when the context is done or at the first error, which is sent to the error channel.
+func follow[T any](
+	ctx context.Context,
+	s subscriptions,
+	startHeight uint64,
+	fetch func(ctx context.Context, startHeight uint64, endHeight uint64) ([]T, error),
+) (<-chan T, <-chan error, error) {
+	// the notifier is created before looking up the latest block so no new block is missed
+	notifier := s.notifier()
+
+	latest, err := s.gateway.GetLatestBlock(ctx)
+	if err != nil {
+		notifier.stop()
+		return nil, nil, err
+	}
+
+	next := startHeight
+	if next == 0 {
+		next = latest.Height
+	}
+
+	items := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer notifier.stop()
+		defer close(items)
+		defer close(errs)
+
+		fail := func(err error) {
+			if ctx.Err() == nil {
+				errs <- err
+			}
+		}
+
+		for {
+			for next <= latest.Height {
+				end := min(latest.Height, next+maxPollHeightRange-1)
+				fetched, err := fetch(ctx, next, end)
+				if err != nil {
+					fail(err)
+					return
+				}
+
+				for _, item := range fetched {
+					select {
+					case items <- item:
+					case <-ctx.Done():
+						return
+					}
+				}
+				next = end + 1
+			}
+
+			if !notifier.wait(ctx) {
+				return
+			}
+
+			latest, err = s.gateway.GetLatestBlock(ctx)
+			if err != nil {
+				fail(err)
+				return
+			}
+		}
+	}()
+
+	return items, errs, nil
+}
+
+func (s subscriptions) blocks(ctx context.Context, startHeight uint64) (<-chan *flow.Block, <-chan error, error) {
+	return follow(ctx, s, startHeight, s.fetchBlocks)
+}
+
+func (s subscriptions) blockHeaders(ctx context.Context, startHeight uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	return follow(ctx, s, startHeight, func(ctx context.Context, startHeight uint64, endHeight uint64) ([]*flow.BlockHeader, error) {
+		blocks, err := s.fetchBlocks(ctx, startHeight, endHeight)
+		if err != nil {
+			return nil, err
+		}
+
+		headers := make([]*flow.BlockHeader, 0, len(blocks))
+		for _, b := range blocks {
+			headers = append(headers, &b.BlockHeader)
+		}
+		return headers, nil
+	})
+}
+
+// events sends the blocks containing events matching the filter, an event matches if its type, its contract or the
+// address of its contract is in the filter, like with the Access API.
+//
+// Events can only be looked up by type, so if the filter only lists event types the events are fetched by type.
+// Otherwise all the events of the blocks are fetched from the transaction results and filtered here.
+func (s subscriptions) events(ctx context.Context, startHeight uint64, filter flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	if len(filter.EventTypes) == 0 && len(filter.Addresses) == 0 && len(filter.Contracts) == 0 {
+		return nil, nil, errors.New("polling event subscriptions require at least one event type, address or contract")
+	}
+
+	if len(filter.Addresses) == 0 && len(filter.Contracts) == 0 {
+		return follow(ctx, s, startHeight, func(ctx context.Context, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+			return s.fetchEvents(ctx, filter.EventTypes, startHeight, endHeight)
+		})
+	}
+
+	matcher := newEventMatcher(filter)
+	return follow(ctx, s, startHeight, func(ctx context.Context, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+		return s.fetchMatchingEvents(ctx, matcher, startHeight, endHeight)
+	})
+}
+
+// eventMatcher matches the event types against an event filter.
+type eventMatcher struct {
+	types     map[string]bool
+	contracts map[string]bool
+	addresses map[flow.Address]bool
+}
+
+func newEventMatcher(filter flow.EventFilter) eventMatcher {
+	m := eventMatcher{
+		types:     make(map[string]bool, len(filter.EventTypes)),
+		contracts: make(map[string]bool, len(filter.Contracts)),
+		addresses: make(map[flow.Address]bool, len(filter.Addresses)),
+	}
+	for _, eventType := range filter.EventTypes {
+		m.types[eventType] = true
+	}
+	for _, contract := range filter.Contracts {
+		m.contracts[contract] = true
+	}
+	for _, address := range filter.Addresses {
+		m.addresses[flow.HexToAddress(address)] = true
+	}
+	return m
+}
+
+// match returns true if the event type, its contract or its address is in the filter. Only the events of contracts
+// deployed to accounts, with types of the form A.{address}.{contract}.{event}, have a contract and an address.
+func (m eventMatcher) match(eventType string) bool {
+	if m.types[eventType] {
+		return true
+	}
+
+	parts := strings.Split(eventType, ".")
+	if len(parts) != 4 || parts[0] != "A" {
+		return false
+	}
+
+	return m.contracts[strings.Join(parts[:3], ".")] || m.addresses[flow.HexToAddress(parts[1])]
+}
+
+// accountStatuses sends the core account events grouped by the account addresses for the blocks containing any.
+// If the filter doesn't list any event types all the core account events are included.
+func (s subscriptions) accountStatuses(ctx context.Context, startHeight uint64, filter flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	eventTypes := filter.EventTypes
+	if len(eventTypes) == 0 {
+		eventTypes = slices.Sorted(maps.Keys(accountEventFields))
+	}
+	for _, eventType := range eventTypes {
+		if _, ok := accountEventFields[eventType]; !ok {
+			return nil, nil, fmt.Errorf("unsupported account event type: %s", eventType)
+		}
+	}
+
+	addresses := make(map[flow.Address]bool, len(filter.Addresses))
+	for _, address := range filter.Addresses {
+		addresses[flow.HexToAddress(address)] = true
+	}
+
+	var messageIndex uint64
+	return follow(ctx, s, startHeight, func(ctx context.Context, startHeight uint64, endHeight uint64) ([]*flow.AccountStatus, error) {
+		blocks, err := s.fetchEvents(ctx, eventTypes, startHeight, endHeight)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses := make([]*flow.AccountStatus, 0, len(blocks))
+		for _, block := range blocks {
+			results := groupAccountEvents(block.Events, addresses)
+			if len(results) == 0 {
+				continue
+			}
+
+			statuses = append(statuses, &flow.AccountStatus{
+				BlockID:      block.BlockID,
+				BlockHeight:  block.Height,
+				MessageIndex: messageIndex,
+				Results:      results,
+			})
+			messageIndex++
+		}
+		return statuses, nil
+	})
+}
+
+func (s subscriptions) fetchBlocks(ctx context.Context, startHeight uint64, endHeight uint64) ([]*flow.Block, error) {
+	blocks := make([]*flow.Block, 0, endHeight-startHeight+1)
+	for height := startHeight; height <= endHeight; height++ {
+		block, err := s.gateway.GetBlockByHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// fetchEvents returns the blocks in the height range with their events of the provided types in the order they were emitted,
+// blocks without any events are left out.
+func (s subscriptions) fetchEvents(ctx context.Context, eventTypes []string, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	blocks := make(map[uint64]*flow.BlockEvents)
+	for _, eventType := range eventTypes {
+		res, err := s.gateway.GetEvents(ctx, eventType, startHeight, endHeight)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, b := range res {
+			if len(b.Events) == 0 {
+				continue
+			}
+
+			block, ok := blocks[b.Height]
+			if !ok {
+				block = &flow.BlockEvents{BlockID: b.BlockID, Height: b.Height, BlockTimestamp: b.BlockTimestamp}
+				blocks[b.Height] = block
+			}
+			block.Events = append(block.Events, b.Events...)
+		}
+	}
+
+	res := make([]flow.BlockEvents, 0, len(blocks))
+	for _, height := range slices.Sorted(maps.Keys(blocks)) {
+		block := blocks[height]
+		slices.SortFunc(block.Events, func(a, b flow.Event) int {
+			return cmp.Or(
+				cmp.Compare(a.TransactionIndex, b.TransactionIndex),
+				cmp.Compare(a.EventIndex, b.EventIndex),
+			)
+		})
+		res = append(res, *block)
+	}
+
+	return res, nil
+}
+
+// fetchMatchingEvents returns the blocks in the height range with their events matching in the order they were
+// emitted, blocks without any matching events are left out.
+func (s subscriptions) fetchMatchingEvents(ctx context.Context, matcher eventMatcher, startHeight uint64, endHeight uint64) ([]flow.BlockEvents, error) {
+	blocks, err := s.fetchBlocks(ctx, startHeight, endHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]flow.BlockEvents, 0)
+	for _, block := range blocks {
+		results, err := s.gateway.GetTransactionResultsByBlockID(ctx, block.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var events []flow.Event
+		for _, result := range results {
+			for _, event := range result.Events {
+				if matcher.match(event.Type) {
+					events = append(events, event)
+				}
+			}
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		res = append(res, flow.BlockEvents{
+			BlockID:        block.ID,
+			Height:         block.Height,
+			BlockTimestamp: block.Timestamp,
+			Events:         events,
+		})
+	}
+
+	return res, nil
+}
+
+// groupAccountEvents groups the core account events by the account addresses they refer to,
+// if any addresses are provided only the events for those accounts are included.
+func groupAccountEvents(events []flow.Event, addresses map[flow.Address]bool) []*flow.AccountStatusResult {
+	var results []*flow.AccountStatusResult
+	byAddress := make(map[flow.Address]*flow.AccountStatusResult)
+
+	for _, event := range events {
+		for _, field := range accountEventFields[event.Type] {
+			value, ok := cadence.SearchFieldByName(event.Value, field).(cadence.Address)
+			if !ok {
+				continue
+			}
+
+			address := flow.Address(value)
+			if len(addresses) > 0 && !addresses[address] {
+				continue
+			}
+
+			result, ok := byAddress[address]
+			if !ok {
+				result = &flow.AccountStatusResult{Address: address}
+				byAddress[address] = result
+				results = append(results, result)
+			}
+			result.Events = append(result.Events, event)
+		}
+	}
+
+	return results
+}
+
+// subscription holds the channels of an established subscription,
+// it lets the gateway decorators handle the subscription calls with their generic helpers.
+type subscription[T any] struct {
+	items <-chan T
+	errs  <-chan error
+}
+
+func newSubscription[T any](items <-chan T, errs <-chan error, err error) (subscription[T], error) {
+	return subscription[T]{items: items, errs: errs}, err
+}
@@ -0,0 +1,232 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+	"github.com/onflow/flowkit/v2/tests"
+)
+
+// receive reads n items from the subscription, failing the test if the subscription errors or stalls.
+func receive[T any](t *testing.T, items <-chan T, errs <-chan error, n int) []T {
+	var res []T
+	for len(res) < n {
+		select {
+		case item := <-items:
+			res = append(res, item)
+		case err := <-errs:
+			require.FailNow(t, "subscription failed", err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "subscription timed out")
+		}
+	}
+	return res
+}
+
+// drain cancels the subscription and waits for its channels to close.
+func drain[T any](cancel context.CancelFunc, items <-chan T, errs <-chan error) {
+	cancel()
+	for range items {
+	}
+	for range errs {
+	}
+}
+
+func TestPollingSubscriptions(t *testing.T) {
+	latest := func(height uint64) *flow.Block {
+		return &flow.Block{BlockHeader: flow.BlockHeader{Height: height}}
+	}
+	block := func(height uint64) *flow.Block {
+		b := tests.NewBlock()
+		b.Height = height
+		return b
+	}
+
+	t.Run("Blocks from start height", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(latest(2), nil).Once()
+		g.On("GetLatestBlock", ctx).Return(latest(3), nil)
+		for height := uint64(1); height <= 3; height++ {
+			g.On("GetBlockByHeight", ctx, height).Return(block(height), nil).Once()
+		}
+
+		blocks, errs, err := polling(g, time.Millisecond).blocks(ctx, 1)
+		require.NoError(t, err)
+
+		res := receive(t, blocks, errs, 3)
+		for i, b := range res {
+			assert.Equal(t, uint64(i+1), b.Height)
+		}
+
+		drain(cancel, blocks, errs)
+	})
+
+	t.Run("Block headers from latest", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(latest(7), nil)
+		g.On("GetBlockByHeight", ctx, uint64(7)).Return(block(7), nil).Once()
+
+		headers, errs, err := polling(g, time.Millisecond).blockHeaders(ctx, 0)
+		require.NoError(t, err)
+
+		res := receive(t, headers, errs, 1)
+		assert.Equal(t, uint64(7), res[0].Height)
+
+		drain(cancel, headers, errs)
+	})
+
+	t.Run("Events merged by block", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		deposit := *tests.NewEvent(2, "A.01.Token.Deposited", nil, nil)
+		withdraw := *tests.NewEvent(1, "A.01.Token.Withdrawn", nil, nil)
+
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(latest(6), nil)
+		g.On("GetEvents", ctx, deposit.Type, uint64(5), uint64(6)).Return([]flow.BlockEvents{
+			{Height: 5},
+			{Height: 6, Events: []flow.Event{deposit}},
+		}, nil).Once()
+		g.On("GetEvents", ctx, withdraw.Type, uint64(5), uint64(6)).Return([]flow.BlockEvents{
+			{Height: 5},
+			{Height: 6, Events: []flow.Event{withdraw}},
+		}, nil).Once()
+
+		filter := flow.EventFilter{EventTypes: []string{deposit.Type, withdraw.Type}}
+		events, errs, err := polling(g, time.Millisecond).events(ctx, 5, filter)
+		require.NoError(t, err)
+
+		// the block without events is skipped and the events are in the emitted order
+		res := receive(t, events, errs, 1)
+		assert.Equal(t, uint64(6), res[0].Height)
+		assert.Equal(t, []flow.Event{withdraw, deposit}, res[0].Events)
+
+		drain(cancel, events, errs)
+	})
+
+	t.Run("Events filtered by address and contract", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		deposit := *tests.NewEvent(0, "A.0000000000000001.Token.Deposited", nil, nil)
+		minted := *tests.NewEvent(1, "A.0000000000000002.NFT.Minted", nil, nil)
+		listed := *tests.NewEvent(0, "A.0000000000000003.Market.Listed", nil, nil)
+		created := *tests.NewEvent(1, "flow.AccountCreated", nil, nil)
+		b := block(4)
+
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(latest(4), nil)
+		g.On("GetBlockByHeight", ctx, uint64(4)).Return(b, nil).Once()
+		g.On("GetTransactionResultsByBlockID", ctx, b.ID).Return([]*flow.TransactionResult{
+			{Events: []flow.Event{deposit, minted}},
+			{Events: []flow.Event{listed, created}},
+		}, nil).Once()
+
+		filter := flow.EventFilter{
+			EventTypes: []string{created.Type},
+			Addresses:  []string{"0x01"},
+			Contracts:  []string{"A.0000000000000003.Market"},
+		}
+		events, errs, err := polling(g, time.Millisecond).events(ctx, 4, filter)
+		require.NoError(t, err)
+
+		res := receive(t, events, errs, 1)
+		assert.Equal(t, b.ID, res[0].BlockID)
+		assert.Equal(t, []flow.Event{deposit, listed, created}, res[0].Events)
+
+		drain(cancel, events, errs)
+	})
+
+	t.Run("Events require a filter", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+
+		_, _, err := polling(g, time.Millisecond).events(context.Background(), 0, flow.EventFilter{})
+		assert.EqualError(t, err, "polling event subscriptions require at least one event type, address or contract")
+	})
+
+	t.Run("Account statuses grouped by address", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		first := tests.NewAccountCreateResult(flow.HexToAddress("02")).Events[0]
+		second := tests.NewAccountCreateResult(flow.HexToAddress("03")).Events[0]
+		second.TransactionIndex = 1
+
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(latest(4), nil)
+		g.On("GetEvents", ctx, flow.EventAccountCreated, uint64(4), uint64(4)).Return([]flow.BlockEvents{
+			{Height: 4, Events: []flow.Event{first, second}},
+		}, nil).Once()
+
+		filter := flow.AccountStatusFilter{EventFilter: flow.EventFilter{
+			EventTypes: []string{flow.EventAccountCreated},
+			Addresses:  []string{"0x03"},
+		}}
+		statuses, errs, err := polling(g, time.Millisecond).accountStatuses(ctx, 0, filter)
+		require.NoError(t, err)
+
+		res := receive(t, statuses, errs, 1)
+		assert.Equal(t, uint64(4), res[0].BlockHeight)
+		require.Len(t, res[0].Results, 1)
+		assert.Equal(t, flow.HexToAddress("03"), res[0].Results[0].Address)
+		assert.Equal(t, []flow.Event{second}, res[0].Results[0].Events)
+
+		drain(cancel, statuses, errs)
+	})
+
+	t.Run("Account statuses reject other events", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		filter := flow.AccountStatusFilter{EventFilter: flow.EventFilter{EventTypes: []string{"A.01.Token.Deposited"}}}
+
+		_, _, err := polling(g, time.Millisecond).accountStatuses(context.Background(), 0, filter)
+		assert.EqualError(t, err, "unsupported account event type: A.01.Token.Deposited")
+	})
+
+	t.Run("Stop on error", func(t *testing.T) {
+		ctx := context.Background()
+		failure := errors.New("block not found")
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(latest(3), nil).Once()
+		g.On("GetBlockByHeight", ctx, mock.Anything).Return(nil, failure).Once()
+
+		blocks, errs, err := polling(g, time.Millisecond).blocks(ctx, 3)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, <-errs, failure)
+		_, ok := <-blocks
+		assert.False(t, ok)
+	})
+
+	t.Run("Fail to subscribe", func(t *testing.T) {
+		ctx := context.Background()
+		failure := errors.New("unavailable")
+		g := mocks.NewGateway(t)
+		g.On("GetLatestBlock", ctx).Return(nil, failure).Once()
+
+		_, _, err := polling(g, time.Millisecond).blocks(ctx, 0)
+		assert.ErrorIs(t, err, failure)
+	})
+}
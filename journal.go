@@ -0,0 +1,333 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk"
+)
+
+// JournalStatus is the deployment status of a contract recorded in the deployment journal.
+type JournalStatus string
+
+const (
+	// JournalPending is the status of a contract which transaction wasn't sent yet.
+	JournalPending JournalStatus = "pending"
+	// JournalSent is the status of a contract which transaction was sent, but its result isn't known.
+	JournalSent JournalStatus = "sent"
+	// JournalSealed is the status of a contract deployed with a sealed transaction.
+	JournalSealed JournalStatus = "sealed"
+	// JournalFailed is the status of a contract which deployment failed.
+	JournalFailed JournalStatus = "failed"
+	// JournalSkipped is the status of an unchanged contract.
+	JournalSkipped JournalStatus = "skipped"
+)
+
+// DeploymentJournalEntry records the progress of deploying a planned contract.
+type DeploymentJournalEntry struct {
+	Contract      *PlannedContract `json:"contract"`
+	Status        JournalStatus    `json:"status"`
+	TransactionID string           `json:"transactionId,omitempty"`
+	Error         string           `json:"error,omitempty"`
+}
+
+// DeploymentJournal records the deployment plan and the progress of each contract deployment in a file,
+// so an interrupted deployment can be resumed.
+//
+// The journal is written to the file every time the status of a contract changes.
+type DeploymentJournal struct {
+	Network string                    `json:"network"`
+	Entries []*DeploymentJournalEntry `json:"entries"`
+
+	mu           sync.Mutex
+	readerWriter ReaderWriter
+	path         string
+}
+
+func newDeploymentJournal(plan *DeploymentPlan, readerWriter ReaderWriter, path string) *DeploymentJournal {
+	journal := &DeploymentJournal{
+		Network:      plan.Network,
+		Entries:      make([]*DeploymentJournalEntry, len(plan.Contracts)),
+		readerWriter: readerWriter,
+		path:         path,
+	}
+	for i, contract := range plan.Contracts {
+		journal.Entries[i] = &DeploymentJournalEntry{Contract: contract, Status: JournalPending}
+	}
+
+	return journal
+}
+
+// LoadDeploymentJournal reads the deployment journal from the file at the path.
+func LoadDeploymentJournal(readerWriter ReaderWriter, path string) (*DeploymentJournal, error) {
+	data, err := readerWriter.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployment journal %s: %w", path, err)
+	}
+
+	journal := &DeploymentJournal{}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("failed to decode deployment journal %s: %w", path, err)
+	}
+	journal.readerWriter = readerWriter
+	journal.path = path
+
+	return journal, nil
+}
+
+// Finished returns whether all the contracts in the journal are deployed or skipped.
+func (j *DeploymentJournal) Finished() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, entry := range j.Entries {
+		if entry.Status != JournalSealed && entry.Status != JournalSkipped {
+			return false
+		}
+	}
+	return true
+}
+
+func (j *DeploymentJournal) save() error {
+	data, err := json.MarshalIndent(j, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := j.readerWriter.WriteFile(j.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write deployment journal %s: %w", j.path, err)
+	}
+	return nil
+}
+
+// record updates the entry of the contract and writes the journal, it's a no-op on a nil journal.
+func (j *DeploymentJournal) record(name string, update func(entry *DeploymentJournalEntry)) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, entry := range j.Entries {
+		if entry.Contract.Name == name {
+			update(entry)
+			return j.save()
+		}
+	}
+	return fmt.Errorf("contract %s is not in the deployment journal", name)
+}
+
+func (j *DeploymentJournal) sent(name string, ID flow.Identifier) error {
+	return j.record(name, func(entry *DeploymentJournalEntry) {
+		entry.Status = JournalSent
+		entry.TransactionID = ID.String()
+		entry.Error = ""
+	})
+}
+
+func (j *DeploymentJournal) finish(name string, err error) error {
+	return j.record(name, func(entry *DeploymentJournalEntry) {
+		switch {
+		case err != nil:
+			entry.Status = JournalFailed
+			entry.Error = err.Error()
+		case entry.Contract.Action == DeploymentUnchanged:
+			entry.Status = JournalSkipped
+		default:
+			entry.Status = JournalSealed
+			entry.Error = ""
+		}
+	})
+}
+
+func (j *DeploymentJournal) reset(name string) error {
+	return j.record(name, func(entry *DeploymentJournalEntry) {
+		entry.Status = JournalPending
+		entry.TransactionID = ""
+		entry.Error = ""
+	})
+}
+
+// DeployProjectWithJournal plans and deploys the project contracts like ExecuteDeploymentPlan, and records
+// the progress of the deployment in the journal file at the path.
+//
+// If the journal file exists and the deployment recorded in it isn't finished, that deployment is resumed
+// like with ResumeDeployment instead of planning a new one, so an interrupted deployment is safe to run again.
+func (f *Flowkit) DeployProjectWithJournal(
+	ctx context.Context,
+	update UpdateContract,
+	path string,
+) (*DeploymentJournal, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = state.ReaderWriter().Stat(path)
+	if err == nil {
+		journal, err := LoadDeploymentJournal(state.ReaderWriter(), path)
+		if err != nil {
+			return nil, err
+		}
+		if !journal.Finished() {
+			f.logger.Info(fmt.Sprintf("Resuming the unfinished deployment recorded in %s", path))
+			return journal, f.resumeDeployment(ctx, state, journal)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read deployment journal %s: %w", path, err)
+	}
+
+	plan, _, err := f.planDeployment(ctx, state, update)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := newDeploymentJournal(plan, state.ReaderWriter(), path)
+	if err := journal.save(); err != nil {
+		return nil, err
+	}
+
+	_, err = f.executeDeploymentPlan(ctx, state, plan, false, journal)
+	return journal, err
+}
+
+// ResumeDeployment continues the deployment recorded in the journal file at the path.
+//
+// The journal is first reconciled with the network: the results of sent transactions are fetched, and contracts
+// found on the account with the planned code are marked as deployed. Only the remaining contracts are deployed,
+// and if any of them changed on the network since the deployment was planned a DeploymentDriftError is returned.
+func (f *Flowkit) ResumeDeployment(ctx context.Context, path string) (*DeploymentJournal, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	journal, err := LoadDeploymentJournal(state.ReaderWriter(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	return journal, f.resumeDeployment(ctx, state, journal)
+}
+
+func (f *Flowkit) resumeDeployment(ctx context.Context, state *State, journal *DeploymentJournal) error {
+	if journal.Network != f.network.Name {
+		return fmt.Errorf(
+			"deployment journal was created for network %s, but the network used is %s",
+			journal.Network,
+			f.network.Name,
+		)
+	}
+
+	remaining := &DeploymentPlan{Network: journal.Network}
+	onChain := make(map[flow.Address]*flow.Account)
+	drift := &DeploymentDriftError{}
+	for _, entry := range journal.Entries {
+		done, err := f.reconcileJournalEntry(ctx, journal, entry, onChain)
+		var driftErr *DeploymentDriftError
+		if errors.As(err, &driftErr) {
+			drift.Contracts = append(drift.Contracts, driftErr.Contracts...)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !done {
+			remaining.Contracts = append(remaining.Contracts, entry.Contract)
+		}
+	}
+
+	if len(drift.Contracts) > 0 {
+		return drift
+	}
+
+	f.logger.Info(fmt.Sprintf(
+		"Resuming deployment, %d of %d contracts remaining",
+		len(remaining.Contracts),
+		len(journal.Entries),
+	))
+
+	_, err := f.executeDeploymentPlan(ctx, state, remaining, false, journal)
+	return err
+}
+
+// reconcileJournalEntry updates the journal entry with the state on the network and returns whether the
+// contract is already deployed. Accounts fetched from the network are cached in the provided map.
+func (f *Flowkit) reconcileJournalEntry(
+	ctx context.Context,
+	journal *DeploymentJournal,
+	entry *DeploymentJournalEntry,
+	onChain map[flow.Address]*flow.Account,
+) (bool, error) {
+	contract := entry.Contract
+	switch entry.Status {
+	case JournalSealed, JournalSkipped:
+		return true, nil
+	case JournalSent, JournalFailed:
+		// the transaction result is unknown, and sending it might have failed after the network accepted it,
+		// if it's not found the contract on the account is checked
+		if entry.TransactionID == "" {
+			break
+		}
+		ID := flow.HexToID(entry.TransactionID)
+		result, err := f.gateway.GetTransactionResult(ctx, ID, false)
+		if err == nil && result.Status != flow.TransactionStatusUnknown && result.Status != flow.TransactionStatusExpired {
+			if result.Status < flow.TransactionStatusSealed {
+				result, err = f.waitForSeal(ctx, ID)
+				if err != nil {
+					return false, err
+				}
+			}
+			if result.Error == nil {
+				return true, journal.finish(contract.Name, nil)
+			}
+		}
+	}
+
+	flowAccount, ok := onChain[contract.Address]
+	if !ok {
+		var err error
+		flowAccount, err = f.gateway.GetAccount(ctx, contract.Address)
+		if err != nil {
+			return false, fmt.Errorf("failed to get account %s: %w", contract.Address, err)
+		}
+		onChain[contract.Address] = flowAccount
+	}
+
+	existingHash := ""
+	if existing, exists := flowAccount.Contracts[contract.Name]; exists {
+		existingHash = codeHash(existing)
+	}
+
+	switch existingHash {
+	case contract.SourceHash:
+		return true, journal.finish(contract.Name, nil)
+	case contract.ExistingHash:
+		return false, journal.reset(contract.Name)
+	default:
+		return false, &DeploymentDriftError{Contracts: []string{contract.Name}}
+	}
+}
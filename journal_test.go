@@ -0,0 +1,255 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/gateway"
+	"github.com/onflow/flowkit/v2/tests"
+)
+
+func TestDeploymentJournal_Integration(t *testing.T) {
+	const journalPath = "deployment.journal.json"
+
+	setupDeploymentWith := func(
+		opts []func(*gateway.EmulatorGateway),
+		resources ...tests.Resource,
+	) (*State, Flowkit, *accounts.Account) {
+		state, flowkit := setupIntegration(opts...)
+		srvAcc, _ := state.EmulatorServiceAccount()
+		state.Networks().AddOrUpdate(config.EmulatorNetwork)
+
+		deployments := make([]config.ContractDeployment, len(resources))
+		for i, c := range resources {
+			state.Contracts().AddOrUpdate(config.Contract{Name: c.Name, Location: c.Filename})
+			deployments[i] = config.ContractDeployment{Name: c.Name}
+		}
+		state.Deployments().AddOrUpdate(config.Deployment{
+			Network:   config.EmulatorNetwork.Name,
+			Account:   srvAcc.Name,
+			Contracts: deployments,
+		})
+
+		return state, flowkit, srvAcc
+	}
+	setupDeployment := func(resources ...tests.Resource) (*State, Flowkit, *accounts.Account) {
+		return setupDeploymentWith(nil, resources...)
+	}
+
+	entry := func(journal *DeploymentJournal, name string) *DeploymentJournalEntry {
+		for _, e := range journal.Entries {
+			if e.Contract.Name == name {
+				return e
+			}
+		}
+		require.Failf(t, "missing journal entry", "contract %s", name)
+		return nil
+	}
+
+	t.Run("Record deployment", func(t *testing.T) {
+		t.Parallel()
+
+		state, flowkit, _ := setupDeployment(tests.ContractSimple, tests.ContractHelloString)
+
+		journal, err := flowkit.DeployProjectWithJournal(ctx, UpdateExistingContract(false), journalPath)
+		require.NoError(t, err)
+		assert.True(t, journal.Finished())
+
+		loaded, err := LoadDeploymentJournal(state.ReaderWriter(), journalPath)
+		require.NoError(t, err)
+		require.Len(t, loaded.Entries, 2)
+		for _, e := range loaded.Entries {
+			assert.Equal(t, JournalSealed, e.Status)
+			assert.Equal(t, DeploymentAdd, e.Contract.Action)
+			assert.NotEmpty(t, e.TransactionID)
+			assert.Equal(t, codeHash(e.Contract.Code), e.Contract.SourceHash)
+		}
+
+		// the recorded deployment is finished, so a new one is planned
+		journal, err = flowkit.DeployProjectWithJournal(ctx, UpdateExistingContract(false), journalPath)
+		require.NoError(t, err)
+		for _, e := range journal.Entries {
+			assert.Equal(t, JournalSkipped, e.Status)
+			assert.Empty(t, e.TransactionID)
+		}
+	})
+
+	t.Run("Resume interrupted deployment", func(t *testing.T) {
+		t.Parallel()
+
+		state, flowkit, srvAcc := setupDeployment(tests.ContractSimple, tests.ContractHelloString, tests.ContractA)
+
+		plan, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+		require.NoError(t, err)
+		journal := newDeploymentJournal(plan, state.ReaderWriter(), journalPath)
+
+		// the deployment was interrupted after sending the first transaction and failing the second one,
+		// which was actually sealed
+		simple := entry(journal, tests.ContractSimple.Name)
+		simpleID, err := flowkit.sendContract(ctx, srvAcc, simple.Contract.Name, simple.Contract.Code, nil, false, nil, untilSealed)
+		require.NoError(t, err)
+		simple.Status = JournalSent
+		simple.TransactionID = simpleID.String()
+
+		hello := entry(journal, tests.ContractHelloString.Name)
+		_, err = flowkit.sendContract(ctx, srvAcc, hello.Contract.Name, hello.Contract.Code, nil, false, nil, untilSealed)
+		require.NoError(t, err)
+		hello.Status = JournalFailed
+		hello.Error = "context deadline exceeded"
+		require.NoError(t, journal.save())
+
+		journal, err = flowkit.DeployProjectWithJournal(ctx, UpdateExistingContract(false), journalPath)
+		require.NoError(t, err)
+		assert.True(t, journal.Finished())
+
+		assert.Equal(t, JournalSealed, entry(journal, tests.ContractSimple.Name).Status)
+		assert.Equal(t, simpleID.String(), entry(journal, tests.ContractSimple.Name).TransactionID)
+		assert.Equal(t, JournalSealed, entry(journal, tests.ContractHelloString.Name).Status)
+		assert.Empty(t, entry(journal, tests.ContractHelloString.Name).Error)
+		assert.Equal(t, JournalSealed, entry(journal, tests.ContractA.Name).Status)
+		assert.NotEmpty(t, entry(journal, tests.ContractA.Name).TransactionID)
+
+		account, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		for _, name := range []string{tests.ContractSimple.Name, tests.ContractHelloString.Name, tests.ContractA.Name} {
+			assert.Contains(t, account.Contracts, name)
+		}
+	})
+
+	t.Run("Resume after send error of an accepted transaction", func(t *testing.T) {
+		t.Parallel()
+
+		_, flowkit, srvAcc := setupDeploymentWith(
+			[]func(*gateway.EmulatorGateway){gateway.WithManualMining()},
+			tests.ContractSimple,
+		)
+
+		// the transaction is accepted by the network, but the send fails and it stays pending
+		emulatorGateway := flowkit.gateway
+		flowkit.gateway = &acceptingFailingGateway{Gateway: emulatorGateway}
+
+		journal, err := flowkit.DeployProjectWithJournal(ctx, UpdateExistingContract(false), journalPath)
+		require.ErrorContains(t, err, "deadline exceeded")
+		simple := entry(journal, tests.ContractSimple.Name)
+		assert.Equal(t, JournalFailed, simple.Status)
+		require.NotEmpty(t, simple.TransactionID)
+		sentID := simple.TransactionID
+
+		flowkit.gateway = emulatorGateway
+		journal, err = flowkit.ResumeDeployment(ctx, journalPath)
+		require.NoError(t, err)
+		assert.True(t, journal.Finished())
+		assert.Equal(t, JournalSealed, entry(journal, tests.ContractSimple.Name).Status)
+		assert.Equal(t, sentID, entry(journal, tests.ContractSimple.Name).TransactionID)
+
+		account, err := flowkit.GetAccount(ctx, srvAcc.Address)
+		require.NoError(t, err)
+		assert.Equal(t, tests.ContractSimple.Source, account.Contracts[tests.ContractSimple.Name])
+	})
+
+	t.Run("Fail on drift", func(t *testing.T) {
+		t.Parallel()
+
+		state, flowkit, srvAcc := setupDeployment(tests.ContractSimple)
+
+		plan, err := flowkit.PlanDeployment(ctx, UpdateExistingContract(false))
+		require.NoError(t, err)
+		require.NoError(t, newDeploymentJournal(plan, state.ReaderWriter(), journalPath).save())
+
+		// a different contract was deployed after the journal was created
+		_, err = flowkit.sendContract(ctx, srvAcc, tests.ContractSimple.Name, tests.ContractSimpleUpdated.Source, nil, false, nil, untilSealed)
+		require.NoError(t, err)
+
+		_, err = flowkit.ResumeDeployment(ctx, journalPath)
+		var driftErr *DeploymentDriftError
+		require.ErrorAs(t, err, &driftErr)
+		assert.Equal(t, []string{tests.ContractSimple.Name}, driftErr.Contracts)
+	})
+
+	t.Run("Fail on different network", func(t *testing.T) {
+		t.Parallel()
+
+		state, flowkit, _ := setupDeployment()
+		journal := newDeploymentJournal(&DeploymentPlan{Network: "testnet"}, state.ReaderWriter(), journalPath)
+		require.NoError(t, journal.save())
+
+		_, err := flowkit.ResumeDeployment(ctx, journalPath)
+		assert.EqualError(t, err, "deployment journal was created for network testnet, but the network used is emulator")
+	})
+}
+
+// acceptingFailingGateway sends the transactions, but fails as if the response didn't arrive in time.
+type acceptingFailingGateway struct {
+	gateway.Gateway
+}
+
+func (g *acceptingFailingGateway) SendSignedTransaction(ctx context.Context, tx *flow.Transaction) (*flow.Transaction, error) {
+	if _, err := g.Gateway.SendSignedTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	return nil, context.DeadlineExceeded
+}
+
+func TestDeploymentJournal(t *testing.T) {
+	rw, _ := tests.ReaderWriter()
+	plan := &DeploymentPlan{
+		Network: config.EmulatorNetwork.Name,
+		Contracts: []*PlannedContract{{
+			Name:       tests.ContractSimple.Name,
+			Action:     DeploymentAdd,
+			SourceHash: codeHash(tests.ContractSimple.Source),
+			Code:       tests.ContractSimple.Source,
+		}, {
+			Name:   tests.ContractHelloString.Name,
+			Action: DeploymentUnchanged,
+		}},
+	}
+
+	journal := newDeploymentJournal(plan, rw, "journal.json")
+	require.NoError(t, journal.save())
+	assert.False(t, journal.Finished())
+
+	ID := flow.HexToID("01")
+	require.NoError(t, journal.sent(tests.ContractSimple.Name, ID))
+	require.NoError(t, journal.finish(tests.ContractHelloString.Name, nil))
+
+	loaded, err := LoadDeploymentJournal(rw, "journal.json")
+	require.NoError(t, err)
+	assert.Equal(t, JournalSent, loaded.Entries[0].Status)
+	assert.Equal(t, ID.String(), loaded.Entries[0].TransactionID)
+	assert.Equal(t, plan.Contracts[0], loaded.Entries[0].Contract)
+	assert.Equal(t, JournalSkipped, loaded.Entries[1].Status)
+	assert.False(t, loaded.Finished())
+
+	require.NoError(t, journal.finish(tests.ContractSimple.Name, nil))
+	assert.True(t, journal.Finished())
+
+	assert.EqualError(t, journal.sent("Missing", ID), "contract Missing is not in the deployment journal")
+
+	var nilJournal *DeploymentJournal
+	assert.NoError(t, nilJournal.sent(tests.ContractSimple.Name, ID))
+}
@@ -0,0 +1,254 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/gateway"
+	"github.com/onflow/flowkit/v2/transactions"
+)
+
+// ProposalKey is a key of the pool account leased to propose a single transaction.
+type ProposalKey struct {
+	Index          uint32
+	SequenceNumber uint64
+	// stale is set when the sequence number is unknown and must be fetched before the key is leased again
+	stale bool
+}
+
+// ProposalKeyPool leases the keys of an account to propose transactions sent concurrently by the account.
+//
+// The pool tracks the sequence number of each key locally, so the transactions proposed with different keys
+// don't collide and the proposer account doesn't need to be fetched for each transaction. The account signs
+// the transactions with the leased key, so if the account is also the payer or an authorizer the keys must
+// have the full weight.
+type ProposalKeyPool struct {
+	gateway gateway.Gateway
+	account *accounts.Account
+	keys    chan *ProposalKey
+	// mu guards the stale flag of the keys, which can be set after the key was released
+	mu sync.Mutex
+}
+
+// NewProposalKeyPool creates a pool of the account keys with the indexes, using the gateway to fetch
+// the sequence numbers of the keys.
+func NewProposalKeyPool(
+	ctx context.Context,
+	gw gateway.Gateway,
+	account *accounts.Account,
+	indexes []uint32,
+) (*ProposalKeyPool, error) {
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("proposal key pool requires at least one key index")
+	}
+
+	flowAccount, err := gw.GetAccount(ctx, account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &ProposalKeyPool{
+		gateway: gw,
+		account: account,
+		keys:    make(chan *ProposalKey, len(indexes)),
+	}
+	for i, index := range indexes {
+		if slices.Contains(indexes[:i], index) {
+			return nil, fmt.Errorf("proposal key %d is in the pool more than once", index)
+		}
+		if int(index) >= len(flowAccount.Keys) {
+			return nil, fmt.Errorf("account %s has no key with index %d", account.Address, index)
+		}
+
+		key := flowAccount.Keys[index]
+		if key.Revoked {
+			return nil, fmt.Errorf("key %d of the account %s is revoked", index, account.Address)
+		}
+		pool.keys <- &ProposalKey{Index: key.Index, SequenceNumber: key.SequenceNumber}
+	}
+
+	return pool, nil
+}
+
+// Address of the account proposing the transactions.
+func (p *ProposalKeyPool) Address() flow.Address {
+	return p.account.Address
+}
+
+// Size returns the number of keys in the pool.
+func (p *ProposalKeyPool) Size() int {
+	return cap(p.keys)
+}
+
+// Lease waits until a key is available and leases it, the key must be returned to the pool with Release or Invalidate.
+func (p *ProposalKeyPool) Lease(ctx context.Context) (*ProposalKey, error) {
+	select {
+	case key := <-p.keys:
+		if p.isStale(key) {
+			if err := p.resync(ctx, key); err != nil {
+				p.keys <- key
+				return nil, fmt.Errorf("failed to resync the proposal key %d: %w", key.Index, err)
+			}
+		}
+		return key, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Release returns the key to the pool, incrementing its sequence number if the transaction was sent.
+func (p *ProposalKeyPool) Release(key *ProposalKey, sent bool) {
+	if sent {
+		key.SequenceNumber++
+	}
+	p.keys <- key
+}
+
+// Invalidate returns the key to the pool when its sequence number is unknown, for example after a sequence number
+// mismatch. The sequence number is fetched from the network the next time the key is leased.
+func (p *ProposalKeyPool) Invalidate(key *ProposalKey) {
+	p.invalidateReleased(key)
+	p.keys <- key
+}
+
+// invalidateReleased marks the key as stale after it was already returned to the pool, when the transaction sent
+// with it turns out to not use the sequence number.
+func (p *ProposalKeyPool) invalidateReleased(key *ProposalKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key.stale = true
+}
+
+func (p *ProposalKeyPool) isStale(key *ProposalKey) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return key.stale
+}
+
+// Signer returns the pool account signing with the leased key.
+func (p *ProposalKeyPool) Signer(key *ProposalKey) (*accounts.Account, error) {
+	signingKey, err := accounts.KeyWithIndex(p.account.Key, key.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := *p.account
+	signer.Key = signingKey
+	return &signer, nil
+}
+
+func (p *ProposalKeyPool) resync(ctx context.Context, key *ProposalKey) error {
+	flowAccount, err := p.gateway.GetAccount(ctx, p.account.Address)
+	if err != nil {
+		return err
+	}
+	if int(key.Index) >= len(flowAccount.Keys) {
+		return fmt.Errorf("account %s has no key with index %d", p.account.Address, key.Index)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key.SequenceNumber = flowAccount.Keys[key.Index].SequenceNumber
+	key.stale = false
+	return nil
+}
+
+// pooledRoles returns the account roles with the pool account signing with the leased key.
+func (p *ProposalKeyPool) pooledRoles(roles transactions.AccountRoles, key *ProposalKey) (transactions.AccountRoles, error) {
+	signer, err := p.Signer(key)
+	if err != nil {
+		return transactions.AccountRoles{}, err
+	}
+
+	pooled := roles
+	if pooled.Proposer.Address == signer.Address {
+		pooled.Proposer.Key = signer.Key
+	}
+	if pooled.Payer.Address == signer.Address {
+		pooled.Payer.Key = signer.Key
+	}
+	pooled.Authorizers = make([]accounts.Account, len(roles.Authorizers))
+	for i, authorizer := range roles.Authorizers {
+		if authorizer.Address == signer.Address {
+			authorizer.Key = signer.Key
+		}
+		pooled.Authorizers[i] = authorizer
+	}
+	return pooled, nil
+}
+
+// isSequenceNumberError returns whether the transaction was rejected because the sequence number of the
+// proposal key didn't match the sequence number on the network.
+func isSequenceNumberError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "invalid proposal key") && strings.Contains(err.Error(), "sequence number")
+}
+
+// AddProposalKeys adds count keys to the account for proposing transactions concurrently with a ProposalKeyPool,
+// and returns the indexes of the added keys. The keys have the full weight and the public key of the account key.
+func (f *Flowkit) AddProposalKeys(
+	ctx context.Context,
+	account *accounts.Account,
+	count int,
+) ([]uint32, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("proposal key count must be positive")
+	}
+
+	signer, err := account.Key.Signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flowAccount, err := f.gateway.GetAccount(ctx, account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := transactions.NewAddAccountKeys(account, &flow.AccountKey{
+		PublicKey: signer.PublicKey(),
+		SigAlgo:   account.Key.SigAlgo(),
+		HashAlgo:  account.Key.HashAlgo(),
+		Weight:    flow.AccountKeyWeightThreshold,
+	}, count)
+	if err != nil {
+		return nil, err
+	}
+
+	f.logger.StartProgress(fmt.Sprintf("Adding %d proposal keys to the account %s...", count, account.Address))
+	defer f.logger.StopProgress()
+
+	if _, err := f.sendAccountTransaction(ctx, tx, account); err != nil {
+		return nil, err
+	}
+
+	// the keys are added with the next indexes
+	indexes := make([]uint32, count)
+	for i := range indexes {
+		indexes[i] = uint32(len(flowAccount.Keys) + i)
+	}
+	return indexes, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+)
+
+func TestProposalKeyPool(t *testing.T) {
+	ctx := context.Background()
+	pkey, _ := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("seedseedseedseedseedseedseedseedseedseedseedseed"))
+	account := &accounts.Account{
+		Name:    "Alice",
+		Address: flow.HexToAddress("01"),
+		Key:     accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+	}
+
+	flowAccount := func(sequenceNumbers ...uint64) *flow.Account {
+		acc := &flow.Account{Address: account.Address}
+		for i, seq := range sequenceNumbers {
+			acc.Keys = append(acc.Keys, &flow.AccountKey{Index: uint32(i), SequenceNumber: seq, Weight: flow.AccountKeyWeightThreshold})
+		}
+		return acc
+	}
+
+	t.Run("Lease and release keys", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		g.On("GetAccount", ctx, account.Address).Return(flowAccount(0, 5, 7), nil).Once()
+
+		pool, err := NewProposalKeyPool(ctx, g, account, []uint32{1, 2})
+		require.NoError(t, err)
+		assert.Equal(t, 2, pool.Size())
+		assert.Equal(t, account.Address, pool.Address())
+
+		first, err := pool.Lease(ctx)
+		require.NoError(t, err)
+		second, err := pool.Lease(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, ProposalKey{Index: 1, SequenceNumber: 5}, *first)
+		assert.Equal(t, ProposalKey{Index: 2, SequenceNumber: 7}, *second)
+
+		signer, err := pool.Signer(second)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), signer.Key.Index())
+		assert.Equal(t, uint32(0), account.Key.Index())
+
+		pool.Release(first, true)
+		pool.Release(second, false)
+
+		first, err = pool.Lease(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(6), first.SequenceNumber)
+		second, err = pool.Lease(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), second.SequenceNumber)
+	})
+
+	t.Run("Resync invalidated key", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		g.On("GetAccount", ctx, account.Address).Return(flowAccount(0, 5), nil).Once()
+		g.On("GetAccount", ctx, account.Address).Return(flowAccount(0, 9), nil).Once()
+
+		pool, err := NewProposalKeyPool(ctx, g, account, []uint32{1})
+		require.NoError(t, err)
+
+		key, err := pool.Lease(ctx)
+		require.NoError(t, err)
+		pool.Invalidate(key)
+
+		key, err = pool.Lease(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(9), key.SequenceNumber)
+	})
+
+	t.Run("Wait for available key", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		g.On("GetAccount", ctx, account.Address).Return(flowAccount(0), nil).Once()
+
+		pool, err := NewProposalKeyPool(ctx, g, account, []uint32{0})
+		require.NoError(t, err)
+
+		_, err = pool.Lease(ctx)
+		require.NoError(t, err)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = pool.Lease(cancelCtx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Fail to create pool", func(t *testing.T) {
+		g := mocks.NewGateway(t)
+		revoked := flowAccount(0, 0)
+		revoked.Keys[1].Revoked = true
+		g.On("GetAccount", ctx, account.Address).Return(revoked, nil)
+
+		_, err := NewProposalKeyPool(ctx, g, account, nil)
+		assert.EqualError(t, err, "proposal key pool requires at least one key index")
+
+		_, err = NewProposalKeyPool(ctx, g, account, []uint32{0, 0})
+		assert.EqualError(t, err, "proposal key 0 is in the pool more than once")
+
+		_, err = NewProposalKeyPool(ctx, g, account, []uint32{2})
+		assert.EqualError(t, err, "account 0000000000000001 has no key with index 2")
+
+		_, err = NewProposalKeyPool(ctx, g, account, []uint32{1})
+		assert.EqualError(t, err, "key 1 of the account 0000000000000001 is revoked")
+	})
+
+	t.Run("Sequence number errors", func(t *testing.T) {
+		assert.True(t, isSequenceNumberError(errors.New("[Error Code: 1007] invalid proposal key: public key 1 on account 01 has sequence number 3, but given 2")))
+		assert.False(t, isSequenceNumberError(errors.New("[Error Code: 1006] invalid proposal key: public key 1 on account 01 does not have a valid signature")))
+		assert.False(t, isSequenceNumberError(nil))
+	})
+}
@@ -0,0 +1,238 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/transactions"
+)
+
+// AddKey adds the public key to the account and returns the account key added as well as the ID of the transaction.
+//
+// If the key weight is not specified the key is added with the full weight.
+func (f *Flowkit) AddKey(
+	ctx context.Context,
+	account *accounts.Account,
+	key accounts.PublicKey,
+) (*flow.AccountKey, flow.Identifier, error) {
+	if key.Weight == 0 { // if key weight is not specified
+		key.Weight = flow.AccountKeyWeightThreshold
+	}
+
+	accKey := &flow.AccountKey{
+		PublicKey: key.Public,
+		SigAlgo:   key.SigAlgo,
+		HashAlgo:  key.HashAlgo,
+		Weight:    key.Weight,
+	}
+	if err := accKey.Validate(); err != nil {
+		return nil, flow.EmptyID, fmt.Errorf("invalid account key: %w", err)
+	}
+
+	tx, err := transactions.NewAddAccountKey(account, accKey)
+	if err != nil {
+		return nil, flow.EmptyID, err
+	}
+
+	f.logger.StartProgress(fmt.Sprintf("Adding key to the account %s...", account.Address))
+	defer f.logger.StopProgress()
+
+	ID, err := f.sendAccountTransaction(ctx, tx, account)
+	if err != nil {
+		return nil, flow.EmptyID, err
+	}
+
+	flowAccount, err := f.gateway.GetAccount(ctx, account.Address)
+	if err != nil {
+		return nil, flow.EmptyID, err
+	}
+
+	// the key is added with the next index, so it's the last key with the public key
+	for i := len(flowAccount.Keys) - 1; i >= 0; i-- {
+		if k := flowAccount.Keys[i]; !k.Revoked && k.PublicKey.Equals(key.Public) {
+			return k, ID, nil
+		}
+	}
+
+	return nil, flow.EmptyID, fmt.Errorf("added key couldn't be found on the account %s", account.Address)
+}
+
+// RevokeKey revokes the key with the index on the account and returns the ID of the transaction.
+//
+// The key used for signing by the account can't be revoked, neither can a key whose revocation would leave
+// the account keys with less than the full weight.
+func (f *Flowkit) RevokeKey(
+	ctx context.Context,
+	account *accounts.Account,
+	index uint32,
+) (flow.Identifier, error) {
+	if index == account.Key.Index() {
+		return flow.EmptyID, fmt.Errorf("can not revoke the key %d used for signing by the account %s", index, account.Address)
+	}
+
+	flowAccount, err := f.gateway.GetAccount(ctx, account.Address)
+	if err != nil {
+		return flow.EmptyID, err
+	}
+
+	if int(index) >= len(flowAccount.Keys) {
+		return flow.EmptyID, fmt.Errorf("account %s has no key with index %d", account.Address, index)
+	}
+	if flowAccount.Keys[index].Revoked {
+		return flow.EmptyID, fmt.Errorf("key %d of the account %s is already revoked", index, account.Address)
+	}
+
+	weight := remainingKeyWeight(flowAccount, index)
+	if weight < flow.AccountKeyWeightThreshold {
+		return flow.EmptyID, fmt.Errorf(
+			"can not revoke the key %d, the remaining keys of the account %s would only have weight %d of the required %d",
+			index,
+			account.Address,
+			weight,
+			flow.AccountKeyWeightThreshold,
+		)
+	}
+
+	tx, err := transactions.NewRevokeAccountKey(account, index)
+	if err != nil {
+		return flow.EmptyID, err
+	}
+
+	f.logger.StartProgress(fmt.Sprintf("Revoking key %d of the account %s...", index, account.Address))
+	defer f.logger.StopProgress()
+
+	return f.sendAccountTransaction(ctx, tx, account)
+}
+
+// RotateKey adds the public key of the new key to the account, switches the account to sign with the new key,
+// and revokes the key previously used for signing. Returns the account key added as well as the ID of the
+// transaction revoking the previous key.
+//
+// The account key is updated in the state if the account exists in it, and the index of the new key is set
+// to the index it was added with. If the key weight is not specified the key is added with the full weight.
+func (f *Flowkit) RotateKey(
+	ctx context.Context,
+	account *accounts.Account,
+	key accounts.Key,
+	weight int,
+) (*flow.AccountKey, flow.Identifier, error) {
+	if err := key.Validate(); err != nil {
+		return nil, flow.EmptyID, fmt.Errorf("invalid key: %w", err)
+	}
+
+	if weight == 0 { // if key weight is not specified
+		weight = flow.AccountKeyWeightThreshold
+	}
+
+	flowAccount, err := f.gateway.GetAccount(ctx, account.Address)
+	if err != nil {
+		return nil, flow.EmptyID, err
+	}
+
+	// check the previous key can be revoked before the new key is added
+	remaining := remainingKeyWeight(flowAccount, account.Key.Index()) + weight
+	if remaining < flow.AccountKeyWeightThreshold {
+		return nil, flow.EmptyID, fmt.Errorf(
+			"can not rotate the key %d, the keys of the account %s would only have weight %d of the required %d",
+			account.Key.Index(),
+			account.Address,
+			remaining,
+			flow.AccountKeyWeightThreshold,
+		)
+	}
+
+	signer, err := key.Signer(ctx)
+	if err != nil {
+		return nil, flow.EmptyID, err
+	}
+
+	accKey, _, err := f.AddKey(ctx, account, accounts.PublicKey{
+		Public:   signer.PublicKey(),
+		Weight:   weight,
+		SigAlgo:  key.SigAlgo(),
+		HashAlgo: key.HashAlgo(),
+	})
+	if err != nil {
+		return nil, flow.EmptyID, err
+	}
+
+	newKey, err := accounts.KeyWithIndex(key, accKey.Index)
+	if err != nil {
+		return nil, flow.EmptyID, err
+	}
+
+	previous := *account
+	account.Key = newKey
+
+	if state, err := f.State(); err == nil {
+		if _, err := state.Accounts().ByName(account.Name); err == nil {
+			state.Accounts().AddOrUpdate(account)
+		}
+	}
+
+	ID, err := f.RevokeKey(ctx, account, previous.Key.Index())
+	if err != nil {
+		return accKey, flow.EmptyID, fmt.Errorf("key %d was added and is used for signing, but revoking the previous key failed: %w", accKey.Index, err)
+	}
+
+	return accKey, ID, nil
+}
+
+// remainingKeyWeight returns the weight of the account keys that are not revoked, without the key with the index.
+func remainingKeyWeight(account *flow.Account, index uint32) int {
+	weight := 0
+	for _, k := range account.Keys {
+		if !k.Revoked && k.Index != index {
+			weight += k.Weight
+		}
+	}
+	return weight
+}
+
+// sendAccountTransaction prepares the transaction signed by the account, sends it and waits for it to be sealed.
+func (f *Flowkit) sendAccountTransaction(
+	ctx context.Context,
+	tx *transactions.Transaction,
+	account *accounts.Account,
+) (flow.Identifier, error) {
+	tx, err := f.prepareTransaction(ctx, tx, account)
+	if err != nil {
+		return flow.EmptyID, err
+	}
+
+	sentTx, err := f.gateway.SendSignedTransaction(ctx, tx.FlowTransaction())
+	if err != nil {
+		return flow.EmptyID, err
+	}
+
+	result, err := f.waitForSeal(ctx, sentTx.ID())
+	if err != nil {
+		return flow.EmptyID, err
+	}
+	if result.Error != nil {
+		return flow.EmptyID, result.Error
+	}
+
+	return sentTx.ID(), nil
+}
@@ -69,6 +69,112 @@ func (_m *Services) AddContract(_a0 context.Context, _a1 *accounts.Account, _a2
 	return r0, r1, r2
 }
 
+// AddContractAndTrack provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4, _a5
+func (_m *Services) AddContractAndTrack(_a0 context.Context, _a1 *accounts.Account, _a2 flowkit.Script, _a3 flowkit.UpdateContract, _a4 flow.TransactionStatus, _a5 flowkit.TransactionStatusCallback) (flow.Identifier, bool, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4, _a5)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddContractAndTrack")
+	}
+
+	var r0 flow.Identifier
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, flowkit.Script, flowkit.UpdateContract, flow.TransactionStatus, flowkit.TransactionStatusCallback) (flow.Identifier, bool, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, flowkit.Script, flowkit.UpdateContract, flow.TransactionStatus, flowkit.TransactionStatusCallback) flow.Identifier); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(flow.Identifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, flowkit.Script, flowkit.UpdateContract, flow.TransactionStatus, flowkit.TransactionStatusCallback) bool); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *accounts.Account, flowkit.Script, flowkit.UpdateContract, flow.TransactionStatus, flowkit.TransactionStatusCallback) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// AddKey provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Services) AddKey(_a0 context.Context, _a1 *accounts.Account, _a2 accounts.PublicKey) (*flow.AccountKey, flow.Identifier, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddKey")
+	}
+
+	var r0 *flow.AccountKey
+	var r1 flow.Identifier
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, accounts.PublicKey) (*flow.AccountKey, flow.Identifier, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, accounts.PublicKey) *flow.AccountKey); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, accounts.PublicKey) flow.Identifier); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(flow.Identifier)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *accounts.Account, accounts.PublicKey) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// AddProposalKeys provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Services) AddProposalKeys(_a0 context.Context, _a1 *accounts.Account, _a2 int) ([]uint32, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddProposalKeys")
+	}
+
+	var r0 []uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, int) ([]uint32, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, int) []uint32); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint32)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BuildTransaction provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
 func (_m *Services) BuildTransaction(_a0 context.Context, _a1 transactions.AddressesRoles, _a2 uint32, _a3 flowkit.Script, _a4 uint64) (*transactions.Transaction, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
@@ -99,6 +205,36 @@ func (_m *Services) BuildTransaction(_a0 context.Context, _a1 transactions.Addre
 	return r0, r1
 }
 
+// ContractDependents provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Services) ContractDependents(_a0 context.Context, _a1 flow.Address, _a2 string, _a3 bool) ([]flowkit.DeployedContract, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContractDependents")
+	}
+
+	var r0 []flowkit.DeployedContract
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, string, bool) ([]flowkit.DeployedContract, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, string, bool) []flowkit.DeployedContract); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flowkit.DeployedContract)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateAccount provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Services) CreateAccount(_a0 context.Context, _a1 *accounts.Account, _a2 []accounts.PublicKey) (*flow.Account, flow.Identifier, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -138,6 +274,45 @@ func (_m *Services) CreateAccount(_a0 context.Context, _a1 *accounts.Account, _a
 	return r0, r1, r2
 }
 
+// CreateAccountAndTrack provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *Services) CreateAccountAndTrack(_a0 context.Context, _a1 *accounts.Account, _a2 []accounts.PublicKey, _a3 flow.TransactionStatus, _a4 flowkit.TransactionStatusCallback) (*flow.Account, flow.Identifier, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAccountAndTrack")
+	}
+
+	var r0 *flow.Account
+	var r1 flow.Identifier
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, []accounts.PublicKey, flow.TransactionStatus, flowkit.TransactionStatusCallback) (*flow.Account, flow.Identifier, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, []accounts.PublicKey, flow.TransactionStatus, flowkit.TransactionStatusCallback) *flow.Account); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, []accounts.PublicKey, flow.TransactionStatus, flowkit.TransactionStatusCallback) flow.Identifier); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(flow.Identifier)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *accounts.Account, []accounts.PublicKey, flow.TransactionStatus, flowkit.TransactionStatusCallback) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // DeployProject provides a mock function with given fields: _a0, _a1
 func (_m *Services) DeployProject(_a0 context.Context, _a1 flowkit.UpdateContract) ([]*project.Contract, error) {
 	ret := _m.Called(_a0, _a1)
@@ -168,6 +343,66 @@ func (_m *Services) DeployProject(_a0 context.Context, _a1 flowkit.UpdateContrac
 	return r0, r1
 }
 
+// DeployProjectParallel provides a mock function with given fields: _a0, _a1
+func (_m *Services) DeployProjectParallel(_a0 context.Context, _a1 flowkit.UpdateContract) ([]*project.Contract, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeployProjectParallel")
+	}
+
+	var r0 []*project.Contract
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.UpdateContract) ([]*project.Contract, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.UpdateContract) []*project.Contract); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*project.Contract)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flowkit.UpdateContract) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeployProjectWithJournal provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Services) DeployProjectWithJournal(_a0 context.Context, _a1 flowkit.UpdateContract, _a2 string) (*flowkit.DeploymentJournal, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeployProjectWithJournal")
+	}
+
+	var r0 *flowkit.DeploymentJournal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.UpdateContract, string) (*flowkit.DeploymentJournal, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.UpdateContract, string) *flowkit.DeploymentJournal); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.DeploymentJournal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flowkit.UpdateContract, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DerivePrivateKeyFromMnemonic provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *Services) DerivePrivateKeyFromMnemonic(_a0 context.Context, _a1 string, _a2 crypto.SigningAlgorithm, _a3 string) (crypto.PrivateKey, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -198,6 +433,66 @@ func (_m *Services) DerivePrivateKeyFromMnemonic(_a0 context.Context, _a1 string
 	return r0, r1
 }
 
+// EstimateTransaction provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Services) EstimateTransaction(_a0 context.Context, _a1 transactions.AccountRoles, _a2 flowkit.Script, _a3 float64) (*flowkit.TransactionEstimate, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EstimateTransaction")
+	}
+
+	var r0 *flowkit.TransactionEstimate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, float64) (*flowkit.TransactionEstimate, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, float64) *flowkit.TransactionEstimate); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.TransactionEstimate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, transactions.AccountRoles, flowkit.Script, float64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExecuteDeploymentPlan provides a mock function with given fields: _a0, _a1
+func (_m *Services) ExecuteDeploymentPlan(_a0 context.Context, _a1 *flowkit.DeploymentPlan) (map[string]flow.Identifier, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteDeploymentPlan")
+	}
+
+	var r0 map[string]flow.Identifier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *flowkit.DeploymentPlan) (map[string]flow.Identifier, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *flowkit.DeploymentPlan) map[string]flow.Identifier); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]flow.Identifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *flowkit.DeploymentPlan) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExecuteScript provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Services) ExecuteScript(_a0 context.Context, _a1 flowkit.Script, _a2 flowkit.ScriptQuery) (cadence.Value, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -228,6 +523,36 @@ func (_m *Services) ExecuteScript(_a0 context.Context, _a1 flowkit.Script, _a2 f
 	return r0, r1
 }
 
+// ExecuteScripts provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Services) ExecuteScripts(_a0 context.Context, _a1 []flowkit.BatchScript, _a2 *flowkit.ScriptBatch) ([]flowkit.BatchScriptResult, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteScripts")
+	}
+
+	var r0 []flowkit.BatchScriptResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []flowkit.BatchScript, *flowkit.ScriptBatch) ([]flowkit.BatchScriptResult, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []flowkit.BatchScript, *flowkit.ScriptBatch) []flowkit.BatchScriptResult); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flowkit.BatchScriptResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []flowkit.BatchScript, *flowkit.ScriptBatch) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Gateway provides a mock function with no fields
 func (_m *Services) Gateway() gateway.Gateway {
 	ret := _m.Called()
@@ -435,29 +760,89 @@ func (_m *Services) GetEvents(_a0 context.Context, _a1 []string, _a2 uint64, _a3
 	return r0, r1
 }
 
-// GetSystemTransaction provides a mock function with given fields: _a0, _a1
-func (_m *Services) GetSystemTransaction(_a0 context.Context, _a1 flow.Identifier) (*flow.Transaction, *flow.TransactionResult, error) {
+// GetExecutionData provides a mock function with given fields: _a0, _a1
+func (_m *Services) GetExecutionData(_a0 context.Context, _a1 flow.Identifier) (*flowkit.ExecutionData, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSystemTransaction")
+		panic("no return value specified for GetExecutionData")
 	}
 
-	var r0 *flow.Transaction
-	var r1 *flow.TransactionResult
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) (*flow.Transaction, *flow.TransactionResult, error)); ok {
+	var r0 *flowkit.ExecutionData
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) (*flowkit.ExecutionData, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flow.Transaction); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flowkit.ExecutionData); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*flow.Transaction)
+			r0 = ret.Get(0).(*flowkit.ExecutionData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) *flow.TransactionResult); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExecutionResult provides a mock function with given fields: _a0, _a1
+func (_m *Services) GetExecutionResult(_a0 context.Context, _a1 flow.Identifier) (*flowkit.ExecutionResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExecutionResult")
+	}
+
+	var r0 *flowkit.ExecutionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) (*flowkit.ExecutionResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flowkit.ExecutionResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.ExecutionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSystemTransaction provides a mock function with given fields: _a0, _a1
+func (_m *Services) GetSystemTransaction(_a0 context.Context, _a1 flow.Identifier) (*flow.Transaction, *flow.TransactionResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSystemTransaction")
+	}
+
+	var r0 *flow.Transaction
+	var r1 *flow.TransactionResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) (*flow.Transaction, *flow.TransactionResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *flow.Transaction); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) *flow.TransactionResult); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		if ret.Get(1) != nil {
@@ -627,6 +1012,96 @@ func (_m *Services) Ping() error {
 	return r0
 }
 
+// PlanDeployment provides a mock function with given fields: _a0, _a1
+func (_m *Services) PlanDeployment(_a0 context.Context, _a1 flowkit.UpdateContract) (*flowkit.DeploymentPlan, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlanDeployment")
+	}
+
+	var r0 *flowkit.DeploymentPlan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.UpdateContract) (*flowkit.DeploymentPlan, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.UpdateContract) *flowkit.DeploymentPlan); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.DeploymentPlan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flowkit.UpdateContract) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PrunableContracts provides a mock function with given fields: _a0
+func (_m *Services) PrunableContracts(_a0 context.Context) ([]flowkit.DeployedContract, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrunableContracts")
+	}
+
+	var r0 []flowkit.DeployedContract
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]flowkit.DeployedContract, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []flowkit.DeployedContract); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flowkit.DeployedContract)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneContracts provides a mock function with given fields: _a0, _a1
+func (_m *Services) PruneContracts(_a0 context.Context, _a1 flowkit.DependentsPolicy) ([]flowkit.DeployedContract, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneContracts")
+	}
+
+	var r0 []flowkit.DeployedContract
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.DependentsPolicy) ([]flowkit.DeployedContract, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flowkit.DependentsPolicy) []flowkit.DeployedContract); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flowkit.DeployedContract)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flowkit.DependentsPolicy) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveContract provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Services) RemoveContract(_a0 context.Context, _a1 *accounts.Account, _a2 string) (flow.Identifier, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -657,6 +1132,66 @@ func (_m *Services) RemoveContract(_a0 context.Context, _a1 *accounts.Account, _
 	return r0, r1
 }
 
+// RemoveContractAndTrack provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *Services) RemoveContractAndTrack(_a0 context.Context, _a1 *accounts.Account, _a2 string, _a3 flow.TransactionStatus, _a4 flowkit.TransactionStatusCallback) (flow.Identifier, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveContractAndTrack")
+	}
+
+	var r0 flow.Identifier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, string, flow.TransactionStatus, flowkit.TransactionStatusCallback) (flow.Identifier, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, string, flow.TransactionStatus, flowkit.TransactionStatusCallback) flow.Identifier); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(flow.Identifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, string, flow.TransactionStatus, flowkit.TransactionStatusCallback) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveContractWithDependents provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *Services) RemoveContractWithDependents(_a0 context.Context, _a1 *accounts.Account, _a2 string, _a3 flowkit.DependentsPolicy, _a4 bool) ([]flowkit.DeployedContract, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveContractWithDependents")
+	}
+
+	var r0 []flowkit.DeployedContract
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, string, flowkit.DependentsPolicy, bool) ([]flowkit.DeployedContract, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, string, flowkit.DependentsPolicy, bool) []flowkit.DeployedContract); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flowkit.DeployedContract)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, string, flowkit.DependentsPolicy, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReplaceImportsInScript provides a mock function with given fields: _a0, _a1
 func (_m *Services) ReplaceImportsInScript(_a0 context.Context, _a1 flowkit.Script) (flowkit.Script, error) {
 	ret := _m.Called(_a0, _a1)
@@ -685,6 +1220,105 @@ func (_m *Services) ReplaceImportsInScript(_a0 context.Context, _a1 flowkit.Scri
 	return r0, r1
 }
 
+// ResumeDeployment provides a mock function with given fields: _a0, _a1
+func (_m *Services) ResumeDeployment(_a0 context.Context, _a1 string) (*flowkit.DeploymentJournal, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResumeDeployment")
+	}
+
+	var r0 *flowkit.DeploymentJournal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*flowkit.DeploymentJournal, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *flowkit.DeploymentJournal); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.DeploymentJournal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeKey provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Services) RevokeKey(_a0 context.Context, _a1 *accounts.Account, _a2 uint32) (flow.Identifier, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeKey")
+	}
+
+	var r0 flow.Identifier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, uint32) (flow.Identifier, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, uint32) flow.Identifier); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(flow.Identifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, uint32) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateKey provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Services) RotateKey(_a0 context.Context, _a1 *accounts.Account, _a2 accounts.Key, _a3 int) (*flow.AccountKey, flow.Identifier, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateKey")
+	}
+
+	var r0 *flow.AccountKey
+	var r1 flow.Identifier
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, accounts.Key, int) (*flow.AccountKey, flow.Identifier, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *accounts.Account, accounts.Key, int) *flow.AccountKey); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *accounts.Account, accounts.Key, int) flow.Identifier); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(flow.Identifier)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *accounts.Account, accounts.Key, int) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SendSignedTransaction provides a mock function with given fields: _a0, _a1
 func (_m *Services) SendSignedTransaction(_a0 context.Context, _a1 *transactions.Transaction) (*flow.Transaction, *flow.TransactionResult, error) {
 	ret := _m.Called(_a0, _a1)
@@ -724,6 +1358,36 @@ func (_m *Services) SendSignedTransaction(_a0 context.Context, _a1 *transactions
 	return r0, r1, r2
 }
 
+// SendSignedTransactionAsync provides a mock function with given fields: _a0, _a1
+func (_m *Services) SendSignedTransactionAsync(_a0 context.Context, _a1 *transactions.Transaction) (*flowkit.TransactionHandle, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendSignedTransactionAsync")
+	}
+
+	var r0 *flowkit.TransactionHandle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transactions.Transaction) (*flowkit.TransactionHandle, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transactions.Transaction) *flowkit.TransactionHandle); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.TransactionHandle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transactions.Transaction) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendTransaction provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *Services) SendTransaction(_a0 context.Context, _a1 transactions.AccountRoles, _a2 flowkit.Script, _a3 uint64) (*flow.Transaction, *flow.TransactionResult, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -763,6 +1427,75 @@ func (_m *Services) SendTransaction(_a0 context.Context, _a1 transactions.Accoun
 	return r0, r1, r2
 }
 
+// SendTransactionAndTrack provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4, _a5
+func (_m *Services) SendTransactionAndTrack(_a0 context.Context, _a1 transactions.AccountRoles, _a2 flowkit.Script, _a3 uint64, _a4 flow.TransactionStatus, _a5 flowkit.TransactionStatusCallback) (*flow.Transaction, *flow.TransactionResult, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4, _a5)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendTransactionAndTrack")
+	}
+
+	var r0 *flow.Transaction
+	var r1 *flow.TransactionResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64, flow.TransactionStatus, flowkit.TransactionStatusCallback) (*flow.Transaction, *flow.TransactionResult, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64, flow.TransactionStatus, flowkit.TransactionStatusCallback) *flow.Transaction); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64, flow.TransactionStatus, flowkit.TransactionStatusCallback) *flow.TransactionResult); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*flow.TransactionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64, flow.TransactionStatus, flowkit.TransactionStatusCallback) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SendTransactionAsync provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Services) SendTransactionAsync(_a0 context.Context, _a1 transactions.AccountRoles, _a2 flowkit.Script, _a3 uint64) (*flowkit.TransactionHandle, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendTransactionAsync")
+	}
+
+	var r0 *flowkit.TransactionHandle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64) (*flowkit.TransactionHandle, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64) *flowkit.TransactionHandle); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.TransactionHandle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetLogger provides a mock function with given fields: _a0
 func (_m *Services) SetLogger(_a0 output.Logger) {
 	_m.Called(_a0)
@@ -798,6 +1531,222 @@ func (_m *Services) SignTransactionPayload(_a0 context.Context, _a1 *accounts.Ac
 	return r0, r1
 }
 
+// SimulateTransaction provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Services) SimulateTransaction(_a0 context.Context, _a1 transactions.AccountRoles, _a2 flowkit.Script, _a3 uint64) (*flowkit.TransactionSimulation, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateTransaction")
+	}
+
+	var r0 *flowkit.TransactionSimulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64) (*flowkit.TransactionSimulation, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64) *flowkit.TransactionSimulation); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flowkit.TransactionSimulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, transactions.AccountRoles, flowkit.Script, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeAccountStatuses provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Services) SubscribeAccountStatuses(_a0 context.Context, _a1 uint64, _a2 flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeAccountStatuses")
+	}
+
+	var r0 <-chan *flow.AccountStatus
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.AccountStatusFilter) <-chan *flow.AccountStatus); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *flow.AccountStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, flow.AccountStatusFilter) <-chan error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, flow.AccountStatusFilter) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SubscribeBlockHeaders provides a mock function with given fields: _a0, _a1
+func (_m *Services) SubscribeBlockHeaders(_a0 context.Context, _a1 uint64) (<-chan *flow.BlockHeader, <-chan error, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeBlockHeaders")
+	}
+
+	var r0 <-chan *flow.BlockHeader
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (<-chan *flow.BlockHeader, <-chan error, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) <-chan *flow.BlockHeader); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *flow.BlockHeader)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) <-chan error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SubscribeBlocks provides a mock function with given fields: _a0, _a1
+func (_m *Services) SubscribeBlocks(_a0 context.Context, _a1 uint64) (<-chan *flow.Block, <-chan error, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeBlocks")
+	}
+
+	var r0 <-chan *flow.Block
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (<-chan *flow.Block, <-chan error, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) <-chan *flow.Block); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *flow.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) <-chan error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SubscribeEvents provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Services) SubscribeEvents(_a0 context.Context, _a1 uint64, _a2 flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeEvents")
+	}
+
+	var r0 <-chan flow.BlockEvents
+	var r1 <-chan error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, flow.EventFilter) <-chan flow.BlockEvents); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan flow.BlockEvents)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, flow.EventFilter) <-chan error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, flow.EventFilter) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// TrackTransaction provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Services) TrackTransaction(_a0 context.Context, _a1 flow.Identifier, _a2 flow.TransactionStatus, _a3 flowkit.TransactionStatusCallback) (*flow.TransactionResult, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TrackTransaction")
+	}
+
+	var r0 *flow.TransactionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, flow.TransactionStatus, flowkit.TransactionStatusCallback) (*flow.TransactionResult, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, flow.TransactionStatus, flowkit.TransactionStatusCallback) *flow.TransactionResult); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TransactionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier, flow.TransactionStatus, flowkit.TransactionStatusCallback) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitServer provides a mock function with given fields: _a0
 func (_m *Services) WaitServer(_a0 context.Context) error {
 	ret := _m.Called(_a0)
@@ -99,26 +99,86 @@ func (d *Deployment) add(contract *Contract) error {
 // any imported contract must be deployed before deploying the contract with that import.
 // Only applicable to contracts.
 func (d *Deployment) Sort() ([]*Contract, error) {
-	if d.conflictExists() {
-		return nil, fmt.Errorf("the same contract cannot be deployed to multiple accounts on the same network")
+	sorted, err := d.sort()
+	if err != nil {
+		return nil, err
 	}
 
-	err := d.buildDependencies()
+	contracts := make([]*Contract, len(d.contracts))
+	for i, s := range sorted {
+		contracts[i] = s.Contract
+	}
+
+	return contracts, nil
+}
+
+// Levels groups contracts into dependency levels in the deployment order.
+//
+// Contracts in the first level have no dependencies, and contracts in every next level only depend on
+// contracts in the previous levels, so contracts in the same level can be deployed independently of each other.
+// Contracts within a level are in the same order as returned by Sort.
+func (d *Deployment) Levels() ([][]*Contract, error) {
+	sorted, err := d.sort()
 	if err != nil {
 		return nil, err
 	}
 
-	sorted, err := sortByDeploymentOrder(d.contracts)
+	levels := make([][]*Contract, 0)
+	contractLevels := make(map[*deployContract]int, len(sorted))
+	for _, c := range sorted {
+		level := 0
+		for _, dep := range c.dependencies {
+			// dependencies are sorted before the contract, so their level is already known
+			level = max(level, contractLevels[dep]+1)
+		}
+		contractLevels[c] = level
+
+		if level == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[level] = append(levels[level], c.Contract)
+	}
+
+	return levels, nil
+}
+
+// Dependents returns the contracts directly importing the contract with the provided name.
+//
+// Contracts are returned in the order they were added to the deployment.
+func (d *Deployment) Dependents(name string) ([]*Contract, error) {
+	if _, exists := d.contractsByName[name]; !exists {
+		return nil, fmt.Errorf("contract %s is not part of the deployment", name)
+	}
+
+	err := d.buildDependencies()
 	if err != nil {
 		return nil, err
 	}
 
-	contracts := make([]*Contract, len(d.contracts))
-	for i, s := range sorted {
-		contracts[i] = s.Contract
+	dependents := make([]*Contract, 0)
+	for _, c := range d.contracts {
+		for _, dep := range c.dependencies {
+			if dep.Name == name {
+				dependents = append(dependents, c.Contract)
+				break
+			}
+		}
 	}
 
-	return contracts, nil
+	return dependents, nil
+}
+
+func (d *Deployment) sort() ([]*deployContract, error) {
+	if d.conflictExists() {
+		return nil, fmt.Errorf("the same contract cannot be deployed to multiple accounts on the same network")
+	}
+
+	err := d.buildDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	return sortByDeploymentOrder(d.contracts)
 }
 
 // conflictExists returns true if the same contract is configured to deploy to more than one account for the same network.
@@ -212,3 +212,92 @@ func TestContractDeploymentOrder(t *testing.T) {
 		})
 	}
 }
+
+func newTestContracts(testContracts ...testContract) []*Contract {
+	contracts := make([]*Contract, len(testContracts))
+	for i, contract := range testContracts {
+		contracts[i] = NewContract(
+			strings.Split(contract.location, ".")[0],
+			contract.location,
+			contract.code,
+			contract.accountAddress,
+			contract.accountName,
+			nil,
+		)
+	}
+	return contracts
+}
+
+func TestContractDeploymentLevels(t *testing.T) {
+	locations := func(levels [][]*Contract) [][]string {
+		result := make([][]string, len(levels))
+		for i, level := range levels {
+			for _, contract := range level {
+				result[i] = append(result[i], contract.Location())
+			}
+		}
+		return result
+	}
+
+	t.Run("Group by dependency level", func(t *testing.T) {
+		deployment, err := NewDeployment(newTestContracts(
+			testContractD, testContractG, testContractC, testContractB, testContractA,
+		), nil)
+		require.NoError(t, err)
+
+		levels, err := deployment.Levels()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{testContractB.location, testContractA.location},
+			{testContractG.location, testContractC.location},
+			{testContractD.location},
+		}, locations(levels))
+	})
+
+	t.Run("No contracts", func(t *testing.T) {
+		deployment, err := NewDeployment(nil, nil)
+		require.NoError(t, err)
+
+		levels, err := deployment.Levels()
+		require.NoError(t, err)
+		assert.Empty(t, levels)
+	})
+
+	t.Run("Fail on import cycle", func(t *testing.T) {
+		deployment, err := NewDeployment(newTestContracts(testContractE, testContractF), nil)
+		require.NoError(t, err)
+
+		_, err = deployment.Levels()
+		assert.IsType(t, &CyclicImportError{}, err)
+	})
+}
+
+func TestContractDeploymentDependents(t *testing.T) {
+	deployment, err := NewDeployment(newTestContracts(
+		testContractD, testContractG, testContractC, testContractB, testContractA,
+	), nil)
+	require.NoError(t, err)
+
+	names := func(contracts []*Contract) []string {
+		result := make([]string, len(contracts))
+		for i, contract := range contracts {
+			result[i] = contract.Name
+		}
+		return result
+	}
+
+	dependents, err := deployment.Dependents("foobar/ContractA")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ContractG", "foobar/ContractC"}, names(dependents))
+
+	dependents, err = deployment.Dependents("foobar/ContractC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ContractD"}, names(dependents))
+
+	dependents, err = deployment.Dependents("ContractD")
+	require.NoError(t, err)
+	assert.Empty(t, dependents)
+
+	_, err = deployment.Dependents("ContractE")
+	assert.EqualError(t, err, "contract ContractE is not part of the deployment")
+}
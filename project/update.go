@@ -0,0 +1,122 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/cadence/ast"
+	"github.com/onflow/cadence/common"
+	cadenceErrors "github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/cadence/stdlib"
+	"github.com/onflow/flow-go-sdk"
+)
+
+// ContractNamesProvider returns the names of the contracts deployed to the account.
+//
+// It is used to resolve imports of all the contracts from an address, such as `import 0x01`.
+type ContractNamesProvider func(address flow.Address) ([]string, error)
+
+func (p ContractNamesProvider) GetAccountContractNames(address common.Address) ([]string, error) {
+	if p == nil {
+		return nil, fmt.Errorf("contract names of the account %s are not available", address)
+	}
+	return p(flow.Address(address))
+}
+
+// UpdateViolation is a contract update rule violated by the new contract code.
+//
+// The positions point to the new code, unless the violation is about the existing code, like a missing declaration.
+type UpdateViolation struct {
+	Message   string
+	Secondary string
+	StartPos  ast.Position
+	EndPos    ast.Position
+}
+
+func (v UpdateViolation) String() string {
+	message := v.Message
+	if v.Secondary != "" {
+		message = fmt.Sprintf("%s: %s", message, v.Secondary)
+	}
+	return fmt.Sprintf("%d:%d: %s", v.StartPos.Line, v.StartPos.Column, message)
+}
+
+// ValidateContractUpdate validates the update of the contract deployed to the address from the existing
+// to the new code, using the same rules the network uses when the contract is updated.
+//
+// Both codes must have the imports resolved to addresses. The returned violations are empty if the update is valid,
+// and an error is only returned if any of the codes can't be parsed.
+func ValidateContractUpdate(
+	address flow.Address,
+	name string,
+	existing []byte,
+	new []byte,
+	contractNames ContractNamesProvider,
+) ([]UpdateViolation, error) {
+	oldProgram, err := parser.ParseProgram(nil, existing, parser.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse existing code of contract %s: %w", name, err)
+	}
+
+	newProgram, err := parser.ParseProgram(nil, new, parser.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse new code of contract %s: %w", name, err)
+	}
+
+	validator := stdlib.NewContractUpdateValidator(
+		common.AddressLocation{Address: common.Address(address), Name: name},
+		name,
+		contractNames,
+		oldProgram,
+		newProgram,
+	)
+
+	err = validator.Validate()
+	if err == nil {
+		return nil, nil
+	}
+
+	return updateViolations(err), nil
+}
+
+// updateViolations flattens the validation error into violations.
+func updateViolations(err error) []UpdateViolation {
+	var parent cadenceErrors.ParentError
+	if errors.As(err, &parent) {
+		var violations []UpdateViolation
+		for _, child := range parent.ChildErrors() {
+			violations = append(violations, updateViolations(child)...)
+		}
+		return violations
+	}
+
+	violation := UpdateViolation{Message: err.Error()}
+	if secondary, ok := err.(cadenceErrors.SecondaryError); ok {
+		violation.Secondary = secondary.SecondaryError()
+	}
+	if positioned, ok := err.(ast.HasPosition); ok {
+		violation.StartPos = positioned.StartPosition()
+		violation.EndPos = positioned.EndPosition(nil)
+	}
+
+	return []UpdateViolation{violation}
+}
@@ -0,0 +1,115 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package project
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateContractUpdate(t *testing.T) {
+	address := flow.HexToAddress("01")
+	existing := []byte(`
+access(all) contract Foo {
+    access(all) var a: Int
+    init() { self.a = 1 }
+}`)
+
+	t.Run("Compatible", func(t *testing.T) {
+		updated := []byte(`
+access(all) contract Foo {
+    access(all) var a: Int
+    access(all) fun get(): Int { return self.a }
+    init() { self.a = 1 }
+}`)
+
+		violations, err := ValidateContractUpdate(address, "Foo", existing, updated, nil)
+		require.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Changed field type", func(t *testing.T) {
+		updated := []byte(`
+access(all) contract Foo {
+    access(all) var a: String
+    init() { self.a = "1" }
+}`)
+
+		violations, err := ValidateContractUpdate(address, "Foo", existing, updated, nil)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.Contains(t, violations[0].Message, "mismatching field `a` in `Foo`")
+		assert.Equal(t, "incompatible types. expected `Int`, found `String`", violations[0].Secondary)
+		assert.Equal(t, 3, violations[0].StartPos.Line)
+		assert.Equal(t, 23, violations[0].StartPos.Column)
+		assert.Equal(t, "3:23: mismatching field `a` in `Foo`: incompatible types. expected `Int`, found `String`", violations[0].String())
+	})
+
+	t.Run("Added field", func(t *testing.T) {
+		updated := []byte(`
+access(all) contract Foo {
+    access(all) var a: Int
+    access(all) var b: Int
+    init() { self.a = 1; self.b = 2 }
+}`)
+
+		violations, err := ValidateContractUpdate(address, "Foo", existing, updated, nil)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.Contains(t, violations[0].Message, "found new field `b` in `Foo`")
+		assert.Equal(t, 4, violations[0].StartPos.Line)
+	})
+
+	t.Run("Import all contracts from address", func(t *testing.T) {
+		withImport := func(fieldType string) []byte {
+			return []byte(`
+import 0x02
+
+access(all) contract Foo {
+    access(all) var a: ` + fieldType + `?
+    init() { self.a = nil }
+}`)
+		}
+
+		names := ContractNamesProvider(func(address flow.Address) ([]string, error) {
+			assert.Equal(t, flow.HexToAddress("02"), address)
+			return []string{"Bar"}, nil
+		})
+		violations, err := ValidateContractUpdate(address, "Foo", withImport("Bar.Baz"), withImport("Bar.Baz"), names)
+		require.NoError(t, err)
+		assert.Empty(t, violations)
+
+		failing := ContractNamesProvider(func(address flow.Address) ([]string, error) {
+			return nil, errors.New("account not found")
+		})
+		violations, err = ValidateContractUpdate(address, "Foo", withImport("Bar.Baz"), withImport("Bar.Baz"), failing)
+		require.NoError(t, err)
+		require.Len(t, violations, 2) // reported for both the existing and the new code
+		assert.Equal(t, "account not found", violations[0].Message)
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		_, err := ValidateContractUpdate(address, "Foo", existing, []byte("access(all) contract Foo {"), nil)
+		assert.ErrorContains(t, err, "failed to parse new code of contract Foo")
+	})
+}
@@ -0,0 +1,505 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/parser"
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/output"
+	"github.com/onflow/flowkit/v2/project"
+)
+
+// DependentsPolicy defines how contracts other contracts depend on are removed.
+type DependentsPolicy string
+
+const (
+	// RefuseDependents refuses to remove contracts other contracts depend on.
+	RefuseDependents DependentsPolicy = "refuse"
+	// WarnDependents removes the contracts and warns about the dependents left behind.
+	WarnDependents DependentsPolicy = "warn"
+	// CascadeDependents removes the dependents, in reverse dependency order, before the contracts.
+	CascadeDependents DependentsPolicy = "cascade"
+)
+
+// DeployedContract identifies a contract deployed to an account.
+type DeployedContract struct {
+	Name    string
+	Address flow.Address
+	// Account is the name of the configured account with the address, empty if the account isn't configured.
+	Account string
+}
+
+func (c DeployedContract) String() string {
+	return fmt.Sprintf("%s (0x%s)", c.Name, c.Address)
+}
+
+// ContractDependentsError is returned when contracts can't be removed because other contracts depend on them.
+type ContractDependentsError struct {
+	Contracts  []DeployedContract
+	Dependents []DeployedContract
+}
+
+func (e *ContractDependentsError) Error() string {
+	return fmt.Sprintf(
+		"can not remove %s, the dependent contracts must be removed first: %s",
+		joinContracts(e.Contracts),
+		joinContracts(e.Dependents),
+	)
+}
+
+func joinContracts(contracts []DeployedContract) string {
+	names := make([]string, len(contracts))
+	for i, c := range contracts {
+		names[i] = c.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// contractGraph holds the contracts importing each contract.
+type contractGraph struct {
+	dependents map[contractKey][]DeployedContract
+	// deployed contains the names of the contracts on the accounts fetched from the network
+	deployed map[flow.Address][]string
+}
+
+type contractKey struct {
+	name    string
+	address flow.Address
+}
+
+func keyOf(c DeployedContract) contractKey {
+	return contractKey{name: c.Name, address: c.Address}
+}
+
+func (g *contractGraph) addDependent(contract DeployedContract, dependent DeployedContract) {
+	key := keyOf(contract)
+	for _, existing := range g.dependents[key] {
+		if keyOf(existing) == keyOf(dependent) {
+			return
+		}
+	}
+	g.dependents[key] = append(g.dependents[key], dependent)
+}
+
+// removalOrder returns the provided contracts with all the contracts depending on them, directly or transitively,
+// ordered so every contract comes before the contracts it imports.
+func (g *contractGraph) removalOrder(contracts []DeployedContract) []DeployedContract {
+	visited := make(map[contractKey]bool)
+	order := make([]DeployedContract, 0)
+
+	var visit func(contract DeployedContract)
+	visit = func(contract DeployedContract) {
+		if visited[keyOf(contract)] {
+			return
+		}
+		visited[keyOf(contract)] = true
+
+		for _, dependent := range g.dependents[keyOf(contract)] {
+			visit(dependent)
+		}
+		order = append(order, contract)
+	}
+
+	for _, contract := range contracts {
+		visit(contract)
+	}
+
+	return order
+}
+
+// deployedContract returns the contract with the name of the configured account deployed to the address.
+func deployedContract(state *State, address flow.Address, name string) DeployedContract {
+	contract := DeployedContract{Name: name, Address: address}
+	if account, err := state.Accounts().ByAddress(address); err == nil {
+		contract.Account = account.Name
+	}
+	return contract
+}
+
+// contractGraph builds the graph of the contracts from the project deployments on the network and, if onChain is set,
+// from the imports of the contracts deployed to the provided and the configured accounts.
+func (f *Flowkit) contractGraph(
+	ctx context.Context,
+	state *State,
+	onChain bool,
+	addresses ...flow.Address,
+) (*contractGraph, error) {
+	graph := &contractGraph{
+		dependents: make(map[contractKey][]DeployedContract),
+		deployed:   make(map[flow.Address][]string),
+	}
+
+	contracts, err := state.DeploymentContractsByNetwork(f.network)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := project.NewDeployment(contracts, state.AliasesForNetwork(f.network))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[*project.Contract]string, len(contracts))
+	for _, contract := range contracts {
+		names[contract], err = declaredName(contract)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, contract := range contracts {
+		dependents, err := deployment.Dependents(contract.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, dependent := range dependents {
+			graph.addDependent(
+				deployedContract(state, contract.AccountAddress, names[contract]),
+				deployedContract(state, dependent.AccountAddress, names[dependent]),
+			)
+		}
+	}
+
+	if !onChain {
+		return graph, nil
+	}
+
+	for _, account := range *state.AccountsForNetwork(f.network) {
+		addresses = append(addresses, account.Address)
+	}
+
+	onChainContracts := make(map[flow.Address]map[string][]byte)
+	for _, address := range addresses {
+		if _, ok := onChainContracts[address]; ok {
+			continue
+		}
+		flowAccount, err := f.gateway.GetAccount(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", address, err)
+		}
+		onChainContracts[address] = flowAccount.Contracts
+		graph.deployed[address] = contractNames(flowAccount.Contracts)
+	}
+
+	for _, address := range addresses {
+		for _, name := range graph.deployed[address] {
+			program, err := parser.ParseProgram(nil, onChainContracts[address][name], parser.Config{})
+			if err != nil {
+				f.logger.Info(fmt.Sprintf(
+					"%s Skipping imports of contract %s on account 0x%s, the code can't be parsed: %s",
+					output.WarningEmoji(),
+					name,
+					address,
+					err,
+				))
+				continue
+			}
+
+			dependent := deployedContract(state, address, name)
+			for _, declaration := range program.ImportDeclarations() {
+				location, ok := declaration.Location.(common.AddressLocation)
+				if !ok {
+					continue
+				}
+				importAddress := flow.Address(location.Address)
+
+				imported := make([]string, 0, len(declaration.Imports))
+				for _, identifier := range declaration.Imports {
+					imported = append(imported, identifier.Identifier.Identifier)
+				}
+				// imports without identifiers import all the contracts from the address
+				if len(imported) == 0 {
+					if _, ok := graph.deployed[importAddress]; !ok {
+						flowAccount, err := f.gateway.GetAccount(ctx, importAddress)
+						if err != nil {
+							return nil, fmt.Errorf("failed to get account %s: %w", importAddress, err)
+						}
+						graph.deployed[importAddress] = contractNames(flowAccount.Contracts)
+					}
+					imported = graph.deployed[importAddress]
+				}
+
+				for _, importedName := range imported {
+					graph.addDependent(deployedContract(state, importAddress, importedName), dependent)
+				}
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// declaredName returns the name of the contract declared in its code, the contract is deployed under this name
+// which can differ from the name in the configuration.
+func declaredName(contract *project.Contract) (string, error) {
+	program, err := project.NewProgram(contract.Code(), contract.Args, contract.Location())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse contract %s: %w", contract.Name, err)
+	}
+
+	name, err := program.Name()
+	if err != nil {
+		return "", fmt.Errorf("invalid contract %s: %w", contract.Name, err)
+	}
+
+	return name, nil
+}
+
+func contractNames(contracts map[string][]byte) []string {
+	names := make([]string, 0, len(contracts))
+	for name := range contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ContractDependents returns the contracts depending on the contract deployed to the address, directly or
+// transitively, in the order they must be removed.
+//
+// Dependents are found in the project deployments on the network, and if onChain is set also in the code of the
+// contracts deployed to the configured accounts.
+func (f *Flowkit) ContractDependents(
+	ctx context.Context,
+	address flow.Address,
+	name string,
+	onChain bool,
+) ([]DeployedContract, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := f.contractGraph(ctx, state, onChain, address)
+	if err != nil {
+		return nil, err
+	}
+
+	order := graph.removalOrder([]DeployedContract{deployedContract(state, address, name)})
+	// the contract itself is always the last one
+	return order[:len(order)-1], nil
+}
+
+// RemoveContractWithDependents removes the contract from the account and handles the contracts depending on it
+// based on the policy, the removed contracts are returned in the order they were removed.
+//
+// Dependents are found the same way as in ContractDependents. Cascading removes the dependents deployed to the
+// configured accounts before the contract, and fails without removing anything if any dependent account isn't configured.
+func (f *Flowkit) RemoveContractWithDependents(
+	ctx context.Context,
+	account *accounts.Account,
+	name string,
+	policy DependentsPolicy,
+	onChain bool,
+) ([]DeployedContract, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := f.contractGraph(ctx, state, onChain, account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := deployedContract(state, account.Address, name)
+	contract.Account = account.Name
+
+	return f.removeContracts(ctx, state, graph, []DeployedContract{contract}, policy)
+}
+
+// PrunableContracts returns the contracts deployed to the configured accounts on the network which are no longer
+// in the project deployments.
+func (f *Flowkit) PrunableContracts(ctx context.Context) ([]DeployedContract, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.prunableContracts(ctx, state)
+}
+
+func (f *Flowkit) prunableContracts(ctx context.Context, state *State) ([]DeployedContract, error) {
+	contracts, err := state.DeploymentContractsByNetwork(f.network)
+	if err != nil {
+		return nil, err
+	}
+
+	// the deployed contracts are compared by the declared names, since that's what they are deployed under
+	configured := make(map[contractKey]bool, len(contracts))
+	for _, contract := range contracts {
+		name, err := declaredName(contract)
+		if err != nil {
+			return nil, err
+		}
+		configured[contractKey{name: name, address: contract.AccountAddress}] = true
+	}
+
+	prunable := make([]DeployedContract, 0)
+	for _, account := range *state.AccountsForNetwork(f.network) {
+		flowAccount, err := f.gateway.GetAccount(ctx, account.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", account.Address, err)
+		}
+
+		for _, name := range contractNames(flowAccount.Contracts) {
+			if !configured[contractKey{name: name, address: account.Address}] {
+				prunable = append(prunable, DeployedContract{Name: name, Address: account.Address, Account: account.Name})
+			}
+		}
+	}
+
+	return prunable, nil
+}
+
+// PruneContracts removes the contracts deployed to the configured accounts on the network which are no longer in the
+// project deployments, and handles the contracts depending on them based on the policy.
+//
+// All the contracts not in the deployments are removed, including the ones deployed by other means, use
+// PrunableContracts to review them first. The removed contracts are returned in the order they were removed.
+func (f *Flowkit) PruneContracts(ctx context.Context, policy DependentsPolicy) ([]DeployedContract, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, err
+	}
+
+	prunable, err := f.prunableContracts(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if len(prunable) == 0 {
+		return prunable, nil
+	}
+
+	graph, err := f.contractGraph(ctx, state, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.removeContracts(ctx, state, graph, prunable, policy)
+}
+
+// removeContracts removes the contracts and their dependents in the removal order based on the policy.
+func (f *Flowkit) removeContracts(
+	ctx context.Context,
+	state *State,
+	graph *contractGraph,
+	contracts []DeployedContract,
+	policy DependentsPolicy,
+) ([]DeployedContract, error) {
+	switch policy {
+	case RefuseDependents, WarnDependents, CascadeDependents:
+	default:
+		return nil, fmt.Errorf("invalid dependents policy %s", policy)
+	}
+
+	removed := make(map[contractKey]bool, len(contracts))
+	for _, c := range contracts {
+		removed[keyOf(c)] = true
+	}
+
+	order := graph.removalOrder(contracts)
+	dependents := make([]DeployedContract, 0)
+	for _, c := range order {
+		if !removed[keyOf(c)] {
+			dependents = append(dependents, c)
+		}
+	}
+
+	remove := order
+	if len(dependents) > 0 {
+		switch policy {
+		case RefuseDependents:
+			return nil, &ContractDependentsError{Contracts: contracts, Dependents: dependents}
+		case WarnDependents:
+			f.logger.Info(fmt.Sprintf(
+				"%s Removing %s, the contracts depending on it will break: %s",
+				output.WarningEmoji(),
+				joinContracts(contracts),
+				joinContracts(dependents),
+			))
+			remove = make([]DeployedContract, 0, len(contracts))
+			for _, c := range order {
+				if removed[keyOf(c)] {
+					remove = append(remove, c)
+				}
+			}
+		case CascadeDependents:
+			// dependents from the project deployments might not be deployed yet
+			remove = make([]DeployedContract, 0, len(order))
+			for _, c := range order {
+				if !removed[keyOf(c)] {
+					deployed, err := f.isDeployed(ctx, graph, c)
+					if err != nil {
+						return nil, err
+					}
+					if !deployed {
+						continue
+					}
+				}
+				remove = append(remove, c)
+			}
+		}
+	}
+
+	// resolve all the accounts before removing anything
+	removeAccounts := make([]*accounts.Account, len(remove))
+	for i, c := range remove {
+		account, err := state.Accounts().ByAddress(c.Address)
+		if err != nil {
+			return nil, fmt.Errorf("can not remove contract %s, the account is not configured", c)
+		}
+		removeAccounts[i] = account
+	}
+
+	done := make([]DeployedContract, 0, len(remove))
+	for i, c := range remove {
+		_, err := f.RemoveContract(ctx, removeAccounts[i], c.Name)
+		if err != nil {
+			return done, fmt.Errorf("failed to remove contract %s: %w", c, err)
+		}
+		done = append(done, c)
+	}
+
+	return done, nil
+}
+
+// isDeployed returns whether the contract is deployed to its account, fetching the account if it's not in the graph.
+func (f *Flowkit) isDeployed(ctx context.Context, graph *contractGraph, contract DeployedContract) (bool, error) {
+	names, ok := graph.deployed[contract.Address]
+	if !ok {
+		flowAccount, err := f.gateway.GetAccount(ctx, contract.Address)
+		if err != nil {
+			return false, fmt.Errorf("failed to get account %s: %w", contract.Address, err)
+		}
+		names = contractNames(flowAccount.Contracts)
+		graph.deployed[contract.Address] = names
+	}
+
+	return slices.Contains(names, contract.Name), nil
+}
@@ -0,0 +1,153 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-go-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/gateway"
+	"github.com/onflow/flowkit/v2/tests"
+)
+
+func TestRemoveContractWithDependents_Integration(t *testing.T) {
+	state, flowkit := setupIntegration(gateway.WithEmulatorOptions(emulator.WithContractRemovalEnabled(true)))
+	srvAcc, _ := state.EmulatorServiceAccount()
+	state.Networks().AddOrUpdate(config.EmulatorNetwork)
+
+	pkey, _ := crypto.GeneratePrivateKey(crypto.ECDSA_P256, []byte("seedseedseedseedseedseedseedseedseedseedseedseedRemove"))
+	flowAcc, _, err := flowkit.CreateAccount(ctx, srvAcc, []accounts.PublicKey{{
+		Public:   pkey.PublicKey(),
+		SigAlgo:  crypto.ECDSA_P256,
+		HashAlgo: crypto.SHA3_256,
+	}})
+	require.NoError(t, err)
+	alice := &accounts.Account{
+		Name:    "Alice",
+		Address: flowAcc.Address,
+		Key:     accounts.NewHexKeyFromPrivateKey(0, crypto.SHA3_256, pkey),
+	}
+	state.Accounts().AddOrUpdate(alice)
+
+	// the contracts are deployed under the names declared in their code, which can differ from the configured names
+	configNames := map[string]string{
+		tests.ContractA.Name:      "AliasedContractA",
+		tests.ContractB.Name:      tests.ContractB.Name,
+		tests.ContractC.Name:      tests.ContractC.Name,
+		tests.ContractSimple.Name: "AliasedSimple",
+	}
+	for _, c := range []tests.Resource{tests.ContractA, tests.ContractB, tests.ContractC, tests.ContractSimple} {
+		state.Contracts().AddOrUpdate(config.Contract{Name: configNames[c.Name], Location: c.Filename})
+	}
+	configDeployment := config.Deployment{
+		Network: config.EmulatorNetwork.Name,
+		Account: alice.Name,
+		Contracts: []config.ContractDeployment{
+			{Name: configNames[tests.ContractA.Name]},
+			{Name: tests.ContractB.Name},
+			{Name: tests.ContractC.Name, Args: []cadence.Value{cadence.String("foo")}},
+			{Name: configNames[tests.ContractSimple.Name]},
+		},
+	}
+	state.Deployments().AddOrUpdate(configDeployment)
+
+	_, err = flowkit.DeployProject(ctx, UpdateExistingContract(false))
+	require.NoError(t, err)
+
+	// deploying adds the declared names to the deployment, which the saved configuration wouldn't have
+	state.Deployments().AddOrUpdate(configDeployment)
+	deployment := state.Deployments().ByAccountAndNetwork(alice.Name, config.EmulatorNetwork.Name)
+
+	contract := func(name string) DeployedContract {
+		return DeployedContract{Name: name, Address: alice.Address, Account: alice.Name}
+	}
+	deployed := func() []string {
+		account, err := flowkit.GetAccount(ctx, alice.Address)
+		require.NoError(t, err)
+		return contractNames(account.Contracts)
+	}
+
+	t.Run("Dependents", func(t *testing.T) {
+		dependents, err := flowkit.ContractDependents(ctx, alice.Address, tests.ContractA.Name, false)
+		require.NoError(t, err)
+		assert.Equal(t, []DeployedContract{contract(tests.ContractC.Name), contract(tests.ContractB.Name)}, dependents)
+
+		dependents, err = flowkit.ContractDependents(ctx, alice.Address, tests.ContractSimple.Name, true)
+		require.NoError(t, err)
+		assert.Empty(t, dependents)
+
+		_, err = flowkit.RemoveContractWithDependents(ctx, alice, tests.ContractA.Name, RefuseDependents, true)
+		var dependentsErr *ContractDependentsError
+		require.ErrorAs(t, err, &dependentsErr)
+		assert.Equal(t, []DeployedContract{contract(tests.ContractC.Name), contract(tests.ContractB.Name)}, dependentsErr.Dependents)
+		assert.Len(t, deployed(), 4)
+
+		_, err = flowkit.RemoveContractWithDependents(ctx, alice, tests.ContractA.Name, "ignore", true)
+		assert.EqualError(t, err, "invalid dependents policy ignore")
+	})
+
+	t.Run("Cascade with on-chain dependents", func(t *testing.T) {
+		deployment.RemoveContract(tests.ContractC.Name)
+
+		dependents, err := flowkit.ContractDependents(ctx, alice.Address, tests.ContractB.Name, false)
+		require.NoError(t, err)
+		assert.Empty(t, dependents)
+
+		dependents, err = flowkit.ContractDependents(ctx, alice.Address, tests.ContractB.Name, true)
+		require.NoError(t, err)
+		assert.Equal(t, []DeployedContract{contract(tests.ContractC.Name)}, dependents)
+
+		removed, err := flowkit.RemoveContractWithDependents(ctx, alice, tests.ContractB.Name, CascadeDependents, true)
+		require.NoError(t, err)
+		assert.Equal(t, []DeployedContract{contract(tests.ContractC.Name), contract(tests.ContractB.Name)}, removed)
+		assert.Equal(t, []string{tests.ContractA.Name, tests.ContractSimple.Name}, deployed())
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		// ContractB is still in the deployments
+		removed, err := flowkit.RemoveContractWithDependents(ctx, alice, tests.ContractA.Name, WarnDependents, false)
+		require.NoError(t, err)
+		assert.Equal(t, []DeployedContract{contract(tests.ContractA.Name)}, removed)
+		assert.Equal(t, []string{tests.ContractSimple.Name}, deployed())
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		prunable, err := flowkit.PrunableContracts(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, prunable)
+
+		deployment.RemoveContract(configNames[tests.ContractSimple.Name])
+
+		prunable, err = flowkit.PrunableContracts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []DeployedContract{contract(tests.ContractSimple.Name)}, prunable)
+
+		removed, err := flowkit.PruneContracts(ctx, RefuseDependents)
+		require.NoError(t, err)
+		assert.Equal(t, prunable, removed)
+		assert.Empty(t, deployed())
+	})
+}
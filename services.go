@@ -51,6 +51,36 @@ type Services interface {
 	// Keys is a slice but only one can be passed as well. If the transaction fails or there are other issues an error is returned.
 	CreateAccount(context.Context, *accounts.Account, []accounts.PublicKey) (*flow.Account, flow.Identifier, error)
 
+	// CreateAccountAndTrack creates the account like CreateAccount, but only waits until the transaction reaches the
+	// provided status, which must be at least executed for the account address to be known. The callback is called
+	// with every status transition and can be nil.
+	//
+	// If the transaction isn't tracked until sealed the account isn't fetched from the network, and the returned
+	// account only contains the address and the keys.
+	CreateAccountAndTrack(context.Context, *accounts.Account, []accounts.PublicKey, flow.TransactionStatus, TransactionStatusCallback) (*flow.Account, flow.Identifier, error)
+
+	// AddKey adds the public key to the account and returns the account key added as well as the ID of the transaction.
+	//
+	// If the key weight is not specified the key is added with the full weight.
+	AddKey(context.Context, *accounts.Account, accounts.PublicKey) (*flow.AccountKey, flow.Identifier, error)
+
+	// RevokeKey revokes the key with the index on the account and returns the ID of the transaction.
+	//
+	// The key used for signing by the account can't be revoked, neither can a key whose revocation would leave
+	// the account keys with less than the full weight.
+	RevokeKey(context.Context, *accounts.Account, uint32) (flow.Identifier, error)
+
+	// RotateKey adds the public key of the new key to the account, switches the account to sign with the new key,
+	// and revokes the key previously used for signing. Returns the account key added as well as the ID of the
+	// transaction revoking the previous key.
+	//
+	// The account key is updated in the state if the account exists in it.
+	RotateKey(context.Context, *accounts.Account, accounts.Key, int) (*flow.AccountKey, flow.Identifier, error)
+
+	// AddProposalKeys adds count keys to the account for proposing transactions concurrently with a ProposalKeyPool,
+	// and returns the indexes of the added keys. The keys have the full weight and the public key of the account key.
+	AddProposalKeys(context.Context, *accounts.Account, int) ([]uint32, error)
+
 	// AddContract to the Flow account provided and return the transaction ID.
 	//
 	// If the contract already exists on the account the operation will fail and error will be returned.
@@ -58,11 +88,44 @@ type Services interface {
 	// define a custom UpdateContract function which returns bool indicating whether a contract should be updated or not.
 	AddContract(context.Context, *accounts.Account, Script, UpdateContract) (flow.Identifier, bool, error)
 
+	// AddContractAndTrack adds the contract like AddContract, but only waits until the transaction reaches the
+	// provided status. The callback is called with every status transition and can be nil.
+	AddContractAndTrack(context.Context, *accounts.Account, Script, UpdateContract, flow.TransactionStatus, TransactionStatusCallback) (flow.Identifier, bool, error)
+
 	// RemoveContract from the provided account by its name.
 	//
 	// If removal is successful transaction ID is returned.
 	RemoveContract(context.Context, *accounts.Account, string) (flow.Identifier, error)
 
+	// RemoveContractAndTrack removes the contract like RemoveContract, but only waits until the transaction reaches
+	// the provided status. The callback is called with every status transition and can be nil.
+	RemoveContractAndTrack(context.Context, *accounts.Account, string, flow.TransactionStatus, TransactionStatusCallback) (flow.Identifier, error)
+
+	// ContractDependents returns the contracts depending on the contract deployed to the address, directly or
+	// transitively, in the order they must be removed.
+	//
+	// Dependents are found in the project deployments on the network, and if onChain is set also in the code of the
+	// contracts deployed to the configured accounts.
+	ContractDependents(context.Context, flow.Address, string, bool) ([]DeployedContract, error)
+
+	// RemoveContractWithDependents removes the contract from the account and handles the contracts depending on it
+	// based on the policy, the removed contracts are returned in the order they were removed.
+	//
+	// Dependents are found the same way as in ContractDependents. Cascading removes the dependents deployed to the
+	// configured accounts before the contract, and fails without removing anything if any dependent account isn't configured.
+	RemoveContractWithDependents(context.Context, *accounts.Account, string, DependentsPolicy, bool) ([]DeployedContract, error)
+
+	// PrunableContracts returns the contracts deployed to the configured accounts on the network which are no longer
+	// in the project deployments.
+	PrunableContracts(context.Context) ([]DeployedContract, error)
+
+	// PruneContracts removes the contracts deployed to the configured accounts on the network which are no longer in the
+	// project deployments, and handles the contracts depending on them based on the policy.
+	//
+	// All the contracts not in the deployments are removed, including the ones deployed by other means, use
+	// PrunableContracts to review them first. The removed contracts are returned in the order they were removed.
+	PruneContracts(context.Context, DependentsPolicy) ([]DeployedContract, error)
+
 	// GetBlock by the query from Flow blockchain. Query can define a block by ID, block by height or require the latest block.
 	GetBlock(context.Context, BlockQuery) (*flow.Block, error)
 
@@ -77,6 +140,25 @@ type Services interface {
 	// if not provided only a single worker will be used.
 	GetEvents(context.Context, []string, uint64, uint64, *EventWorker) ([]flow.BlockEvents, error)
 
+	// SubscribeBlocks streams the sealed blocks starting at the provided height, or at the latest sealed block if the height is zero.
+	//
+	// The subscription runs until the context is cancelled or an error is sent to the error channel, after which both channels are closed.
+	SubscribeBlocks(context.Context, uint64) (<-chan *flow.Block, <-chan error, error)
+
+	// SubscribeBlockHeaders streams the sealed block headers starting at the provided height, or at the latest sealed block if the height is zero.
+	SubscribeBlockHeaders(context.Context, uint64) (<-chan *flow.BlockHeader, <-chan error, error)
+
+	// SubscribeEvents streams the events matching the filter by event type, address or contract starting at the provided height,
+	// or at the latest sealed block if the height is zero.
+	//
+	// Networks that can't stream are polled for new blocks instead, in that case filtering by address or contract fetches
+	// all the events of every block and the filter can't be empty.
+	SubscribeEvents(context.Context, uint64, flow.EventFilter) (<-chan flow.BlockEvents, <-chan error, error)
+
+	// SubscribeAccountStatuses streams the core account events grouped by account starting at the provided height,
+	// or at the latest sealed block if the height is zero. The filter can limit the event types and the accounts.
+	SubscribeAccountStatuses(context.Context, uint64, flow.AccountStatusFilter) (<-chan *flow.AccountStatus, <-chan error, error)
+
 	// GenerateKey using the signature algorithm and optional seed. If seed is not provided a random safe seed will be generated.
 	GenerateKey(context.Context, crypto.SignatureAlgorithm, string) (crypto.PrivateKey, error)
 
@@ -92,12 +174,57 @@ type Services interface {
 	// Retrieve all the contracts for specified network, sort them for deployment deploy one by one and replace
 	// the imports in the contract source, so it corresponds to the account name the contract was deployed to.
 	// If contracts already exist use UpdateExistingContract(bool) to define whether a contract should be updated or not.
+	//
+	// If batch deployments are set the contracts are deployed by executing a deployment plan, so the contracts
+	// deployed to the same account in a dependency level are sent with a single transaction.
 	DeployProject(context.Context, UpdateContract) ([]*project.Contract, error)
 
+	// DeployProjectParallel deploys the project contracts like DeployProject, but deploys the contracts in the same
+	// dependency level concurrently and waits for the whole level to be sealed before deploying the next level.
+	//
+	// Contracts on different accounts are deployed at the same time, while contracts on the same account are deployed
+	// one after another, unless the account is proposing with the proposal key pool set on flowkit, in which case
+	// they are deployed concurrently with different proposal keys.
+	DeployProjectParallel(context.Context, UpdateContract) ([]*project.Contract, error)
+
+	// DeployProjectWithJournal plans and deploys the project contracts like ExecuteDeploymentPlan, and records
+	// the progress of the deployment in the journal file at the path.
+	//
+	// If the journal file exists and the deployment recorded in it isn't finished, that deployment is resumed
+	// like with ResumeDeployment instead of planning a new one, so an interrupted deployment is safe to run again.
+	DeployProjectWithJournal(context.Context, UpdateContract, string) (*DeploymentJournal, error)
+
+	// ResumeDeployment continues the deployment recorded in the journal file at the path.
+	//
+	// The journal is first reconciled with the network: the results of sent transactions are fetched, and contracts
+	// found on the account with the planned code are marked as deployed. Only the remaining contracts are deployed,
+	// and if any of them changed on the network since the deployment was planned a DeploymentDriftError is returned.
+	ResumeDeployment(context.Context, string) (*DeploymentJournal, error)
+
+	// PlanDeployment creates the plan for deploying the project contracts to the network without sending any transactions.
+	//
+	// Contracts are sorted in the deployment order and their imports are resolved the same way as in DeployProject.
+	// Contracts that exist on the account with different code are planned for update only if UpdateContract returns true,
+	// otherwise an error for the contract is returned as part of the ProjectDeploymentError.
+	PlanDeployment(context.Context, UpdateContract) (*DeploymentPlan, error)
+
+	// ExecuteDeploymentPlan deploys the contracts exactly as planned and returns the IDs of the sent transactions
+	// by the contract name.
+	//
+	// Before sending any transaction the contracts on the network are compared to the state they were in when
+	// the plan was created, and if any of them changed a DeploymentDriftError is returned.
+	ExecuteDeploymentPlan(context.Context, *DeploymentPlan) (map[string]flow.Identifier, error)
+
 	// ExecuteScript on the Flow network and return the Cadence value as a result. The script is executed at the
 	// block provided as part of the ScriptQuery value.
 	ExecuteScript(context.Context, Script, ScriptQuery) (cadence.Value, error)
 
+	// ExecuteScripts on the Flow network concurrently and return the results in the order of the scripts.
+	//
+	// Imports are resolved once per unique script source and the failure of a script is returned as part of
+	// its result. Use ScriptBatch to define the number of workers and to pin all the scripts to the same block height.
+	ExecuteScripts(context.Context, []BatchScript, *ScriptBatch) ([]BatchScriptResult, error)
+
 	// GetTransactionByID from the Flow network including the transaction result. Using the waitSeal we can wait for the transaction to be sealed.
 	GetTransactionByID(context.Context, flow.Identifier, bool) (*flow.Transaction, *flow.TransactionResult, error)
 
@@ -110,6 +237,12 @@ type Services interface {
 	// GetSystemTransactionWithID returns the system transaction by ID; if ID is empty, returns the last in the block. Returns transaction and result.
 	GetSystemTransactionWithID(context.Context, flow.Identifier, flow.Identifier) (*flow.Transaction, *flow.TransactionResult, error)
 
+	// GetExecutionResult returns the execution result of the block with its chunks.
+	GetExecutionResult(context.Context, flow.Identifier) (*ExecutionResult, error)
+
+	// GetExecutionData returns the transactions, results, events and register updates of every chunk of the block.
+	GetExecutionData(context.Context, flow.Identifier) (*ExecutionData, error)
+
 	// BuildTransaction builds a new transaction type for later signing and submitting to the network.
 	//
 	// AddressesRoles type defines the address for each role (payer, proposer, authorizers) and the script defines the transaction content.
@@ -127,8 +260,43 @@ type Services interface {
 
 	// SendTransaction will build and send a transaction to the Flow network, using the accounts provided for each role and
 	// contain the script. Transaction as well as transaction result will be returned in case the transaction is successfully submitted.
+	//
+	// If the gas limit is zero the compute limit is estimated by executing the transaction against an emulator first.
+	// If the proposer is the account of the proposal key pool, the transaction is proposed with a key leased from the pool.
 	SendTransaction(context.Context, transactions.AccountRoles, Script, uint64) (*flow.Transaction, *flow.TransactionResult, error)
 
+	// SendTransactionAsync builds and sends the transaction like SendTransaction, but returns right after the transaction
+	// is sent with a handle to wait for it.
+	SendTransactionAsync(context.Context, transactions.AccountRoles, Script, uint64) (*TransactionHandle, error)
+
+	// SendSignedTransactionAsync sends the prebuilt and signed transaction like SendSignedTransaction, but returns
+	// right after the transaction is sent with a handle to wait for it.
+	SendSignedTransactionAsync(context.Context, *transactions.Transaction) (*TransactionHandle, error)
+
+	// SendTransactionAndTrack builds and sends a transaction like SendTransaction, but only waits until the transaction
+	// reaches the provided status. The callback is called with every status transition.
+	SendTransactionAndTrack(
+		context.Context,
+		transactions.AccountRoles,
+		Script,
+		uint64,
+		flow.TransactionStatus,
+		TransactionStatusCallback,
+	) (*flow.Transaction, *flow.TransactionResult, error)
+
+	// TrackTransaction follows the transaction status until it reaches the provided status, calling the callback
+	// with every status transition, and returns the transaction result.
+	TrackTransaction(context.Context, flow.Identifier, flow.TransactionStatus, TransactionStatusCallback) (*flow.TransactionResult, error)
+
+	// SimulateTransaction builds the transaction like SendTransaction and executes it against an in-process emulator
+	// forked from the network state, or against a local emulator with the project accounts and contracts deployed
+	// if forking isn't possible. Nothing is sent to the network.
+	SimulateTransaction(context.Context, transactions.AccountRoles, Script, uint64) (*TransactionSimulation, error)
+
+	// EstimateTransaction simulates the transaction like SimulateTransaction to measure its computation and effort,
+	// and returns the suggested compute limit with the safety margin added and the expected FLOW fee.
+	EstimateTransaction(context.Context, transactions.AccountRoles, Script, float64) (*TransactionEstimate, error)
+
 	// ReplaceImportsInScript will replace the imports in the script code with the contracts from the network.
 	ReplaceImportsInScript(context.Context, Script) (Script, error)
 }
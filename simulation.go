@@ -0,0 +1,388 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/flow-emulator/emulator"
+	"github.com/onflow/flow-go-sdk"
+	flowGo "github.com/onflow/flow-go/model/flow"
+
+	"github.com/onflow/flowkit/v2/accounts"
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/gateway"
+	"github.com/onflow/flowkit/v2/output"
+	"github.com/onflow/flowkit/v2/transactions"
+)
+
+// storageUsedScript returns the storage used by each of the accounts in bytes.
+const storageUsedScript = `
+access(all) fun main(addresses: [Address]): [UInt64] {
+	let used: [UInt64] = []
+	for address in addresses {
+		used.append(getAccount(address).storage.used)
+	}
+	return used
+}`
+
+// TransactionSimulation is the outcome of a transaction executed against a copy of the network state,
+// the transaction is never sent to the network.
+type TransactionSimulation struct {
+	// Forked is true if the transaction was executed against the state of the network, forked from the network
+	// or copied from the emulator of the flowkit gateway, otherwise it was executed against a local emulator
+	// with the project contracts deployed.
+	Forked          bool
+	Transaction     *flow.Transaction
+	Events          []flow.Event
+	Error           error
+	ComputationUsed uint64
+	StorageChanges  []StorageChange
+}
+
+// StorageChange is the change of the storage used by an account in bytes.
+type StorageChange struct {
+	Address flow.Address
+	Before  uint64
+	After   uint64
+}
+
+// SimulateTransaction builds the transaction the same way SendTransaction does and executes it against an in-process
+// emulator instead of sending it to the network.
+//
+// If the flowkit gateway is an emulator gateway, the transaction is executed against a copy of the committed emulator
+// state, so the emulator itself is never changed and the transactions in its pending block aren't part of the
+// simulation. Otherwise the emulator is forked from the state of the network defined by the network fork, or of the
+// network itself if it doesn't fork another network. If forking isn't possible the transaction is executed against
+// a local emulator with the project emulator accounts created and the project contracts deployed to it, in which
+// case the imports are resolved for the emulator network. Signatures and sequence numbers are only checked by the
+// simulation when it uses the emulator gateway.
+func (f *Flowkit) SimulateTransaction(
+	ctx context.Context,
+	accounts transactions.AccountRoles,
+	script Script,
+	gasLimit uint64,
+) (*TransactionSimulation, error) {
+	f.logger.StartProgress("Simulating transaction...")
+	defer f.logger.StopProgress()
+
+	simulation, _, discard, err := f.simulate(ctx, accounts.AddressRoles(), func(sim *Flowkit) (*transactions.Transaction, error) {
+		return sim.signedTransaction(ctx, accounts, script, gasLimit, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := discard(); err != nil {
+		return nil, err
+	}
+
+	return simulation, nil
+}
+
+// simulate executes the transaction built by the simulation flowkit against the simulation emulator, and returns
+// the simulation emulator gateway with the function discarding it, which must be called by the caller.
+func (f *Flowkit) simulate(
+	ctx context.Context,
+	roles transactions.AddressesRoles,
+	build func(sim *Flowkit) (*transactions.Transaction, error),
+) (*TransactionSimulation, *gateway.EmulatorGateway, func() error, error) {
+	sim, gw, discard, forked, err := f.newSimulation(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	simulation, err := executeSimulation(ctx, sim, gw, roles, build)
+	if err != nil {
+		_ = discard()
+		return nil, nil, nil, err
+	}
+	simulation.Forked = forked
+
+	return simulation, gw, discard, nil
+}
+
+// executeSimulation sends the transaction to the simulation emulator and executes it, measuring the storage used
+// by the accounts in the transaction roles and events before and after the transaction.
+func executeSimulation(
+	ctx context.Context,
+	sim *Flowkit,
+	gw *gateway.EmulatorGateway,
+	roles transactions.AddressesRoles,
+	build func(sim *Flowkit) (*transactions.Transaction, error),
+) (*TransactionSimulation, error) {
+	tx, err := build(sim)
+	if err != nil {
+		return nil, err
+	}
+
+	sentTx, err := gw.SendSignedTransaction(ctx, tx.FlowTransaction())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gw.ExecuteNextTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute the transaction: %w", err)
+	}
+	if result.TransactionID != sentTx.ID() {
+		return nil, fmt.Errorf("executed transaction %s instead of the simulated transaction %s", result.TransactionID, sentTx.ID())
+	}
+
+	addresses := append([]flow.Address{roles.Proposer, roles.Payer}, roles.Authorizers...)
+	for _, event := range result.Events {
+		addresses = append(addresses, eventAddresses(event)...)
+	}
+	slices.SortFunc(addresses, func(a, b flow.Address) int {
+		return slices.Compare(a.Bytes(), b.Bytes())
+	})
+	addresses = slices.Compact(addresses)
+
+	// accounts created by the transaction used no storage before it
+	var existing []flow.Address
+	events := EventsFromTransaction(result)
+	created := events.GetCreatedAddresses()
+	for _, address := range addresses {
+		if !slices.ContainsFunc(created, func(c *flow.Address) bool { return *c == address }) {
+			existing = append(existing, address)
+		}
+	}
+
+	// scripts read the committed state, so until the block is committed they don't see the transaction changes
+	used, err := storageUsed(ctx, gw, existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the storage used before the transaction: %w", err)
+	}
+	before := make([]uint64, len(addresses))
+	for i, address := range addresses {
+		if j := slices.Index(existing, address); j >= 0 {
+			before[i] = used[j]
+		}
+	}
+
+	if _, err := gw.CommitBlock(); err != nil {
+		return nil, err
+	}
+
+	after, err := storageUsed(ctx, gw, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the storage used after the transaction: %w", err)
+	}
+
+	var changes []StorageChange
+	for i, address := range addresses {
+		if before[i] != after[i] {
+			changes = append(changes, StorageChange{Address: address, Before: before[i], After: after[i]})
+		}
+	}
+
+	return &TransactionSimulation{
+		Transaction:     sentTx,
+		Events:          result.Events,
+		Error:           result.Error,
+		ComputationUsed: result.ComputationUsage,
+		StorageChanges:  changes,
+	}, nil
+}
+
+// newSimulation returns the flowkit and the emulator gateway executing the simulated transaction, the function
+// discarding the emulator, and whether the emulator has the state of the network.
+func (f *Flowkit) newSimulation(ctx context.Context) (*Flowkit, *gateway.EmulatorGateway, func() error, bool, error) {
+	state, err := f.State()
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	logger := output.NewStdoutLogger(output.NoneLog)
+
+	// the emulator of the gateway already has the state, so a copy of it is used instead of a new emulator
+	if gw, ok := gateway.As[*gateway.EmulatorGateway](f.gateway); ok {
+		sim, err := gw.Copy(gateway.WithManualMining())
+		if err != nil {
+			return nil, nil, nil, false, fmt.Errorf("failed to copy the emulator state for the simulation: %w", err)
+		}
+		return NewFlowkit(state, f.network, sim, logger), sim, sim.Close, true, nil
+	}
+
+	opts := []func(*gateway.EmulatorGateway){
+		gateway.WithManualMining(),
+		gateway.WithEmulatorOptions(emulator.WithTransactionValidationEnabled(false)),
+	}
+
+	source := f.network
+	if f.network.Fork != "" {
+		if network, err := state.Networks().ByName(f.network.Fork); err == nil {
+			source = *network
+		}
+	}
+
+	gw, err := gateway.NewForkedEmulatorGateway(source, opts...)
+	if err == nil {
+		return NewFlowkit(state, f.network, gw, logger), gw, gw.Close, true, nil
+	}
+	f.logger.Debug(fmt.Sprintf("Simulating with a local emulator, forking isn't possible: %s", err))
+
+	serviceAccount, err := state.EmulatorServiceAccount()
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	privateKey, err := serviceAccount.Key.PrivateKey()
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	// the chain settings of the project emulator are used, but never its storage so the simulation leaves no trace
+	conf := config.Emulator{}
+	if e := state.Config().Emulators.Default(); e != nil {
+		conf = *e
+	}
+	conf.Storage = config.EmulatorMemoryStorage
+
+	gw, err = gateway.NewEmulatorGatewayFromConfig(&gateway.EmulatorKey{
+		PublicKey: (*privateKey).PublicKey(),
+		SigAlgo:   serviceAccount.Key.SigAlgo(),
+		HashAlgo:  serviceAccount.Key.HashAlgo(),
+	}, conf, opts...)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	sim := NewFlowkit(state, config.EmulatorNetwork, gw, logger)
+	if err := createEmulatorAccounts(ctx, sim, gw, serviceAccount); err != nil {
+		_ = gw.Close()
+		return nil, nil, nil, false, fmt.Errorf("failed to create the project accounts on the simulation emulator: %w", err)
+	}
+	if _, err := sim.DeployProject(ctx, UpdateExistingContract(false)); err != nil {
+		_ = gw.Close()
+		return nil, nil, nil, false, fmt.Errorf("failed to deploy the project contracts to the simulation emulator: %w", err)
+	}
+
+	return sim, gw, gw.Close, false, nil
+}
+
+// createEmulatorAccounts creates the accounts the project deploys to on the emulator network, with their configured
+// keys, so the project can be deployed to a new emulator.
+//
+// The emulator derives the address of a new account from the number of accounts it created, so the accounts are
+// created in that order, and accounts are created until the address of the next configured account is reached.
+func createEmulatorAccounts(
+	ctx context.Context,
+	sim *Flowkit,
+	gw *gateway.EmulatorGateway,
+	serviceAccount *accounts.Account,
+) error {
+	state, err := sim.State()
+	if err != nil {
+		return err
+	}
+
+	type emulatorAccount struct {
+		account accounts.Account
+		index   uint64
+	}
+
+	chain := flowGo.ChainID(gw.ChainID()).Chain()
+	var accs []emulatorAccount
+	for _, account := range *state.AccountsForNetwork(config.EmulatorNetwork) {
+		if account.Address == serviceAccount.Address {
+			continue
+		}
+		index, err := chain.IndexFromAddress(flowGo.Address(account.Address))
+		if err != nil {
+			return fmt.Errorf("address %s of account %s is not an emulator address: %w", account.Address, account.Name, err)
+		}
+		accs = append(accs, emulatorAccount{account: account, index: index})
+	}
+	slices.SortFunc(accs, func(a, b emulatorAccount) int {
+		return cmp.Compare(a.index, b.index)
+	})
+
+	for _, acc := range accs {
+		privateKey, err := acc.account.Key.PrivateKey()
+		if err != nil {
+			return fmt.Errorf("key of account %s can't be used on the simulation emulator: %w", acc.account.Name, err)
+		}
+		keys := []accounts.PublicKey{{
+			Public:   (*privateKey).PublicKey(),
+			SigAlgo:  acc.account.Key.SigAlgo(),
+			HashAlgo: acc.account.Key.HashAlgo(),
+		}}
+
+		// the accounts created before the address is reached are left unused
+		for {
+			created, _, err := sim.CreateAccount(ctx, serviceAccount, keys)
+			if err != nil {
+				return err
+			}
+			index, err := chain.IndexFromAddress(flowGo.Address(created.Address))
+			if err != nil {
+				return err
+			}
+			if index == acc.index {
+				break
+			}
+			if index > acc.index {
+				return fmt.Errorf("address %s of account %s is already used by the emulator", acc.account.Address, acc.account.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// eventAddresses returns the addresses in the event fields.
+func eventAddresses(event flow.Event) []flow.Address {
+	var addresses []flow.Address
+	for _, value := range event.Value.FieldsMappedByName() {
+		if optional, ok := value.(cadence.Optional); ok {
+			value = optional.Value
+		}
+		if address, ok := value.(cadence.Address); ok {
+			addresses = append(addresses, flow.Address(address))
+		}
+	}
+	return addresses
+}
+
+// storageUsed returns the storage used by each of the accounts in bytes.
+func storageUsed(ctx context.Context, gw gateway.Gateway, addresses []flow.Address) ([]uint64, error) {
+	values := make([]cadence.Value, len(addresses))
+	for i, address := range addresses {
+		values[i] = cadence.NewAddress(address)
+	}
+
+	value, err := gw.ExecuteScript(ctx, []byte(storageUsedScript), []cadence.Value{cadence.NewArray(values)})
+	if err != nil {
+		return nil, err
+	}
+
+	array, ok := value.(cadence.Array)
+	if !ok || len(array.Values) != len(addresses) {
+		return nil, fmt.Errorf("unexpected storage used result %s", value)
+	}
+
+	used := make([]uint64, len(addresses))
+	for i, v := range array.Values {
+		used[i] = uint64(v.(cadence.UInt64))
+	}
+	return used, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+
+	"github.com/onflow/flowkit/v2/gateway"
+)
+
+// transactionPollInterval is how often the transaction status is checked while tracking a transaction.
+var transactionPollInterval = time.Second
+
+// TransactionStatusUpdate is a transaction status transition observed while tracking the transaction.
+type TransactionStatusUpdate struct {
+	TransactionID flow.Identifier
+	Status        flow.TransactionStatus
+	// Timestamp is the time the status was observed.
+	Timestamp time.Time
+	// Result is the transaction result at the status, it contains the events and the error once the transaction is executed.
+	Result *flow.TransactionResult
+}
+
+// TransactionStatusCallback is called with every transaction status transition.
+type TransactionStatusCallback func(TransactionStatusUpdate)
+
+// TrackTransaction follows the transaction status until it reaches the provided status, the callback is called
+// with every status transition and can be nil. The status is polled, so transitions happening between two polls
+// are reported as a single transition to the latest status.
+//
+// The latest transaction result is returned once the status is reached, if the transaction expires an error is returned.
+func (f *Flowkit) TrackTransaction(
+	ctx context.Context,
+	ID flow.Identifier,
+	until flow.TransactionStatus,
+	callback TransactionStatusCallback,
+) (*flow.TransactionResult, error) {
+	if until < flow.TransactionStatusPending || until > flow.TransactionStatusSealed {
+		return nil, fmt.Errorf("can not track transaction until status %s", until)
+	}
+
+	status := flow.TransactionStatusUnknown
+	for {
+		result, err := f.gateway.GetTransactionResult(ctx, ID, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Status != status {
+			status = result.Status
+			if callback != nil {
+				callback(TransactionStatusUpdate{
+					TransactionID: ID,
+					Status:        status,
+					Timestamp:     time.Now(),
+					Result:        result,
+				})
+			}
+		}
+
+		if status == flow.TransactionStatusExpired {
+			return result, fmt.Errorf("transaction %s expired", ID)
+		}
+		if status >= until {
+			return result, nil
+		}
+
+		// the pending transactions are only executed once the block is committed
+		if miner, ok := gateway.As[gateway.ManualMiner](f.gateway); ok && miner.ManualMining() && status == flow.TransactionStatusPending {
+			if _, err := miner.CommitBlock(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(transactionPollInterval):
+		}
+	}
+}
+
+// transactionTracking defines the status a transaction is tracked until, and the callback called with every
+// status transition which can be nil.
+type transactionTracking struct {
+	until    flow.TransactionStatus
+	callback TransactionStatusCallback
+}
+
+// untilSealed tracks the transaction until it's sealed.
+var untilSealed = transactionTracking{until: flow.TransactionStatusSealed}
+
+// waitForSeal tracks the transaction until it's sealed and shows its status as the logger progress.
+func (f *Flowkit) waitForSeal(ctx context.Context, ID flow.Identifier) (*flow.TransactionResult, error) {
+	return f.waitFor(ctx, ID, untilSealed)
+}
+
+// waitFor tracks the transaction as defined by the tracking and shows its status as the logger progress.
+func (f *Flowkit) waitFor(ctx context.Context, ID flow.Identifier, tracking transactionTracking) (*flow.TransactionResult, error) {
+	defer f.logger.StopProgress()
+	return f.TrackTransaction(ctx, ID, tracking.until, func(update TransactionStatusUpdate) {
+		f.logTransactionStatus(update)
+		if tracking.callback != nil {
+			tracking.callback(update)
+		}
+	})
+}
+
+func (f *Flowkit) logTransactionStatus(update TransactionStatusUpdate) {
+	switch update.Status {
+	case flow.TransactionStatusPending:
+		f.logger.StartProgress("Transaction pending, waiting for it to be finalized...")
+	case flow.TransactionStatusFinalized:
+		f.logger.StartProgress("Transaction finalized, waiting for it to be executed...")
+	case flow.TransactionStatusExecuted:
+		f.logger.StartProgress("Transaction executed, waiting for it to be sealed...")
+	case flow.TransactionStatusSealed:
+		f.logger.StopProgress()
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ * Flow CLI
+ *
+ * Copyright 2019 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flowkit/v2/config"
+	"github.com/onflow/flowkit/v2/gateway/mocks"
+	"github.com/onflow/flowkit/v2/output"
+)
+
+func TestTrackTransaction(t *testing.T) {
+	interval := transactionPollInterval
+	transactionPollInterval = time.Millisecond
+	t.Cleanup(func() { transactionPollInterval = interval })
+
+	ctx := context.Background()
+	ID := flow.HexToID("01")
+
+	setupTracking := func(t *testing.T, statuses ...flow.TransactionStatus) Flowkit {
+		g := mocks.NewGateway(t)
+		for _, status := range statuses {
+			g.On("GetTransactionResult", ctx, ID, false).
+				Return(&flow.TransactionResult{TransactionID: ID, Status: status}, nil).
+				Once()
+		}
+
+		return Flowkit{
+			network: config.TestnetNetwork,
+			gateway: g,
+			logger:  output.NewStdoutLogger(output.NoneLog),
+		}
+	}
+
+	collect := func(updates *[]flow.TransactionStatus) TransactionStatusCallback {
+		return func(update TransactionStatusUpdate) {
+			assert.Equal(t, ID, update.TransactionID)
+			assert.False(t, update.Timestamp.IsZero())
+			*updates = append(*updates, update.Status)
+		}
+	}
+
+	t.Run("Track until sealed", func(t *testing.T) {
+		flowkit := setupTracking(t,
+			flow.TransactionStatusUnknown,
+			flow.TransactionStatusPending,
+			flow.TransactionStatusPending,
+			flow.TransactionStatusFinalized,
+			flow.TransactionStatusExecuted,
+			flow.TransactionStatusSealed,
+		)
+
+		var updates []flow.TransactionStatus
+		result, err := flowkit.TrackTransaction(ctx, ID, flow.TransactionStatusSealed, collect(&updates))
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusSealed, result.Status)
+		assert.Equal(t, []flow.TransactionStatus{
+			flow.TransactionStatusPending,
+			flow.TransactionStatusFinalized,
+			flow.TransactionStatusExecuted,
+			flow.TransactionStatusSealed,
+		}, updates)
+	})
+
+	t.Run("Stop once executed", func(t *testing.T) {
+		flowkit := setupTracking(t, flow.TransactionStatusFinalized, flow.TransactionStatusExecuted)
+
+		var updates []flow.TransactionStatus
+		result, err := flowkit.TrackTransaction(ctx, ID, flow.TransactionStatusExecuted, collect(&updates))
+		require.NoError(t, err)
+		assert.Equal(t, flow.TransactionStatusExecuted, result.Status)
+		assert.Equal(t, []flow.TransactionStatus{flow.TransactionStatusFinalized, flow.TransactionStatusExecuted}, updates)
+	})
+
+	t.Run("Fail expired", func(t *testing.T) {
+		flowkit := setupTracking(t, flow.TransactionStatusPending, flow.TransactionStatusExpired)
+
+		var updates []flow.TransactionStatus
+		result, err := flowkit.TrackTransaction(ctx, ID, flow.TransactionStatusSealed, collect(&updates))
+		assert.EqualError(t, err, "transaction 0100000000000000000000000000000000000000000000000000000000000000 expired")
+		assert.Equal(t, flow.TransactionStatusExpired, result.Status)
+		assert.Equal(t, []flow.TransactionStatus{flow.TransactionStatusPending, flow.TransactionStatusExpired}, updates)
+	})
+
+	t.Run("Fail invalid status", func(t *testing.T) {
+		flowkit := setupTracking(t)
+
+		_, err := flowkit.TrackTransaction(ctx, ID, flow.TransactionStatusExpired, nil)
+		assert.EqualError(t, err, "can not track transaction until status EXPIRED")
+	})
+
+	t.Run("Stop on cancel", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		g := mocks.NewGateway(t)
+		g.On("GetTransactionResult", cancelCtx, ID, false).
+			Return(&flow.TransactionResult{TransactionID: ID, Status: flow.TransactionStatusPending}, nil)
+		flowkit := Flowkit{gateway: g, logger: output.NewStdoutLogger(output.NoneLog)}
+
+		_, err := flowkit.TrackTransaction(cancelCtx, ID, flow.TransactionStatusSealed, func(TransactionStatusUpdate) {
+			cancel()
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Stop progress when waiting fails", func(t *testing.T) {
+		flowkit := setupTracking(t, flow.TransactionStatusPending, flow.TransactionStatusExpired)
+		logger := &progressLogger{Logger: output.NewStdoutLogger(output.NoneLog)}
+		flowkit.logger = logger
+
+		_, err := flowkit.waitForSeal(ctx, ID)
+		assert.ErrorContains(t, err, "expired")
+		assert.Equal(t, 1, logger.started)
+		assert.False(t, logger.inProgress)
+	})
+}
+
+// progressLogger records whether the logger progress is shown.
+type progressLogger struct {
+	output.Logger
+	started    int
+	inProgress bool
+}
+
+func (l *progressLogger) StartProgress(string) {
+	l.started++
+	l.inProgress = true
+}
+
+func (l *progressLogger) StopProgress() {
+	l.inProgress = false
+}
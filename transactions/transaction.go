@@ -22,6 +22,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"strings"
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/ast"
@@ -93,6 +94,52 @@ func NewRemoveAccountContract(signer *accounts.Account, name string) (*Transacti
 	)
 }
 
+// NewAddAccountKey creates new transaction to add the key to the account.
+func NewAddAccountKey(signer *accounts.Account, key *flow.AccountKey) (*Transaction, error) {
+	template, err := templates.AddAccountKey(signer.Address, key)
+	if err != nil {
+		return nil, err
+	}
+	return newFromTemplate(template, signer)
+}
+
+// NewAddAccountKeys creates new transaction to add the key to the account count times, each copy with a new index.
+func NewAddAccountKeys(signer *accounts.Account, key *flow.AccountKey, count int) (*Transaction, error) {
+	const addAccountKeysTemplate = `
+	import Crypto
+
+	transaction(key: Crypto.KeyListEntry, count: Int) {
+		prepare(signer: auth(AddKey) &Account) {
+			var i = 0
+			while i < count {
+				signer.keys.add(publicKey: key.publicKey, hashAlgorithm: key.hashAlgorithm, weight: key.weight)
+				i = i + 1
+			}
+		}
+	}`
+
+	cadenceKey, err := templates.AccountKeyToCadenceCryptoKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := flow.NewTransaction().
+		SetScript([]byte(addAccountKeysTemplate)).
+		AddRawArgument(jsoncdc.MustEncode(cadenceKey)).
+		AddRawArgument(jsoncdc.MustEncode(cadence.NewInt(count))).
+		AddAuthorizer(signer.Address)
+
+	return newFromTemplate(tx, signer)
+}
+
+// NewRevokeAccountKey creates new transaction to revoke the key with the index on the account.
+func NewRevokeAccountKey(signer *accounts.Account, index uint32) (*Transaction, error) {
+	return newFromTemplate(
+		templates.RemoveAccountKey(signer.Address, int(index)),
+		signer,
+	)
+}
+
 // addAccountContractWithArgs contains logic to build a transaction and include the contract code
 // as well as possible init arguments.
 func addAccountContractWithArgs(
@@ -122,50 +169,17 @@ func addAccountContractWithArgs(
 	}
 
 	// Determine the argument types for the contract init function
-	program, err := parser.ParseProgram(nil, []byte(contract.Source), parser.Config{})
+	paramTypes, err := contractInitParameterTypes(contract.Source, args)
 	if err != nil {
 		return nil, err
 	}
 
+	// here we itterate over all arguments and possibly extend the transaction input argument
+	// in the above template to include them
 	txArgs, addArgs := "", ""
-	interfaceAst := program.SoleContractInterfaceDeclaration()
-	if interfaceAst == nil {
-		// get contract init function
-		contractAst := program.SoleContractDeclaration()
-		if contractAst == nil {
-			return nil, fmt.Errorf("failed to find contract declaration")
-		}
-
-		// get contract init function
-		specialFunctions := contractAst.Members.SpecialFunctions()
-		var initFunction *ast.SpecialFunctionDeclaration
-		for _, specialFunction := range specialFunctions {
-			if specialFunction.FunctionDeclaration.Identifier.Identifier == "init" {
-				initFunction = specialFunction
-				break
-			}
-		}
-
-		// if init function is not found, return error
-		contractInitArgs := make([]*ast.Parameter, 0)
-		if initFunction != nil {
-			contractInitArgs = initFunction.FunctionDeclaration.ParameterList.Parameters
-		}
-
-		// get contract init function arguments
-		if len(contractInitArgs) != len(args) {
-			return nil, fmt.Errorf(
-				"provided arguments length mismatch, required arguments %d, but provided %d",
-				len(contractInitArgs),
-				len(args),
-			)
-		}
-		// here we itterate over all arguments and possibly extend the transaction input argument
-		// in the above template to include them
-		for i, arg := range contractInitArgs {
-			txArgs += fmt.Sprintf(",arg%d:%s", i, arg.TypeAnnotation.Type.String())
-			addArgs += fmt.Sprintf(",arg%d", i)
-		}
+	for i, paramType := range paramTypes {
+		txArgs += fmt.Sprintf(",arg%d:%s", i, paramType)
+		addArgs += fmt.Sprintf(",arg%d", i)
 	}
 
 	script := fmt.Sprintf(addAccountContractTemplate, txArgs, addArgs)
@@ -182,6 +196,111 @@ func addAccountContractWithArgs(
 	return t, nil
 }
 
+// contractInitParameterTypes returns the types of the contract init function parameters, checking the provided
+// arguments match them. Contract interfaces have no init function, so no types are returned for them.
+func contractInitParameterTypes(source string, args []cadence.Value) ([]string, error) {
+	program, err := parser.ParseProgram(nil, []byte(source), parser.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	if program.SoleContractInterfaceDeclaration() != nil {
+		return nil, nil
+	}
+
+	// get contract init function
+	contractAst := program.SoleContractDeclaration()
+	if contractAst == nil {
+		return nil, fmt.Errorf("failed to find contract declaration")
+	}
+
+	// get contract init function
+	specialFunctions := contractAst.Members.SpecialFunctions()
+	var initFunction *ast.SpecialFunctionDeclaration
+	for _, specialFunction := range specialFunctions {
+		if specialFunction.FunctionDeclaration.Identifier.Identifier == "init" {
+			initFunction = specialFunction
+			break
+		}
+	}
+
+	// if init function is not found, return error
+	contractInitArgs := make([]*ast.Parameter, 0)
+	if initFunction != nil {
+		contractInitArgs = initFunction.FunctionDeclaration.ParameterList.Parameters
+	}
+
+	// get contract init function arguments
+	if len(contractInitArgs) != len(args) {
+		return nil, fmt.Errorf(
+			"provided arguments length mismatch, required arguments %d, but provided %d",
+			len(contractInitArgs),
+			len(args),
+		)
+	}
+
+	types := make([]string, len(contractInitArgs))
+	for i, arg := range contractInitArgs {
+		types[i] = arg.TypeAnnotation.Type.String()
+	}
+
+	return types, nil
+}
+
+// AccountContract is a contract added or updated on the account by NewDeployAccountContracts.
+type AccountContract struct {
+	Name   string
+	Source []byte
+	// Args are the init arguments used when the contract is added
+	Args   []cadence.Value
+	Update bool
+}
+
+// NewDeployAccountContracts creates new transaction to add or update all the contracts on the account at once,
+// so either all the contracts are deployed or the account stays unchanged. The contracts are deployed in order.
+func NewDeployAccountContracts(signer *accounts.Account, contracts []AccountContract) (*Transaction, error) {
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("no contracts to deploy")
+	}
+
+	var params, body strings.Builder
+	tx := flow.NewTransaction().AddAuthorizer(signer.Address)
+	for i, contract := range contracts {
+		if i > 0 {
+			params.WriteString(", ")
+		}
+		fmt.Fprintf(&params, "name%d: String, code%d: String", i, i)
+		tx.AddRawArgument(jsoncdc.MustEncode(cadence.String(contract.Name)))
+		tx.AddRawArgument(jsoncdc.MustEncode(cadence.String(contract.Source)))
+
+		if contract.Update {
+			fmt.Fprintf(&body, "\t\t\tsigner.contracts.update(name: name%d, code: code%d.utf8)\n", i, i)
+			continue
+		}
+
+		paramTypes, err := contractInitParameterTypes(string(contract.Source), contract.Args)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract %s: %w", contract.Name, err)
+		}
+
+		addArgs := ""
+		for j, paramType := range paramTypes {
+			fmt.Fprintf(&params, ", arg%d_%d: %s", i, j, paramType)
+			addArgs += fmt.Sprintf(", arg%d_%d", i, j)
+			tx.AddRawArgument(jsoncdc.MustEncode(contract.Args[j]))
+		}
+		fmt.Fprintf(&body, "\t\t\tsigner.contracts.add(name: name%d, code: code%d.utf8%s)\n", i, i, addArgs)
+	}
+
+	script := fmt.Sprintf(`
+	transaction(%s) {
+		prepare(signer: auth(AddContract, UpdateContract) &Account) {
+%s		}
+	}`, params.String(), body.String())
+
+	return newFromTemplate(tx.SetScript([]byte(script)), signer)
+}
+
 // NewCreateAccount creates new transaction for account.
 func NewCreateAccount(
 	signer *accounts.Account,
@@ -203,7 +322,7 @@ func newFromTemplate(templateTx *flow.Transaction, signer *accounts.Account) (*T
 		return nil, err
 	}
 	tx.SetPayer(signer.Address)
-	tx.SetComputeLimit(flow.DefaultTransactionGasLimit) // TODO(sideninja) change this to calculated limit
+	tx.SetComputeLimit(flow.DefaultTransactionGasLimit) // flowkit can replace it with an estimated limit
 
 	return tx, nil
 }
@@ -296,6 +415,12 @@ func (t *Transaction) SetProposer(proposer *flow.Account, keyIndex uint32) error
 	return nil
 }
 
+// SetProposalKey sets the proposal key for transaction without fetching the proposer account.
+func (t *Transaction) SetProposalKey(address flow.Address, keyIndex uint32, sequenceNumber uint64) *Transaction {
+	t.tx.SetProposalKey(address, keyIndex, sequenceNumber)
+	return t
+}
+
 // SetPayer sets the payer for transaction.
 func (t *Transaction) SetPayer(address flow.Address) *Transaction {
 	t.tx.SetPayer(address)
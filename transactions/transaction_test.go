@@ -86,3 +86,43 @@ func TestNew(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, signed.FlowTransaction().EnvelopeSignatures, 1)
 }
+
+func TestNewDeployAccountContracts(t *testing.T) {
+	rw, _ := tests.ReaderWriter()
+	signer, _ := accounts.NewEmulatorAccount(rw, crypto.ECDSA_P256, crypto.SHA3_256, "")
+
+	t.Run("Add and update contracts", func(t *testing.T) {
+		tx, err := transactions.NewDeployAccountContracts(signer, []transactions.AccountContract{{
+			Name:   tests.ContractSimpleWithArgs.Name,
+			Source: tests.ContractSimpleWithArgs.Source,
+			Args:   []cadence.Value{cadence.UInt64(4)},
+		}, {
+			Name:   tests.ContractHelloString.Name,
+			Source: tests.ContractHelloString.Source,
+			Update: true,
+		}})
+		assert.NoError(t, err)
+
+		script := string(tx.FlowTransaction().Script)
+		assert.Contains(t, script, "transaction(name0: String, code0: String, arg0_0: UInt64, name1: String, code1: String)")
+		assert.Contains(t, script, "prepare(signer: auth(AddContract, UpdateContract) &Account)")
+		assert.Contains(t, script, "signer.contracts.add(name: name0, code: code0.utf8, arg0_0)")
+		assert.Contains(t, script, "signer.contracts.update(name: name1, code: code1.utf8)")
+
+		assert.Len(t, tx.FlowTransaction().Arguments, 5)
+		assert.Equal(t, string(jsoncdc.MustEncode(cadence.UInt64(4))), string(tx.FlowTransaction().Arguments[2]))
+		assert.Equal(t, []flow.Address{signer.Address}, tx.FlowTransaction().Authorizers)
+		assert.Equal(t, signer.Address, tx.FlowTransaction().Payer)
+	})
+
+	t.Run("Fail on invalid arguments", func(t *testing.T) {
+		_, err := transactions.NewDeployAccountContracts(signer, []transactions.AccountContract{{
+			Name:   tests.ContractSimpleWithArgs.Name,
+			Source: tests.ContractSimpleWithArgs.Source,
+		}})
+		assert.EqualError(t, err, "invalid contract Simple: provided arguments length mismatch, required arguments 1, but provided 0")
+
+		_, err = transactions.NewDeployAccountContracts(signer, nil)
+		assert.EqualError(t, err, "no contracts to deploy")
+	})
+}